@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/cosmez/redisman-go/internal/command"
+	"github.com/cosmez/redisman-go/internal/conn"
+	"github.com/cosmez/redisman-go/internal/keyspace"
+	"github.com/cosmez/redisman-go/internal/output"
+	"github.com/fatih/color"
+)
+
+func handleAnalyze(c *conn.Connection, parsed *command.ParsedCommand) {
+	opts, asJSON, err := keyspace.ParseAnalyzeArgs(parsed.Args)
+	if err != nil {
+		color.Red("Usage: ANALYZE [pattern] [TOP n] [SAMPLE fraction] [LIMIT n] [JSON] (%v)", err)
+		return
+	}
+
+	opts.Progress = func(keys int) { fmt.Printf("\rScanned %d keys...", keys) }
+	report, err := keyspace.Analyze(c, opts)
+	fmt.Print("\r\033[K")
+	if err != nil {
+		color.Red("Analyze failed: %v", err)
+		return
+	}
+	printReport(parsed, report, asJSON)
+}
+
+func handlePrefixes(c *conn.Connection, parsed *command.ParsedCommand) {
+	opts, asJSON, err := keyspace.ParsePrefixArgs(parsed.Args)
+	if err != nil {
+		color.Red("Usage: PREFIXES [pattern] [DEPTH n] [DELIM delimiter] [JSON] (%v)", err)
+		return
+	}
+
+	opts.Progress = func(keys int) { fmt.Printf("\rScanned %d keys...", keys) }
+	report, err := keyspace.Prefixes(c, opts)
+	fmt.Print("\r\033[K")
+	if err != nil {
+		color.Red("Prefixes failed: %v", err)
+		return
+	}
+	printReport(parsed, report, asJSON)
+}
+
+// printReport writes a report to stdout or to the command's redirect target.
+func printReport(parsed *command.ParsedCommand, report interface{ WriteText(io.Writer) error }, asJSON bool) {
+	w := io.Writer(os.Stdout)
+	if parsed.Redirect != "" {
+		f, err := output.OpenRedirect(parsed.Redirect, parsed.Append)
+		if err != nil {
+			color.Red("Redirect error: %v", err)
+			return
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := writeReport(w, report, asJSON); err != nil {
+		color.Red("Write error: %v", err)
+	}
+}
+
+// writeReport writes a keyspace report as indented JSON or as text tables.
+func writeReport(w io.Writer, report interface{ WriteText(io.Writer) error }, asJSON bool) error {
+	if !asJSON {
+		return report.WriteText(w)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cosmez/redisman-go/internal/backup"
+	"github.com/cosmez/redisman-go/internal/command"
+	"github.com/cosmez/redisman-go/internal/conn"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+func handleBackup(c *conn.Connection, parsed *command.ParsedCommand) {
+	if len(parsed.Args) != 2 {
+		color.Red("Usage: BACKUP <pattern> <file>")
+		return
+	}
+
+	n, err := backup.BackupFile(c, parsed.Args[1], backup.BackupOptions{
+		Pattern:  parsed.Args[0],
+		Progress: func(keys int) { fmt.Printf("\rBacked up %d keys...", keys) },
+	})
+	fmt.Print("\r\033[K")
+	if err != nil {
+		color.Red("Backup failed: %v", err)
+		return
+	}
+	color.Green("Backed up %d keys to %s", n, parsed.Args[1])
+}
+
+func handleRestoreFile(c *conn.Connection, parsed *command.ParsedCommand) {
+	path, opts, err := backup.ParseRestoreArgs(parsed.Args)
+	if err != nil {
+		color.Red("Usage: RESTORE-FILE <file> [REPLACE] [SKIPEXISTING] [TTL keep|absolute|none] (%v)", err)
+		return
+	}
+
+	opts.Progress = func(keys int) { fmt.Printf("\rRestored %d keys...", keys) }
+	hdr, result, err := backup.RestoreFile(c, path, opts)
+	fmt.Print("\r\033[K")
+	if err != nil {
+		color.Red("Restore failed: %v", err)
+		return
+	}
+	printRestoreResult(hdr, result)
+}
+
+func handleExportKeys(c *conn.Connection, parsed *command.ParsedCommand) {
+	if len(parsed.Args) != 2 {
+		color.Red("Usage: EXPORT-KEYS <pattern> <file>")
+		return
+	}
+
+	n, err := backup.ExportKeysFile(c, parsed.Args[1], backup.ExportOptions{
+		Pattern:  parsed.Args[0],
+		Progress: func(keys int) { fmt.Printf("\rExported %d keys...", keys) },
+	})
+	fmt.Print("\r\033[K")
+	if err != nil {
+		color.Red("Export failed: %v", err)
+		return
+	}
+	color.Green("Exported %d keys to %s", n, parsed.Args[1])
+}
+
+func handleImportKeys(c *conn.Connection, parsed *command.ParsedCommand) {
+	path, opts, err := backup.ParseImportArgs(parsed.Args)
+	if err != nil {
+		color.Red("Usage: IMPORT-KEYS <file> [REPLACE] [SKIPEXISTING] [NOTTL] (%v)", err)
+		return
+	}
+
+	opts.Progress = func(keys int) { fmt.Printf("\rImported %d keys...", keys) }
+	result, err := backup.ImportKeysFile(c, path, opts)
+	fmt.Print("\r\033[K")
+	if err != nil {
+		color.Red("Import failed after %d keys: %v", result.Restored, err)
+		return
+	}
+	printResultCounts("Imported", result)
+}
+
+// printRestoreResult reports the outcome of a restore, listing the first
+// few per-key failures.
+func printRestoreResult(hdr backup.Header, result backup.RestoreResult) {
+	color.Cyan("Backup of %q from Redis %s (db %d) taken %s",
+		hdr.Pattern, hdr.ServerVersion, hdr.DB, hdr.Created.Local().Format("2006-01-02 15:04:05"))
+	printResultCounts("Restored", result)
+}
+
+// printResultCounts prints restore or import counts and the first failures.
+func printResultCounts(verb string, result backup.RestoreResult) {
+	color.Green("%s %d keys, skipped %d, failed %d", verb, result.Restored, result.Skipped, result.Failed)
+	for _, err := range result.Errors {
+		color.Red("  %v", err)
+	}
+	if result.Failed > len(result.Errors) {
+		color.Red("  ... and %d more", result.Failed-len(result.Errors))
+	}
+}
+
+// connectOrExit connects with the global connection flags, exiting on failure.
+func connectOrExit() *conn.Connection {
+	c, err := conn.Connect(host, port, username, password)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Connection failed: %v\n", err)
+		os.Exit(1)
+	}
+	return c
+}
+
+// newBackupCmd returns the `redisman backup <pattern> <file>` subcommand.
+func newBackupCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "backup <pattern> <file>",
+		Short: "Back up keys matching a pattern to a file using DUMP",
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			c := connectOrExit()
+			defer c.Close()
+
+			n, err := backup.BackupFile(c, args[1], backup.BackupOptions{Pattern: args[0]})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Backup failed: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("Backed up %d keys to %s\n", n, args[1])
+		},
+	}
+}
+
+// newRestoreCmd returns the `redisman restore <file>` subcommand.
+func newRestoreCmd() *cobra.Command {
+	var opts backup.RestoreOptions
+
+	cmd := &cobra.Command{
+		Use:   "restore <file>",
+		Short: "Restore keys from a backup file",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if !backup.ValidTTLMode(opts.TTLMode) {
+				fmt.Fprintf(os.Stderr, "Invalid --ttl %q: use keep, absolute or none\n", opts.TTLMode)
+				os.Exit(1)
+			}
+
+			c := connectOrExit()
+			defer c.Close()
+
+			_, result, err := backup.RestoreFile(c, args[0], opts)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Restore failed: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("Restored %d keys, skipped %d, failed %d\n", result.Restored, result.Skipped, result.Failed)
+			for _, err := range result.Errors {
+				fmt.Fprintf(os.Stderr, "  %v\n", err)
+			}
+			if result.Failed > 0 {
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.Replace, "replace", false, "Overwrite keys that already exist")
+	cmd.Flags().BoolVar(&opts.SkipExisting, "skip-existing", false, "Skip keys that already exist instead of failing")
+	cmd.Flags().StringVar(&opts.TTLMode, "ttl", backup.TTLKeep, "TTL handling: keep, absolute or none")
+	return cmd
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/cosmez/redisman-go/internal/command"
+	"github.com/cosmez/redisman-go/internal/conn"
+	"github.com/cosmez/redisman-go/internal/keyspace"
+	"github.com/cosmez/redisman-go/internal/output"
+	"github.com/fatih/color"
+)
+
+func handleDelPattern(c *conn.Connection, parsed *command.ParsedCommand) {
+	pattern, _, opts, err := keyspace.ParseArgs(parsed.Args, false)
+	if err != nil {
+		color.Red("Usage: DELPATTERN <pattern> [RATE n] (%v)", err)
+		return
+	}
+	runBulk(c, pattern, "Delete", opts, func(keys []string, opts keyspace.Options) (keyspace.Result, error) {
+		return keyspace.Delete(c, keys, opts)
+	})
+}
+
+func handleExpirePattern(c *conn.Connection, parsed *command.ParsedCommand) {
+	pattern, seconds, opts, err := keyspace.ParseArgs(parsed.Args, true)
+	if err != nil {
+		color.Red("Usage: EXPIREPATTERN <pattern> <seconds> [RATE n] (%v)", err)
+		return
+	}
+	runBulk(c, pattern, fmt.Sprintf("Set a %ds TTL on", seconds), opts, func(keys []string, opts keyspace.Options) (keyspace.Result, error) {
+		return keyspace.Expire(c, keys, seconds, opts)
+	})
+}
+
+// runBulk scans the keys matching pattern, previews them, asks once for
+// confirmation and then runs op with opts and a progress bar.
+func runBulk(c *conn.Connection, pattern, action string, opts keyspace.Options, op func(keys []string, opts keyspace.Options) (keyspace.Result, error)) {
+	keys, err := keyspace.Scan(c, pattern, nil)
+	if err != nil {
+		color.Red("%v", err)
+		return
+	}
+	if len(keys) == 0 {
+		color.Yellow("No keys match %s", pattern)
+		return
+	}
+
+	color.Cyan("%d keys match %s:", len(keys), pattern)
+	for _, key := range keyspace.Sample(keys) {
+		fmt.Printf("  %s\n", key)
+	}
+	if len(keys) > keyspace.SampleSize {
+		fmt.Printf("  ... and %d more\n", len(keys)-keyspace.SampleSize)
+	}
+	color.Yellow("%s %d keys? (Y/N)", action, len(keys))
+	if !confirmed() {
+		color.Yellow("Aborted.")
+		return
+	}
+
+	opts.Progress = func(done, total int) { fmt.Printf("\r%s", output.ProgressBar(done, total, progressWidth)) }
+	result, err := op(keys, opts)
+	fmt.Print("\r\033[K")
+	if err != nil {
+		color.Red("Failed after %d keys: %v", result.Done, err)
+		return
+	}
+	color.Green("Done: %d keys, %d already gone, %d failed", result.Done, result.Missing, result.Failed)
+	for _, err := range result.Errors {
+		color.Red("  %v", err)
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"os"
+	"strings"
+
+	"github.com/chzyer/readline"
+	"github.com/cosmez/redisman-go/internal/command"
+	"github.com/cosmez/redisman-go/internal/conn"
+	"github.com/cosmez/redisman-go/internal/server"
+	"github.com/fatih/color"
+)
+
+// handleClient prints CLIENT LIST as a table, with SORT and FILTER options.
+// Other subcommands, and replies sent to a pipe, filter or file, go to
+// Redis unchanged.
+func handleClient(rl *readline.Instance, c *conn.Connection, reg *command.Registry, parsed *command.ParsedCommand) {
+	if parsed.Pipe != "" || parsed.Filter != "" || parsed.Redirect != "" ||
+		len(parsed.Args) == 0 || !strings.EqualFold(parsed.Args[0], "LIST") {
+		handleStandardCommand(rl, c, reg, parsed)
+		return
+	}
+
+	opts, err := server.ParseClientArgs(parsed.Args[1:])
+	if err != nil {
+		color.Red("Usage: CLIENT LIST [TYPE type] [SORT column] [FILTER text] (%v)", err)
+		return
+	}
+	clients, err := server.Clients(c, opts)
+	if err != nil {
+		color.Red("%v", err)
+		return
+	}
+	if len(clients) == 0 {
+		color.Yellow("No matching clients.")
+		return
+	}
+	if err := server.WriteClients(os.Stdout, clients); err != nil {
+		color.Red("%v", err)
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cosmez/redisman-go/internal/backup"
+	"github.com/cosmez/redisman-go/internal/command"
+	"github.com/cosmez/redisman-go/internal/conn"
+	"github.com/cosmez/redisman-go/internal/output"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// progressWidth is the width of the copy progress bar in characters.
+const progressWidth = 30
+
+func handleCopyKeys(c *conn.Connection, parsed *command.ParsedCommand) {
+	profile, opts, err := backup.ParseCopyArgs(parsed.Args)
+	if err != nil {
+		color.Red("Usage: COPY-KEYS <profile> <pattern> [REPLACE|SKIPEXISTING] [DRYRUN] [RATE n] [MODE auto|dump|logical] (%v)", err)
+		return
+	}
+
+	dst, err := connectProfile(profile)
+	if err != nil {
+		color.Red("%v", err)
+		return
+	}
+	defer dst.Close()
+
+	opts.Progress = func(done, total int) { fmt.Printf("\rCopying %s", output.ProgressBar(done, total, progressWidth)) }
+	result, err := backup.Copy(c, dst, opts)
+	fmt.Print("\r\033[K")
+	if err != nil {
+		color.Red("Copy failed after %d keys: %v", result.Restored, err)
+		return
+	}
+	if opts.DryRun {
+		color.Cyan("Dry run of a %s copy to %s: %d keys match", result.Mode, profile, result.Total)
+		printResultCounts("Would copy", result.RestoreResult)
+		return
+	}
+	color.Cyan("Copied keys to %s using %s", profile, result.Mode)
+	printResultCounts("Copied", result.RestoreResult)
+}
+
+// connectProfile connects to the server named by a config profile or host:port.
+func connectProfile(name string) (*conn.Connection, error) {
+	p, err := cfg.Profiles.Lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	h, pt := p.Addr()
+	c, err := conn.Connect(h, pt, p.Username, p.Password)
+	if err != nil {
+		return nil, fmt.Errorf("connection to %s failed: %w", name, err)
+	}
+	return c, nil
+}
+
+// newCopyCmd returns the `redisman copy --from a --to b` subcommand.
+func newCopyCmd() *cobra.Command {
+	var from, to string
+	var opts backup.CopyOptions
+
+	cmd := &cobra.Command{
+		Use:   "copy --to <profile> [--from <profile>] [--pattern <pattern>]",
+		Short: "Copy keys matching a pattern from one server to another",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if !backup.ValidCopyMode(opts.Mode) {
+				fmt.Fprintf(os.Stderr, "Invalid --mode %q: use auto, dump or logical\n", opts.Mode)
+				os.Exit(1)
+			}
+			if !backup.ValidConflict(opts.Conflict) {
+				fmt.Fprintf(os.Stderr, "Invalid --conflict %q: use fail, skip or replace\n", opts.Conflict)
+				os.Exit(1)
+			}
+
+			var src *conn.Connection
+			if from == "" {
+				src = connectOrExit()
+			} else {
+				var err error
+				if src, err = connectProfile(from); err != nil {
+					fmt.Fprintln(os.Stderr, err)
+					os.Exit(1)
+				}
+			}
+			defer src.Close()
+
+			dst, err := connectProfile(to)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			defer dst.Close()
+
+			if term.IsTerminal(int(os.Stderr.Fd())) {
+				opts.Progress = func(done, total int) {
+					fmt.Fprintf(os.Stderr, "\r%s", output.ProgressBar(done, total, progressWidth))
+				}
+			}
+			result, err := backup.Copy(src, dst, opts)
+			if opts.Progress != nil {
+				fmt.Fprint(os.Stderr, "\r\033[K")
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Copy failed after %d keys: %v\n", result.Restored, err)
+				os.Exit(1)
+			}
+
+			verb := "Copied"
+			if opts.DryRun {
+				verb = "Would copy"
+			}
+			fmt.Printf("%s %d of %d keys (%s), skipped %d, failed %d\n",
+				verb, result.Restored, result.Total, result.Mode, result.Skipped, result.Failed)
+			for _, err := range result.Errors {
+				fmt.Fprintf(os.Stderr, "  %v\n", err)
+			}
+			if result.Failed > 0 && !opts.DryRun {
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "Source profile or host:port (default: the --host/--port server)")
+	cmd.Flags().StringVar(&to, "to", "", "Destination profile or host:port")
+	cmd.Flags().StringVar(&opts.Pattern, "pattern", "*", "Copy keys matching this pattern")
+	cmd.Flags().StringVar(&opts.Mode, "mode", backup.CopyAuto, "Copy mode: auto, dump or logical")
+	cmd.Flags().StringVar(&opts.Conflict, "conflict", backup.ConflictFail, "Existing keys on the destination: fail, skip or replace")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Report what would be copied without writing")
+	cmd.Flags().IntVar(&opts.Rate, "rate", 0, "Maximum keys per second (0 for no limit)")
+	cmd.MarkFlagRequired("to")
+	return cmd
+}
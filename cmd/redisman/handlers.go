@@ -1,18 +1,22 @@
 package main
 
 import (
-	"context"
 	"fmt"
+	"iter"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/chzyer/readline"
 	"github.com/cosmez/redisman-go/internal/command"
 	"github.com/cosmez/redisman-go/internal/conn"
+	"github.com/cosmez/redisman-go/internal/keyspace"
 	"github.com/cosmez/redisman-go/internal/output"
+	"github.com/cosmez/redisman-go/internal/resp"
 	"github.com/cosmez/redisman-go/internal/serializer"
 	"github.com/fatih/color"
+	"golang.org/x/term"
 )
 
 func handleCommand(rl *readline.Instance, c *conn.Connection, reg *command.Registry, parsed *command.ParsedCommand) {
@@ -31,8 +35,48 @@ func handleCommand(rl *readline.Instance, c *conn.Connection, reg *command.Regis
 		handleView(c, parsed)
 	case "EXPORT":
 		handleExport(c, reg, parsed)
-	case "SUBSCRIBE":
+	case "BACKUP":
+		handleBackup(c, parsed)
+	case "RESTORE-FILE":
+		handleRestoreFile(c, parsed)
+	case "EXPORT-KEYS":
+		handleExportKeys(c, parsed)
+	case "IMPORT-KEYS":
+		handleImportKeys(c, parsed)
+	case "COPY-KEYS":
+		handleCopyKeys(c, parsed)
+	case "DELPATTERN":
+		handleDelPattern(c, parsed)
+	case "EXPIREPATTERN":
+		handleExpirePattern(c, parsed)
+	case "ANALYZE":
+		handleAnalyze(c, parsed)
+	case "PREFIXES":
+		handlePrefixes(c, parsed)
+	case "RENAME-KEY":
+		handleKeyAction(c, parsed, keyspace.OpRename)
+	case "DUPLICATE-KEY":
+		handleKeyAction(c, parsed, keyspace.OpDuplicate)
+	case "MOVE-KEY":
+		handleKeyAction(c, parsed, keyspace.OpMove)
+	case "SLOWLOG":
+		handleSlowlog(rl, c, reg, parsed)
+	case "CLIENT":
+		handleClient(rl, c, reg, parsed)
+	case "MONITOR":
+		handleMonitor(rl, c, parsed)
+	case "WATCHKEYS":
+		handleWatchKeys(rl, c, parsed)
+	case "DASHBOARD":
+		color.Yellow("DASHBOARD is only available in the TUI (--tui)")
+	case "PAGING":
+		handlePaging(parsed)
+	case "FORMAT":
+		handleFormat(parsed)
+	case "SUBSCRIBE", "PSUBSCRIBE", "SSUBSCRIBE":
 		handleSubscribe(rl, c, parsed)
+	case "PUBSUB":
+		handlePubSub(rl, c, reg, parsed)
 	default:
 		handleStandardCommand(rl, c, reg, parsed)
 	}
@@ -99,8 +143,92 @@ func handleSafeKeys(c *conn.Connection, parsed *command.ParsedCommand) {
 	}
 
 	seq := c.SafeKeys(pattern)
+	if parsed.Redirect != "" {
+		redirectTo(parsed, nil, seq, "keys", nil)
+		return
+	}
+	if parsed.Pipe != "" {
+		reportPipeError(output.PipeRedisValues(os.Stdout, seq, parsed.Pipe, "keys"))
+		return
+	}
 	opts := output.PrintOpts{Color: true, Newline: true}
-	output.PrintRedisValues(os.Stdout, os.Stdin, seq, opts, 100)
+	output.PageRedisValues(os.Stdout, seq, opts, pageOpts())
+}
+
+// pageOpts returns the current paging settings sized to the terminal.
+func pageOpts() output.PageOpts {
+	page := paging
+	page.Input = os.Stdin
+	if _, h, err := term.GetSize(int(os.Stdout.Fd())); err == nil {
+		page.Height = h - 1 // leave room for the prompt
+	}
+	return page
+}
+
+func handleFormat(parsed *command.ParsedCommand) {
+	if len(parsed.Args) > 0 {
+		f := strings.ToLower(parsed.Args[0])
+		if !output.ValidFormat(f) {
+			color.Red("Usage: FORMAT [text|json|csv]")
+			return
+		}
+		format = f
+	}
+	color.Cyan("Format: %s", format)
+}
+
+// redirectTo writes a result to the file of a `> file` or `>> file` redirect
+// in the current format. A filter, if present, writes its results instead.
+func redirectTo(parsed *command.ParsedCommand, v resp.RedisValue, values iter.Seq[resp.RedisValue], typeHint string, ser serializer.Serializer) {
+	f, err := output.OpenRedirect(parsed.Redirect, parsed.Append)
+	if err != nil {
+		color.Red("Redirect failed: %v", err)
+		return
+	}
+	defer f.Close()
+
+	switch {
+	case parsed.Filter != "" && v != nil:
+		err = output.FilterRedisValue(f, v, parsed.Filter, output.PrintOpts{Serializer: ser})
+	case parsed.Filter != "":
+		err = output.FilterRedisValues(f, values, parsed.Filter, output.PrintOpts{Serializer: ser})
+	default:
+		err = output.WriteFormatted(f, v, values, format, typeHint, ser)
+	}
+	if err != nil {
+		color.Red("Redirect failed: %v", err)
+		return
+	}
+	color.Green("Written to %s", parsed.Redirect)
+}
+
+func handlePaging(parsed *command.ParsedCommand) {
+	for _, arg := range parsed.Args {
+		if size, err := strconv.Atoi(arg); err == nil && size > 0 {
+			paging.PageSize = size
+			continue
+		}
+		mode := strings.ToLower(arg)
+		if !output.ValidPagingMode(mode) {
+			color.Red("Usage: PAGING [pager|prompt|none] [page-size]")
+			return
+		}
+		paging.Mode = mode
+	}
+	color.Cyan("Paging: %s (page size %d)", paging.Mode, paging.PageSize)
+}
+
+// reportPipeError prints the outcome of a pipe: a non-zero exit status of
+// the shell command, or a failure to run it.
+func reportPipeError(err error) {
+	if err == nil {
+		return
+	}
+	if code, ok := output.ExitCode(err); ok {
+		color.Yellow("Exit status %d", code)
+		return
+	}
+	color.Red("Pipe error: %v", err)
 }
 
 func handleView(c *conn.Connection, parsed *command.ParsedCommand) {
@@ -122,21 +250,33 @@ func handleView(c *conn.Connection, parsed *command.ParsedCommand) {
 		return
 	}
 
-	opts := output.PrintOpts{Color: true, Newline: true}
-	if parsed.Modifier != "" {
-		ser, err := serializer.Get(parsed.Modifier)
-		if err != nil {
-			color.Red("Serializer error: %v", err)
-			return
-		}
-		opts.Serializer = ser
+	opts := output.PrintOpts{Color: true, Newline: true, PrettyJSON: true}
+	ser, err := codecRules.Resolve(parsed.Modifier, key)
+	if err != nil {
+		color.Red("Serializer error: %v", err)
+		return
 	}
+	opts.Serializer = ser
 
-	if single != nil {
-		output.PrintRedisValue(os.Stdout, single, opts)
+	if parsed.Redirect != "" {
+		redirectTo(parsed, single, collection, typeName, ser)
+	} else if single != nil && parsed.Filter != "" {
+		if err := output.FilterRedisValue(os.Stdout, single, parsed.Filter, opts); err != nil {
+			color.Red("Filter error: %v", err)
+		}
+	} else if collection != nil && parsed.Filter != "" {
+		if err := output.FilterRedisValues(os.Stdout, collection, parsed.Filter, opts); err != nil {
+			color.Red("Filter error: %v", err)
+		}
+	} else if single != nil && parsed.Pipe != "" {
+		reportPipeError(output.PipeRedisValue(os.Stdout, single, parsed.Pipe))
+	} else if single != nil {
+		output.PageRedisValue(os.Stdout, single, opts, pageOpts())
+	} else if collection != nil && parsed.Pipe != "" {
+		reportPipeError(output.PipeRedisValues(os.Stdout, collection, parsed.Pipe, typeName))
 	} else if collection != nil {
 		opts.TypeHint = typeName
-		output.PrintRedisValues(os.Stdout, os.Stdin, collection, opts, 100)
+		output.PageRedisValues(os.Stdout, collection, opts, pageOpts())
 	}
 }
 
@@ -196,36 +336,33 @@ func handleExport(c *conn.Connection, reg *command.Registry, parsed *command.Par
 	}
 }
 
-func handleSubscribe(rl *readline.Instance, c *conn.Connection, parsed *command.ParsedCommand) {
-	if err := c.Send(parsed); err != nil {
-		color.Red("Send error: %v", err)
-		return
-	}
-
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	color.Yellow("Subscribed. Press Ctrl+C to stop.")
-
-	// Run subscription in a goroutine
-	go func() {
-		seq := c.Subscribe(ctx)
-		opts := output.PrintOpts{Color: true, Newline: true}
-		for msg := range seq {
-			if parsed.Pipe != "" {
-				output.PipeRedisValue(os.Stdout, msg, parsed.Pipe)
-			} else {
-				output.PrintRedisValue(os.Stdout, msg, opts)
+// confirmed reads a line from stdin and reports whether it starts with Y.
+func confirmed() bool {
+	var ans []byte
+	buf := make([]byte, 1)
+	for {
+		n, err := os.Stdin.Read(buf)
+		if n > 0 {
+			ans = append(ans, buf[0])
+			if buf[0] == '\n' {
+				break
 			}
 		}
-	}()
+		if err != nil {
+			break
+		}
+	}
 
-	// Wait for Ctrl+C
+	ansStr := strings.TrimSpace(string(ans))
+	return len(ansStr) > 0 && (ansStr[0] == 'Y' || ansStr[0] == 'y')
+}
+
+// waitForInterrupt reads lines until Ctrl+C (or Ctrl+D), for commands that
+// stream output until the user stops them.
+func waitForInterrupt(rl *readline.Instance) {
 	for {
-		_, err := rl.Readline()
-		if err == readline.ErrInterrupt {
-			cancel()
-			break
+		if _, err := rl.Readline(); err != nil {
+			return
 		}
 	}
 }
@@ -237,24 +374,7 @@ func handleStandardCommand(_ *readline.Instance, c *conn.Connection, reg *comman
 			color.Cyan("Hint: You can execute SAFEKEYS or SCAN instead.")
 		}
 
-		// Read single character confirmation
-		var ans []byte
-		buf := make([]byte, 1)
-		for {
-			n, err := os.Stdin.Read(buf)
-			if n > 0 {
-				ans = append(ans, buf[0])
-				if buf[0] == '\n' {
-					break
-				}
-			}
-			if err != nil {
-				break
-			}
-		}
-
-		ansStr := strings.TrimSpace(string(ans))
-		if len(ansStr) == 0 || (ansStr[0] != 'Y' && ansStr[0] != 'y') {
+		if !confirmed() {
 			color.Yellow("Aborted.")
 			return
 		}
@@ -280,21 +400,23 @@ func handleStandardCommand(_ *readline.Instance, c *conn.Connection, reg *comman
 		return
 	}
 
-	opts := output.PrintOpts{Color: true, Newline: true}
-	if parsed.Modifier != "" {
-		ser, err := serializer.Get(parsed.Modifier)
-		if err != nil {
-			color.Red("Serializer error: %v", err)
-			return
-		}
-		opts.Serializer = ser
+	opts := output.PrintOpts{Color: true, Newline: true, PrettyJSON: true}
+	ser, err := codecRules.Resolve(parsed.Modifier, command.ValueKey(parsed))
+	if err != nil {
+		color.Red("Serializer error: %v", err)
+		return
 	}
+	opts.Serializer = ser
 
-	if parsed.Pipe != "" {
-		if err := output.PipeRedisValue(os.Stdout, val, parsed.Pipe); err != nil {
-			color.Red("Pipe error: %v", err)
+	if parsed.Redirect != "" {
+		redirectTo(parsed, val, nil, "", ser)
+	} else if parsed.Filter != "" {
+		if err := output.FilterRedisValue(os.Stdout, val, parsed.Filter, opts); err != nil {
+			color.Red("Filter error: %v", err)
 		}
+	} else if parsed.Pipe != "" {
+		reportPipeError(output.PipeRedisValue(os.Stdout, val, parsed.Pipe))
 	} else {
-		output.PrintRedisValue(os.Stdout, val, opts)
+		output.PageRedisValue(os.Stdout, val, opts, pageOpts())
 	}
 }
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/cosmez/redisman-go/internal/command"
+	"github.com/cosmez/redisman-go/internal/conn"
+	"github.com/cosmez/redisman-go/internal/keyspace"
+	"github.com/fatih/color"
+)
+
+// keyActionUsage is the usage line of each single-key built-in.
+var keyActionUsage = map[string]string{
+	keyspace.OpRename:    "RENAME-KEY <key> <newname> [REPLACE]",
+	keyspace.OpDuplicate: "DUPLICATE-KEY <key> <dest> [DB n] [REPLACE]",
+	keyspace.OpMove:      "MOVE-KEY <key> <db>",
+}
+
+// handleKeyAction previews a rename, duplicate or move, asks for
+// confirmation and runs it.
+func handleKeyAction(c *conn.Connection, parsed *command.ParsedCommand, op string) {
+	action, err := keyspace.ParseKeyActionArgs(op, parsed.Args)
+	if err != nil {
+		color.Red("Usage: %s (%v)", keyActionUsage[op], err)
+		return
+	}
+	lines, err := action.Preview(c)
+	if err != nil {
+		color.Red("%v", err)
+		return
+	}
+	for _, line := range lines[1:] {
+		fmt.Printf("  %s\n", line)
+	}
+	color.Yellow("%s? (Y/N)", lines[0])
+	if !confirmed() {
+		color.Yellow("Aborted.")
+		return
+	}
+	if err := action.Run(c); err != nil {
+		color.Red("%v", err)
+		return
+	}
+	color.Green("Done.")
+}
@@ -5,8 +5,10 @@ import (
 	"os"
 
 	"github.com/cosmez/redisman-go/internal/command"
+	"github.com/cosmez/redisman-go/internal/config"
 	"github.com/cosmez/redisman-go/internal/conn"
 	"github.com/cosmez/redisman-go/internal/output"
+	"github.com/cosmez/redisman-go/internal/serializer"
 	"github.com/cosmez/redisman-go/internal/tui"
 	"github.com/spf13/cobra"
 )
@@ -14,12 +16,18 @@ import (
 var (
 	version = "dev" // set at build time via -ldflags "-X main.version=..."
 
-	host     string
-	port     string
-	username string
-	password string
-	cmdStr  string
-	tuiMode bool
+	host       string
+	port       string
+	username   string
+	password   string
+	cmdStr     string
+	tuiMode    bool
+	configPath string
+
+	cfg        *config.Config    // loaded once before any mode runs
+	codecRules *serializer.Rules // key pattern → codec rules from the rules file
+	paging     output.PageOpts   // REPL paging settings; changed at runtime with PAGING
+	format     string            // format for `> file` redirects; changed at runtime with FORMAT
 )
 
 func main() {
@@ -27,6 +35,9 @@ func main() {
 		Use:     "redisman",
 		Short:   "A cross-platform Redis client",
 		Version: version,
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			loadConfig()
+		},
 		Run: func(cmd *cobra.Command, args []string) {
 			if tuiMode {
 				runTUI()
@@ -41,12 +52,15 @@ func main() {
 		},
 	}
 
-	rootCmd.Flags().StringVarP(&host, "host", "H", "localhost", "Redis server host")
-	rootCmd.Flags().StringVarP(&port, "port", "p", "6379", "Redis server port")
-	rootCmd.Flags().StringVarP(&username, "username", "u", "", "Redis ACL username")
-	rootCmd.Flags().StringVar(&password, "password", "", "Redis password")
+	rootCmd.PersistentFlags().StringVarP(&host, "host", "H", "localhost", "Redis server host")
+	rootCmd.PersistentFlags().StringVarP(&port, "port", "p", "6379", "Redis server port")
+	rootCmd.PersistentFlags().StringVarP(&username, "username", "u", "", "Redis ACL username")
+	rootCmd.PersistentFlags().StringVar(&password, "password", "", "Redis password")
 	rootCmd.Flags().StringVarP(&cmdStr, "command", "c", "", "Execute a single command and exit")
 	rootCmd.Flags().BoolVar(&tuiMode, "tui", false, "Launch TUI mode")
+	rootCmd.PersistentFlags().StringVar(&configPath, "config", config.DefaultPath(), "Path to the config file")
+
+	rootCmd.AddCommand(newBackupCmd(), newRestoreCmd(), newCopyCmd())
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
@@ -54,6 +68,57 @@ func main() {
 	}
 }
 
+// loadConfig reads the config file, registers any external codecs it defines
+// so that `#:name` modifiers resolve to them, applies the pipe, paging and
+// format settings, then loads the codec rules file.
+// Errors are fatal: a half-applied config would make codec behavior surprising.
+func loadConfig() {
+	var err error
+	cfg, err = config.Load(configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Config error: %v\n", err)
+		os.Exit(1)
+	}
+
+	for name, cc := range cfg.Codecs {
+		timeout, _ := cc.TimeoutDuration() // validated by config.Load
+		codec := serializer.NewExternal(name, cc.Encode, cc.Decode, timeout)
+		if err := serializer.Register(name, codec); err != nil {
+			fmt.Fprintf(os.Stderr, "Config error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	switch cfg.PipeShell {
+	case "":
+		// keep output.DefaultPipeShell
+	case config.PipeShellNone:
+		output.PipeShell = nil
+	default:
+		output.PipeShell = []string{cfg.PipeShell, "-c"}
+	}
+
+	paging = output.PageOpts{Mode: cfg.Paging, PageSize: cfg.PageSize}
+	if paging.Mode == "" {
+		paging.Mode = output.PagingPager
+	}
+	if paging.PageSize == 0 {
+		paging.PageSize = output.DefaultPageSize
+	}
+
+	format = cfg.Format
+	if format == "" {
+		format = output.FormatText
+	}
+
+	// Rules are loaded after registration so they may reference external codecs.
+	codecRules, err = serializer.LoadRules(cfg.RulesPath(configPath))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Codec rules error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
 func runTUI() {
 	reg, err := command.NewRegistry()
 	if err != nil {
@@ -70,7 +135,13 @@ func runTUI() {
 
 	mergeServerCommands(c, reg)
 
-	if err := tui.Run(c, reg); err != nil {
+	if err := tui.Run(c, reg, tui.Options{
+		Rules:    codecRules,
+		Format:   format,
+		Profiles: cfg.Profiles,
+		KeyView:  cfg.KeyView,
+		KeyDelim: cfg.KeyDelim,
+	}); err != nil {
 		fmt.Fprintf(os.Stderr, "TUI error: %v\n", err)
 		os.Exit(1)
 	}
@@ -111,9 +182,41 @@ func runOneShot() {
 		Color:   false, // Usually no color for one-shot scripts
 		Newline: true,
 	}
+	ser, err := codecRules.Resolve(parsed.Modifier, command.ValueKey(parsed))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Serializer error: %v\n", err)
+		os.Exit(1)
+	}
+	opts.Serializer = ser
+	opts.PrettyJSON = serializer.IsJSON(ser) // scripts get raw values unless #:json asks otherwise
 
-	if parsed.Pipe != "" {
+	if parsed.Redirect != "" {
+		f, err := output.OpenRedirect(parsed.Redirect, parsed.Append)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Redirect error: %v\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		if parsed.Filter != "" {
+			err = output.FilterRedisValue(f, val, parsed.Filter, opts)
+		} else {
+			err = output.WriteFormatted(f, val, nil, format, "", ser)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Redirect error: %v\n", err)
+			os.Exit(1)
+		}
+	} else if parsed.Filter != "" {
+		if err := output.FilterRedisValue(os.Stdout, val, parsed.Filter, opts); err != nil {
+			fmt.Fprintf(os.Stderr, "Filter error: %v\n", err)
+			os.Exit(1)
+		}
+	} else if parsed.Pipe != "" {
 		if err := output.PipeRedisValue(os.Stdout, val, parsed.Pipe); err != nil {
+			// Scripts see the pipe's own exit status, like a shell pipeline.
+			if code, ok := output.ExitCode(err); ok {
+				os.Exit(code)
+			}
 			fmt.Fprintf(os.Stderr, "Pipe error: %v\n", err)
 			os.Exit(1)
 		}
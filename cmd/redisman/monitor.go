@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/chzyer/readline"
+	"github.com/cosmez/redisman-go/internal/command"
+	"github.com/cosmez/redisman-go/internal/conn"
+	"github.com/cosmez/redisman-go/internal/output"
+	"github.com/cosmez/redisman-go/internal/server"
+	"github.com/fatih/color"
+)
+
+// handleMonitor streams MONITOR until Ctrl+C, keeping the commands that match
+// the CMD, KEY and CLIENT options. MONITOR runs on a second connection, so
+// this one stays usable afterwards. With > file, the matching lines are also
+// recorded as the server sent them.
+func handleMonitor(rl *readline.Instance, c *conn.Connection, parsed *command.ParsedCommand) {
+	if parsed.Pipe != "" || parsed.Filter != "" {
+		color.Red("MONITOR output cannot be piped or filtered; use the CMD, KEY and CLIENT options, or record it with > file")
+		return
+	}
+	filter, err := server.ParseMonitorArgs(parsed.Args)
+	if err != nil {
+		color.Red("Usage: MONITOR [CMD name[,name...]] [KEY pattern] [CLIENT text] [> file] (%v)", err)
+		return
+	}
+
+	var record *os.File
+	if parsed.Redirect != "" {
+		if record, err = output.OpenRedirect(parsed.Redirect, parsed.Append); err != nil {
+			color.Red("Redirect failed: %v", err)
+			return
+		}
+		defer record.Close()
+	}
+
+	mc, err := c.Dial()
+	if err != nil {
+		color.Red("MONITOR connection failed: %v", err)
+		return
+	}
+	defer mc.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	status := "Monitoring"
+	if f := filter.String(); f != "" {
+		status += " " + f
+	}
+	if record != nil {
+		status += ", recording to " + parsed.Redirect
+	}
+	color.Yellow("%s. Press Ctrl+C to stop.", status)
+
+	done := make(chan int)
+	go func(record *os.File) {
+		seen := 0
+		for e, err := range mc.Monitor(ctx) {
+			if err != nil {
+				color.Red("%v", err)
+				continue
+			}
+			if !filter.Match(e) {
+				continue
+			}
+			seen++
+			fmt.Println(server.FormatMonitorEvent(e))
+			if record != nil {
+				if _, err := fmt.Fprintln(record, e.Line); err != nil {
+					color.Red("Recording stopped: %v", err)
+					record = nil
+				}
+			}
+		}
+		done <- seen
+	}(record)
+
+	waitForInterrupt(rl)
+	cancel()
+	color.Yellow("Stopped after %d commands.", <-done)
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"os"
+	"strings"
+
+	"github.com/chzyer/readline"
+	"github.com/cosmez/redisman-go/internal/command"
+	"github.com/cosmez/redisman-go/internal/conn"
+	"github.com/cosmez/redisman-go/internal/output"
+	"github.com/cosmez/redisman-go/internal/resp"
+	"github.com/cosmez/redisman-go/internal/server"
+	"github.com/fatih/color"
+)
+
+// handleSubscribe runs SUBSCRIBE, PSUBSCRIBE or SSUBSCRIBE on a second
+// connection until Ctrl+C, so this one is never left subscribed. Each
+// message is printed with its channel, its payload decoded with #:codec or
+// the codec rule matching the channel. With > file the messages are written
+// there as channel and payload pairs; with | cmd each pair goes to cmd.
+func handleSubscribe(rl *readline.Instance, c *conn.Connection, parsed *command.ParsedCommand) {
+	if len(parsed.Args) == 0 {
+		color.Red("Usage: %s channel [channel ...]", parsed.Name)
+		return
+	}
+	if parsed.Filter != "" {
+		color.Red("%s messages cannot be filtered with |>; use | or > instead", parsed.Name)
+		return
+	}
+	sc, err := c.Dial()
+	if err != nil {
+		color.Red("Subscribe connection failed: %v", err)
+		return
+	}
+	defer sc.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	color.Yellow("Subscribed to %s. Press Ctrl+C to stop.", strings.Join(parsed.Args, ", "))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		messages := subscribeMessages(ctx, sc, parsed)
+		switch {
+		case parsed.Redirect != "":
+			// Messages stream into the file until Ctrl+C ends the subscription.
+			redirectTo(parsed, nil, messageValues(messages), "", nil)
+		case parsed.Pipe != "":
+			for v := range messageValues(messages) {
+				reportPipeError(output.PipeRedisValue(os.Stdout, v, parsed.Pipe))
+			}
+		default:
+			for m := range messages {
+				fmt.Println(color.CyanString(server.MessageSource(m)+":"), m.Payload)
+			}
+		}
+	}()
+
+	waitForInterrupt(rl)
+	cancel()
+	<-done
+}
+
+// subscribeMessages yields the published messages of a subscription, with
+// payloads decoded. Errors are printed; subscription confirmations skipped.
+func subscribeMessages(ctx context.Context, sc *conn.Connection, parsed *command.ParsedCommand) iter.Seq[conn.PubSubMessage] {
+	return func(yield func(conn.PubSubMessage) bool) {
+		for m, err := range sc.Listen(ctx, parsed.Name, parsed.Args...) {
+			if err != nil {
+				color.Red("%v", err)
+				continue
+			}
+			if !m.IsMessage() {
+				continue
+			}
+			ser, err := codecRules.Resolve(parsed.Modifier, m.Channel)
+			if err != nil {
+				color.Red("Serializer error: %v", err)
+				return
+			}
+			m.Payload = server.DecodePayload(m.Payload, ser)
+			if !yield(m) {
+				return
+			}
+		}
+	}
+}
+
+// messageValues turns messages into [channel, payload] arrays for the file
+// and pipe writers.
+func messageValues(messages iter.Seq[conn.PubSubMessage]) iter.Seq[resp.RedisValue] {
+	return func(yield func(resp.RedisValue) bool) {
+		for m := range messages {
+			v := resp.RedisArray{Values: []resp.RedisValue{
+				resp.RedisBulkString{Value: m.Channel, Length: len(m.Channel)},
+				resp.RedisBulkString{Value: m.Payload, Length: len(m.Payload)},
+			}}
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// handlePubSub prints PUBSUB CHANNELS and NUMSUB (and their SHARD variants)
+// as a table of channels and subscriber counts; CHANNELS lists the busiest
+// first. Other subcommands, and replies sent to a pipe, filter or file, go
+// to Redis unchanged.
+func handlePubSub(rl *readline.Instance, c *conn.Connection, reg *command.Registry, parsed *command.ParsedCommand) {
+	sub := ""
+	if len(parsed.Args) > 0 {
+		sub = strings.ToUpper(parsed.Args[0])
+	}
+	if parsed.Pipe != "" || parsed.Filter != "" || parsed.Redirect != "" {
+		sub = ""
+	}
+
+	var channels []server.ChannelInfo
+	var err error
+	switch sub {
+	case "CHANNELS", "SHARDCHANNELS":
+		if len(parsed.Args) > 2 {
+			color.Red("Usage: PUBSUB %s [pattern]", sub)
+			return
+		}
+		pattern := ""
+		if len(parsed.Args) == 2 {
+			pattern = parsed.Args[1]
+		}
+		channels, err = server.Channels(c, sub == "SHARDCHANNELS", pattern)
+	case "NUMSUB", "SHARDNUMSUB":
+		if len(parsed.Args) < 2 {
+			color.Red("Usage: PUBSUB %s channel [channel ...]", sub)
+			return
+		}
+		channels, err = server.NumSub(c, sub == "SHARDNUMSUB", parsed.Args[1:])
+	default:
+		handleStandardCommand(rl, c, reg, parsed)
+		return
+	}
+	if err != nil {
+		color.Red("%v", err)
+		return
+	}
+	if len(channels) == 0 {
+		color.Yellow("No active channels.")
+		return
+	}
+	if err := server.WriteChannels(os.Stdout, channels); err != nil {
+		color.Red("%v", err)
+	}
+}
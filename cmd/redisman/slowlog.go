@@ -0,0 +1,62 @@
+package main
+
+import (
+	"os"
+	"strings"
+
+	"github.com/chzyer/readline"
+	"github.com/cosmez/redisman-go/internal/command"
+	"github.com/cosmez/redisman-go/internal/conn"
+	"github.com/cosmez/redisman-go/internal/server"
+	"github.com/fatih/color"
+)
+
+// handleSlowlog prints SLOWLOG GET as a table, with SORT and CMD options,
+// and always asks before SLOWLOG RESET. Other subcommands, and SLOWLOG GET
+// replies sent to a pipe, filter or file, go to Redis unchanged.
+func handleSlowlog(rl *readline.Instance, c *conn.Connection, reg *command.Registry, parsed *command.ParsedCommand) {
+	sub := ""
+	if len(parsed.Args) > 0 {
+		sub = strings.ToUpper(parsed.Args[0])
+	}
+	if sub == "RESET" {
+		n, err := server.SlowlogLen(c)
+		if err != nil {
+			color.Red("%v", err)
+			return
+		}
+		color.Yellow("Reset the slowlog (%d entries)? (Y/N)", n)
+		if !confirmed() {
+			color.Yellow("Aborted.")
+			return
+		}
+		if err := server.ResetSlowlog(c); err != nil {
+			color.Red("%v", err)
+			return
+		}
+		color.Green("Done.")
+		return
+	}
+	if parsed.Pipe != "" || parsed.Filter != "" || parsed.Redirect != "" || sub != "GET" {
+		handleStandardCommand(rl, c, reg, parsed)
+		return
+	}
+
+	opts, err := server.ParseSlowlogArgs(parsed.Args[1:])
+	if err != nil {
+		color.Red("Usage: SLOWLOG GET [count] [SORT time|duration] [CMD name] (%v)", err)
+		return
+	}
+	entries, err := server.Slowlog(c, opts)
+	if err != nil {
+		color.Red("%v", err)
+		return
+	}
+	if len(entries) == 0 {
+		color.Yellow("No slowlog entries.")
+		return
+	}
+	if err := server.WriteSlowlog(os.Stdout, entries); err != nil {
+		color.Red("%v", err)
+	}
+}
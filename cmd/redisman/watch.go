@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chzyer/readline"
+	"github.com/cosmez/redisman-go/internal/command"
+	"github.com/cosmez/redisman-go/internal/conn"
+	"github.com/cosmez/redisman-go/internal/server"
+	"github.com/fatih/color"
+)
+
+// handleWatchKeys prints keyspace notifications for the matching keys of
+// the current database until Ctrl+C. notify-keyspace-events is extended if
+// the server lacks the needed flags and allows CONFIG SET, and set back when
+// the watch stops; otherwise it warns and listens anyway. The subscription
+// runs on a second connection.
+func handleWatchKeys(rl *readline.Instance, c *conn.Connection, parsed *command.ParsedCommand) {
+	if parsed.Pipe != "" || parsed.Filter != "" || parsed.Redirect != "" {
+		color.Red("WATCHKEYS output cannot be piped, filtered or redirected")
+		return
+	}
+	opts, err := server.ParseWatchArgs(parsed.Args)
+	if err != nil {
+		color.Red("Usage: WATCHKEYS [pattern] [EVENT name[,name...]] (%v)", err)
+		return
+	}
+	db, err := c.CurrentDB()
+	if err != nil {
+		color.Yellow("Cannot tell the current database (%v); watching db 0", err)
+	}
+
+	sc, err := c.Dial()
+	if err != nil {
+		color.Red("Subscribe connection failed: %v", err)
+		return
+	}
+	defer sc.Close()
+
+	before, after, err := server.EnableNotifications(c, opts.Flags())
+	if err != nil {
+		color.Yellow("Warning: %v. Events only arrive if %s is already set.", err, server.NotifyConfig)
+	} else if after != before {
+		color.Yellow("Set %s from %q to %q.", server.NotifyConfig, before, after)
+		defer func() {
+			if err := server.RestoreNotifications(c, before); err != nil {
+				color.Red("%v", err)
+				return
+			}
+			color.Yellow("Restored %s to %q.", server.NotifyConfig, before)
+		}()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	color.Yellow("Watching %s in db %d. Press Ctrl+C to stop.", opts, db)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for m, err := range sc.Listen(ctx, "PSUBSCRIBE", opts.Channels(db)...) {
+			if err != nil {
+				color.Red("%v", err)
+				continue
+			}
+			e, ok := server.ParseKeyEvent(m)
+			if !m.IsMessage() || !ok || !opts.Match(e) {
+				continue
+			}
+			fmt.Println(color.HiBlackString(time.Now().Format(server.MonitorTimeFormat)), color.CyanString(e.Key), e.Event)
+		}
+	}()
+
+	waitForInterrupt(rl)
+	cancel()
+	<-done
+}
@@ -0,0 +1,334 @@
+package backup
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cosmez/redisman-go/internal/conn"
+	"github.com/cosmez/redisman-go/internal/resp"
+)
+
+// DefaultBatchSize is how many keys are dumped or restored per pipeline.
+const DefaultBatchSize = 100
+
+// TTL modes for Restore.
+const (
+	TTLKeep     = "keep"     // restore each key with the TTL it had at backup time
+	TTLAbsolute = "absolute" // expire at backup time + TTL; keys already expired are skipped
+	TTLNone     = "none"     // restore every key without an expiry
+)
+
+// BackupOptions configures Backup.
+type BackupOptions struct {
+	Pattern   string           // SCAN MATCH pattern, "*" if empty
+	BatchSize int              // keys per DUMP/PTTL pipeline, DefaultBatchSize if 0
+	Progress  func(keys int)   // called after each batch with the running total, may be nil
+	Now       func() time.Time // clock for the header, time.Now if nil
+}
+
+// Backup iterates keys matching opts.Pattern with SafeKeys and writes a DUMP
+// and PTTL for each to w, pipelined in batches. Keys that disappear between
+// SCAN and DUMP are skipped. It returns the number of keys written.
+func Backup(c *conn.Connection, w io.Writer, opts BackupOptions) (int, error) {
+	if opts.Pattern == "" {
+		opts.Pattern = "*"
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+	now := time.Now
+	if opts.Now != nil {
+		now = opts.Now
+	}
+
+	hdr := Header{
+		ServerVersion: c.ServerInfo["redis_version"],
+		DB:            currentDB(c),
+		Pattern:       opts.Pattern,
+		Created:       now().UTC(),
+	}
+	bw, err := NewWriter(w, hdr)
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	batch := make([]string, 0, opts.BatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		recs, err := dumpBatch(c, batch)
+		if err != nil {
+			return err
+		}
+		for _, rec := range recs {
+			if err := bw.Write(rec); err != nil {
+				return err
+			}
+		}
+		total += len(recs)
+		batch = batch[:0]
+		if opts.Progress != nil {
+			opts.Progress(total)
+		}
+		return nil
+	}
+
+	for key := range c.SafeKeys(opts.Pattern) {
+		if errResp, ok := key.(resp.RedisError); ok {
+			return total, fmt.Errorf("scan failed: %s", errResp.Value)
+		}
+		batch = append(batch, key.StringValue())
+		if len(batch) == opts.BatchSize {
+			if err := flush(); err != nil {
+				return total, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return total, err
+	}
+
+	return total, bw.Close()
+}
+
+// dumpBatch pipelines DUMP and PTTL for keys and returns the records of the
+// keys that still exist.
+func dumpBatch(c *conn.Connection, keys []string) ([]Record, error) {
+	cmds := make([][]string, 0, len(keys)*2)
+	for _, key := range keys {
+		cmds = append(cmds, []string{"DUMP", key}, []string{"PTTL", key})
+	}
+	replies, err := c.Pipeline(cmds)
+	if err != nil {
+		return nil, err
+	}
+
+	recs := make([]Record, 0, len(keys))
+	for i, key := range keys {
+		dump, pttl := replies[2*i], replies[2*i+1]
+		if errResp, ok := dump.(resp.RedisError); ok {
+			return nil, fmt.Errorf("DUMP %s failed: %s", key, errResp.Value)
+		}
+		payload, ok := dump.(resp.RedisBulkString)
+		if !ok || payload.Length == -1 {
+			continue // deleted since SCAN
+		}
+
+		ttl := int64(0)
+		if n, ok := pttl.(resp.RedisInteger); ok {
+			if n.IntValue == -2 {
+				continue // expired since DUMP
+			}
+			if n.IntValue > 0 {
+				ttl = n.IntValue
+			}
+		}
+		recs = append(recs, Record{Key: []byte(key), TTL: ttl, Dump: []byte(payload.Value)})
+	}
+	return recs, nil
+}
+
+// currentDB asks the server which database the connection uses, from
+// CLIENT INFO (Redis 6.2+). It falls back to 0.
+func currentDB(c *conn.Connection) int {
+	db, _ := c.CurrentDB()
+	return db
+}
+
+// RestoreOptions configures Restore.
+type RestoreOptions struct {
+	Replace      bool             // overwrite existing keys (RESTORE ... REPLACE)
+	SkipExisting bool             // count existing keys as skipped instead of failed
+	TTLMode      string           // TTLKeep (default), TTLAbsolute or TTLNone
+	BatchSize    int              // keys per RESTORE pipeline, DefaultBatchSize if 0
+	Progress     func(keys int)   // called after each batch with the running total, may be nil
+	Now          func() time.Time // clock for TTLAbsolute, time.Now if nil
+}
+
+// RestoreResult counts what Restore did with each record.
+type RestoreResult struct {
+	Restored int
+	Skipped  int // existing keys with SkipExisting, or expired keys with TTLAbsolute
+	Failed   int
+	Errors   []error // first few failures, for reporting
+}
+
+// maxRestoreErrors caps RestoreResult.Errors.
+const maxRestoreErrors = 10
+
+// fail counts a failed key, keeping the first few errors for reporting.
+func (r *RestoreResult) fail(key, msg string) {
+	r.Failed++
+	if len(r.Errors) < maxRestoreErrors {
+		r.Errors = append(r.Errors, fmt.Errorf("%s: %s", key, msg))
+	}
+}
+
+// tally counts the reply to a RESTORE of key. BUSYKEY errors count as
+// skipped when skipExisting is set.
+func (r *RestoreResult) tally(key string, reply resp.RedisValue, skipExisting bool) {
+	errResp, ok := reply.(resp.RedisError)
+	switch {
+	case !ok:
+		r.Restored++
+	case skipExisting && strings.HasPrefix(errResp.Value, "BUSYKEY"):
+		r.Skipped++
+	default:
+		r.fail(key, errResp.Value)
+	}
+}
+
+// Restore reads records from br and RESTOREs them, pipelined in batches.
+// Per-key failures (e.g. BUSYKEY without Replace, or a payload from a newer
+// Redis version) are counted and do not stop the restore; a bad file or a
+// connection error does.
+func Restore(c *conn.Connection, br *Reader, opts RestoreOptions) (RestoreResult, error) {
+	var result RestoreResult
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+	if opts.TTLMode == "" {
+		opts.TTLMode = TTLKeep
+	}
+	now := time.Now
+	if opts.Now != nil {
+		now = opts.Now
+	}
+	created := br.Header().Created
+
+	var batch [][]string
+	var batchKeys []string
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		replies, err := c.Pipeline(batch)
+		if err != nil {
+			return err
+		}
+		for i, reply := range replies {
+			result.tally(batchKeys[i], reply, opts.SkipExisting)
+		}
+		batch, batchKeys = batch[:0], batchKeys[:0]
+		if opts.Progress != nil {
+			opts.Progress(result.Restored + result.Skipped + result.Failed)
+		}
+		return nil
+	}
+
+	for {
+		rec, err := br.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return result, err
+		}
+
+		args := []string{"RESTORE", string(rec.Key), "0", string(rec.Dump)}
+		switch opts.TTLMode {
+		case TTLKeep:
+			args[2] = strconv.FormatInt(rec.TTL, 10)
+		case TTLAbsolute:
+			if rec.TTL > 0 {
+				expireAt := created.Add(time.Duration(rec.TTL) * time.Millisecond)
+				if !expireAt.After(now()) {
+					result.Skipped++
+					continue
+				}
+				args[2] = strconv.FormatInt(expireAt.UnixMilli(), 10)
+				args = append(args, "ABSTTL")
+			}
+		}
+		if opts.Replace {
+			args = append(args, "REPLACE")
+		}
+
+		batch = append(batch, args)
+		batchKeys = append(batchKeys, string(rec.Key))
+		if len(batch) == opts.BatchSize {
+			if err := flush(); err != nil {
+				return result, err
+			}
+		}
+	}
+	return result, flush()
+}
+
+// ValidTTLMode reports whether mode is one of the TTL modes.
+func ValidTTLMode(mode string) bool {
+	return mode == TTLKeep || mode == TTLAbsolute || mode == TTLNone
+}
+
+// RestoreFile verifies the checksum of the backup at path before restoring
+// anything, then restores it. A corrupt file leaves the server untouched.
+func RestoreFile(c *conn.Connection, path string, opts RestoreOptions) (Header, RestoreResult, error) {
+	hdr, _, err := Verify(path)
+	if err != nil {
+		return hdr, RestoreResult{}, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return hdr, RestoreResult{}, err
+	}
+	defer f.Close()
+
+	br, err := NewReader(f)
+	if err != nil {
+		return hdr, RestoreResult{}, err
+	}
+	result, err := Restore(c, br, opts)
+	return hdr, result, err
+}
+
+// BackupFile creates the file at path and backs up into it. On error the
+// partial file is removed.
+func BackupFile(c *conn.Connection, path string, opts BackupOptions) (int, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := Backup(c, f, opts)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+	}
+	return n, err
+}
+
+// ParseRestoreArgs parses the arguments of the RESTORE-FILE built-in:
+// file [REPLACE] [SKIPEXISTING] [TTL keep|absolute|none].
+func ParseRestoreArgs(args []string) (string, RestoreOptions, error) {
+	var opts RestoreOptions
+	if len(args) == 0 {
+		return "", opts, fmt.Errorf("missing file")
+	}
+	for i := 1; i < len(args); i++ {
+		switch strings.ToUpper(args[i]) {
+		case "REPLACE":
+			opts.Replace = true
+		case "SKIPEXISTING":
+			opts.SkipExisting = true
+		case "TTL":
+			if i+1 >= len(args) || !ValidTTLMode(strings.ToLower(args[i+1])) {
+				return "", opts, fmt.Errorf("TTL must be followed by keep, absolute or none")
+			}
+			opts.TTLMode = strings.ToLower(args[i+1])
+			i++
+		default:
+			return "", opts, fmt.Errorf("unknown option %q", args[i])
+		}
+	}
+	return args[0], opts, nil
+}
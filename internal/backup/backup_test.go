@@ -0,0 +1,319 @@
+package backup
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cosmez/redisman-go/internal/conn"
+	"github.com/cosmez/redisman-go/internal/redistest"
+)
+
+// fakeKey is a key held by the fake server: a canned DUMP payload and a PTTL.
+type fakeKey struct {
+	dump string
+	pttl int64
+}
+
+// newFakeServer returns a connection to a fake server holding keys, and a
+// function that snapshots the server's keys (including RESTOREd ones).
+func newFakeServer(t *testing.T, keys map[string]fakeKey) (*conn.Connection, *redistest.Server, func() map[string][]string) {
+	t.Helper()
+	var mu sync.Mutex
+	restored := map[string][]string{}
+
+	s := redistest.NewServer(t)
+	s.Handle("SCAN", func(args []string) string {
+		mu.Lock()
+		defer mu.Unlock()
+		names := make([]string, 0, len(keys))
+		for k := range keys {
+			names = append(names, k)
+		}
+		slices.Sort(names)
+		return redistest.Array(redistest.Bulk("0"), redistest.BulkArray(names...))
+	})
+	s.Handle("DUMP", func(args []string) string {
+		mu.Lock()
+		defer mu.Unlock()
+		if k, ok := keys[args[1]]; ok {
+			return redistest.Bulk(k.dump)
+		}
+		return redistest.NullBulk
+	})
+	s.Handle("PTTL", func(args []string) string {
+		mu.Lock()
+		defer mu.Unlock()
+		if k, ok := keys[args[1]]; ok {
+			return redistest.Int(k.pttl)
+		}
+		return redistest.Int(-2)
+	})
+	s.Handle("CLIENT", func(args []string) string {
+		return redistest.Bulk("id=7 addr=127.0.0.1:5000 db=3 cmd=client|info\n")
+	})
+	s.Handle("RESTORE", func(args []string) string {
+		mu.Lock()
+		defer mu.Unlock()
+		_, exists := keys[args[1]]
+		if exists && !slices.Contains(args, "REPLACE") {
+			return redistest.Err("BUSYKEY Target key name already exists.")
+		}
+		keys[args[1]] = fakeKey{dump: args[3]}
+		restored[args[1]] = args[2:]
+		return redistest.Simple("OK")
+	})
+
+	c, err := conn.Connect(s.Host, s.Port, "", "")
+	if err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+
+	return c, s, func() map[string][]string {
+		mu.Lock()
+		defer mu.Unlock()
+		out := map[string][]string{}
+		for k, v := range restored {
+			out[k] = v
+		}
+		return out
+	}
+}
+
+func TestFileRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	hdr := Header{ServerVersion: "7.2.0", DB: 1, Pattern: "*", Created: time.Unix(1700000000, 0).UTC()}
+	bw, err := NewWriter(&buf, hdr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recs := []Record{
+		{Key: []byte("user:1"), TTL: 5000, Dump: []byte("\x00\x03abc\x0b\x00")},
+		{Key: []byte("bin\xff\x00key"), Dump: []byte("x")},
+	}
+	for _, rec := range recs {
+		if err := bw.Write(rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := bw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	br, err := NewReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("NewReader failed: %v", err)
+	}
+	if br.Header() != hdr {
+		t.Errorf("Header() = %+v, want %+v", br.Header(), hdr)
+	}
+	for i, want := range recs {
+		got, err := br.Next()
+		if err != nil {
+			t.Fatalf("Next() %d failed: %v", i, err)
+		}
+		if !bytes.Equal(got.Key, want.Key) || !bytes.Equal(got.Dump, want.Dump) || got.TTL != want.TTL {
+			t.Errorf("Next() %d = %+v, want %+v", i, got, want)
+		}
+	}
+	if _, err := br.Next(); err != io.EOF {
+		t.Errorf("Expected io.EOF at end, got %v", err)
+	}
+}
+
+func TestFileCorruption(t *testing.T) {
+	var buf bytes.Buffer
+	bw, _ := NewWriter(&buf, Header{Pattern: "*"})
+	bw.Write(Record{Key: []byte("a"), Dump: []byte("payload-a")})
+	bw.Write(Record{Key: []byte("b"), Dump: []byte("payload-b")})
+	bw.Close()
+	good := buf.String()
+
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "Tampered", content: strings.Replace(good, `"key":"YQ=="`, `"key":"Yg=="`, 1)},
+		{name: "Truncated", content: good[:strings.LastIndex(good, "T ")]},
+		{name: "Record Removed", content: removeLine(good, "R ")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			br, err := NewReader(strings.NewReader(tt.content))
+			if err != nil {
+				t.Fatalf("NewReader failed: %v", err)
+			}
+			for {
+				_, err = br.Next()
+				if err != nil {
+					break
+				}
+			}
+			if !errors.Is(err, ErrChecksum) {
+				t.Errorf("Expected ErrChecksum, got %v", err)
+			}
+		})
+	}
+
+	if _, err := NewReader(strings.NewReader("hello\n")); err == nil {
+		t.Error("Expected error for a file without the magic line")
+	}
+}
+
+func TestBackupRestore(t *testing.T) {
+	c, _, _ := newFakeServer(t, map[string]fakeKey{
+		"user:1":  {dump: "dump-1", pttl: 60000},
+		"user:2":  {dump: "dump-2", pttl: -1},
+		"session": {dump: "dump-s", pttl: -1},
+	})
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	path := filepath.Join(t.TempDir(), "backup.rmb")
+	var progress []int
+	n, err := BackupFile(c, path, BackupOptions{
+		BatchSize: 2,
+		Progress:  func(keys int) { progress = append(progress, keys) },
+		Now:       func() time.Time { return created },
+	})
+	if err != nil {
+		t.Fatalf("BackupFile failed: %v", err)
+	}
+	if n != 3 || !slices.Equal(progress, []int{2, 3}) {
+		t.Errorf("BackupFile() = %d keys, progress %v; want 3, [2 3]", n, progress)
+	}
+
+	hdr, keys, err := Verify(path)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if keys != 3 || hdr.ServerVersion != "7.2.0" || hdr.DB != 3 || !hdr.Created.Equal(created) {
+		t.Errorf("Verify() = %+v, %d keys", hdr, keys)
+	}
+
+	// Restore into an empty server, preserving TTLs.
+	target, _, restored := newFakeServer(t, map[string]fakeKey{})
+	_, result, err := RestoreFile(target, path, RestoreOptions{})
+	if err != nil {
+		t.Fatalf("RestoreFile failed: %v", err)
+	}
+	if result.Restored != 3 || result.Failed != 0 {
+		t.Errorf("RestoreFile() = %+v, want 3 restored", result)
+	}
+	got := restored()
+	if !slices.Equal(got["user:1"], []string{"60000", "dump-1"}) || !slices.Equal(got["user:2"], []string{"0", "dump-2"}) {
+		t.Errorf("Unexpected RESTORE arguments: %v", got)
+	}
+
+	// A second restore hits existing keys.
+	_, result, _ = RestoreFile(target, path, RestoreOptions{})
+	if result.Failed != 3 || len(result.Errors) != 3 {
+		t.Errorf("Restore over existing keys = %+v, want 3 failed", result)
+	}
+	_, result, _ = RestoreFile(target, path, RestoreOptions{SkipExisting: true})
+	if result.Skipped != 3 {
+		t.Errorf("Restore with SkipExisting = %+v, want 3 skipped", result)
+	}
+	_, result, _ = RestoreFile(target, path, RestoreOptions{Replace: true, TTLMode: TTLNone})
+	if result.Restored != 3 {
+		t.Errorf("Restore with Replace = %+v, want 3 restored", result)
+	}
+	if args := restored()["user:1"]; !slices.Equal(args, []string{"0", "dump-1", "REPLACE"}) {
+		t.Errorf("Restore with TTLNone args = %v", args)
+	}
+}
+
+func TestRestoreAbsoluteTTL(t *testing.T) {
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	bw, _ := NewWriter(&buf, Header{Created: created})
+	bw.Write(Record{Key: []byte("short"), TTL: 1000, Dump: []byte("d1")})
+	bw.Write(Record{Key: []byte("long"), TTL: 3600000, Dump: []byte("d2")})
+	bw.Write(Record{Key: []byte("forever"), Dump: []byte("d3")})
+	bw.Close()
+
+	c, _, restored := newFakeServer(t, map[string]fakeKey{})
+	br, _ := NewReader(&buf)
+	result, err := Restore(c, br, RestoreOptions{
+		TTLMode: TTLAbsolute,
+		Now:     func() time.Time { return created.Add(time.Minute) },
+	})
+	if err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if result.Restored != 2 || result.Skipped != 1 {
+		t.Errorf("Restore() = %+v, want 2 restored, 1 skipped", result)
+	}
+
+	got := restored()
+	wantExpire := "1767229200000" // 2026-01-01T01:00:00Z in ms
+	if !slices.Equal(got["long"], []string{wantExpire, "d2", "ABSTTL"}) {
+		t.Errorf("RESTORE long args = %v", got["long"])
+	}
+	if !slices.Equal(got["forever"], []string{"0", "d3"}) {
+		t.Errorf("RESTORE forever args = %v", got["forever"])
+	}
+}
+
+func TestRestoreFileRejectsCorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad.rmb")
+	c, s, _ := newFakeServer(t, map[string]fakeKey{"a": {dump: "x", pttl: -1}})
+	if _, err := BackupFile(c, path, BackupOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Drop the trailer.
+	data, _ := os.ReadFile(path)
+	writeFile(t, path, data[:bytes.LastIndex(data, []byte("T "))])
+
+	if _, _, err := RestoreFile(c, path, RestoreOptions{}); !errors.Is(err, ErrChecksum) {
+		t.Fatalf("Expected ErrChecksum, got %v", err)
+	}
+	for _, cmd := range s.Commands() {
+		if cmd[0] == "RESTORE" {
+			t.Fatal("RESTORE must not be sent for a corrupt file")
+		}
+	}
+}
+
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// removeLine removes the first line of s starting with prefix.
+func removeLine(s, prefix string) string {
+	lines := strings.SplitAfter(s, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, prefix) {
+			return strings.Join(append(lines[:i:i], lines[i+1:]...), "")
+		}
+	}
+	return s
+}
+
+func TestParseRestoreArgs(t *testing.T) {
+	path, opts, err := ParseRestoreArgs([]string{"b.rmb", "replace", "TTL", "Absolute"})
+	if err != nil {
+		t.Fatalf("ParseRestoreArgs failed: %v", err)
+	}
+	if path != "b.rmb" || !opts.Replace || opts.SkipExisting || opts.TTLMode != TTLAbsolute {
+		t.Errorf("ParseRestoreArgs() = %q, %+v", path, opts)
+	}
+
+	for _, args := range [][]string{nil, {"f", "TTL"}, {"f", "TTL", "later"}, {"f", "FORCE"}} {
+		if _, _, err := ParseRestoreArgs(args); err == nil {
+			t.Errorf("ParseRestoreArgs(%q) should fail", args)
+		}
+	}
+}
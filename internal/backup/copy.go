@@ -0,0 +1,314 @@
+package backup
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cosmez/redisman-go/internal/conn"
+	"github.com/cosmez/redisman-go/internal/resp"
+)
+
+// Copy modes.
+const (
+	CopyAuto    = "auto"    // dump unless the destination is older than the source or either version is unknown
+	CopyDump    = "dump"    // DUMP on the source, RESTORE on the destination
+	CopyLogical = "logical" // read values and rewrite them with SET, RPUSH, HSET, ...
+)
+
+// Conflict policies for keys that already exist on the destination.
+const (
+	ConflictFail    = "fail"    // leave the key alone and report it as failed
+	ConflictSkip    = "skip"    // leave the key alone and count it as skipped
+	ConflictReplace = "replace" // overwrite the key
+)
+
+// CopyOptions configures Copy.
+type CopyOptions struct {
+	Pattern  string                // SCAN MATCH pattern, "*" if empty
+	Mode     string                // CopyAuto (default), CopyDump or CopyLogical
+	Conflict string                // ConflictFail (default), ConflictSkip or ConflictReplace
+	DryRun   bool                  // only check the destination for conflicts
+	Rate     int                   // maximum keys per second, 0 for no limit
+	Progress func(done, total int) // called after each batch; total is estimated from DBSIZE, may be nil
+	Now      func() time.Time      // clock for rate limiting, time.Now if nil
+	Sleep    func(d time.Duration) // used for rate limiting, time.Sleep if nil
+}
+
+// CopyResult counts what Copy did. In a dry run the counts are what a real
+// copy would do: existing keys are counted as restored with
+// ConflictReplace, skipped with ConflictSkip and failed otherwise.
+type CopyResult struct {
+	RestoreResult
+	Mode  string // CopyDump or CopyLogical, as resolved from CopyAuto
+	Total int    // keys matched on the source
+}
+
+// ValidCopyMode reports whether mode is one of the copy modes.
+func ValidCopyMode(mode string) bool {
+	return mode == CopyAuto || mode == CopyDump || mode == CopyLogical
+}
+
+// ValidConflict reports whether policy is one of the conflict policies.
+func ValidConflict(policy string) bool {
+	return policy == ConflictFail || policy == ConflictSkip || policy == ConflictReplace
+}
+
+// Copy copies every key matching opts.Pattern from src to dst with its
+// TTL, DefaultBatchSize keys at a time (fewer when Rate is lower). Keys
+// deleted on the source while copying are not counted. Per-key failures do
+// not stop the copy; a SCAN or connection error does.
+func Copy(src, dst *conn.Connection, opts CopyOptions) (CopyResult, error) {
+	if opts.Pattern == "" {
+		opts.Pattern = "*"
+	}
+	if opts.Mode == "" {
+		opts.Mode = CopyAuto
+	}
+	if opts.Conflict == "" {
+		opts.Conflict = ConflictFail
+	}
+	if !ValidCopyMode(opts.Mode) {
+		return CopyResult{}, fmt.Errorf("invalid mode %q: use auto, dump or logical", opts.Mode)
+	}
+	if !ValidConflict(opts.Conflict) {
+		return CopyResult{}, fmt.Errorf("invalid conflict policy %q: use fail, skip or replace", opts.Conflict)
+	}
+	now, sleep := time.Now, time.Sleep
+	if opts.Now != nil {
+		now = opts.Now
+	}
+	if opts.Sleep != nil {
+		sleep = opts.Sleep
+	}
+
+	result := CopyResult{Mode: opts.Mode}
+	if result.Mode == CopyAuto {
+		// DUMP payloads are only readable by the same or a newer version.
+		result.Mode = CopyLogical
+		if versionAtLeast(dst.ServerInfo["redis_version"], src.ServerInfo["redis_version"]) {
+			result.Mode = CopyDump
+		}
+	}
+
+	batchSize := DefaultBatchSize
+	if opts.Rate > 0 && opts.Rate < batchSize {
+		batchSize = opts.Rate
+	}
+	// DBSIZE counts every key, not only the matching ones, so it is only an
+	// estimate of the progress total.
+	estimate := 0
+	if n, err := src.DBSize(); err == nil {
+		estimate = int(n)
+	}
+
+	start := now()
+	done := 0
+	batch := make([]string, 0, batchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if opts.Rate > 0 {
+			due := start.Add(time.Duration(done) * time.Second / time.Duration(opts.Rate))
+			if wait := due.Sub(now()); wait > 0 {
+				sleep(wait)
+			}
+		}
+
+		var err error
+		switch {
+		case opts.DryRun:
+			err = checkConflicts(dst, batch, opts.Conflict, &result.RestoreResult)
+		case result.Mode == CopyDump:
+			err = copyDumpBatch(src, dst, batch, opts.Conflict, &result.RestoreResult)
+		default:
+			err = copyLogicalBatch(src, dst, batch, opts.Conflict, &result.RestoreResult)
+		}
+		if err != nil {
+			return err
+		}
+
+		done += len(batch)
+		batch = batch[:0]
+		if opts.Progress != nil {
+			opts.Progress(done, max(estimate, done))
+		}
+		return nil
+	}
+
+	for key := range src.SafeKeys(opts.Pattern) {
+		if errResp, ok := key.(resp.RedisError); ok {
+			return result, fmt.Errorf("scan failed: %s", errResp.Value)
+		}
+		result.Total++
+		batch = append(batch, key.StringValue())
+		if len(batch) == batchSize {
+			if err := flush(); err != nil {
+				return result, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// checkConflicts counts what copying keys would do, based on which of them
+// already exist on dst.
+func checkConflicts(dst *conn.Connection, keys []string, conflict string, result *RestoreResult) error {
+	cmds := make([][]string, len(keys))
+	for i, key := range keys {
+		cmds[i] = []string{"EXISTS", key}
+	}
+	replies, err := dst.Pipeline(cmds)
+	if err != nil {
+		return err
+	}
+
+	for i, reply := range replies {
+		n, ok := reply.(resp.RedisInteger)
+		switch {
+		case !ok:
+			result.fail(keys[i], reply.StringValue())
+		case n.IntValue == 0, conflict == ConflictReplace:
+			result.Restored++
+		case conflict == ConflictSkip:
+			result.Skipped++
+		default:
+			result.fail(keys[i], "key already exists")
+		}
+	}
+	return nil
+}
+
+// copyDumpBatch DUMPs keys on src and RESTOREs them on dst with their
+// remaining TTL.
+func copyDumpBatch(src, dst *conn.Connection, keys []string, conflict string, result *RestoreResult) error {
+	recs, err := dumpBatch(src, keys)
+	if err != nil || len(recs) == 0 {
+		return err
+	}
+
+	cmds := make([][]string, len(recs))
+	for i, rec := range recs {
+		cmds[i] = []string{"RESTORE", string(rec.Key), strconv.FormatInt(rec.TTL, 10), string(rec.Dump)}
+		if conflict == ConflictReplace {
+			cmds[i] = append(cmds[i], "REPLACE")
+		}
+	}
+	replies, err := dst.Pipeline(cmds)
+	if err != nil {
+		return err
+	}
+	for i, reply := range replies {
+		result.tally(string(recs[i].Key), reply, conflict == ConflictSkip)
+	}
+	return nil
+}
+
+// copyLogicalBatch reads each key on src the way ExportKeys does and writes
+// it to dst the way ImportKeys does.
+func copyLogicalBatch(src, dst *conn.Connection, keys []string, conflict string, result *RestoreResult) error {
+	opts := ImportOptions{
+		Replace:      conflict == ConflictReplace,
+		SkipExisting: conflict == ConflictSkip,
+	}
+	for _, key := range keys {
+		entry, ok, err := exportEntry(src, key)
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		if !ok {
+			continue
+		}
+		cmds, err := importCommands(entry, opts)
+		if err != nil {
+			result.fail(key, err.Error())
+			continue
+		}
+		if err := importEntry(dst, cmds, opts, result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// versionAtLeast reports whether dotted version v is at least min. It is
+// false if either version is missing or unparsable.
+func versionAtLeast(v, min string) bool {
+	pv, okV := parseVersion(v)
+	pm, okM := parseVersion(min)
+	if !okV || !okM {
+		return false
+	}
+	for i := 0; i < max(len(pv), len(pm)); i++ {
+		var x, y int
+		if i < len(pv) {
+			x = pv[i]
+		}
+		if i < len(pm) {
+			y = pm[i]
+		}
+		if x != y {
+			return x > y
+		}
+	}
+	return true
+}
+
+func parseVersion(v string) ([]int, bool) {
+	if v == "" {
+		return nil, false
+	}
+	var parts []int
+	for _, s := range strings.Split(v, ".") {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, false
+		}
+		parts = append(parts, n)
+	}
+	return parts, true
+}
+
+// ParseCopyArgs parses the arguments of the COPY-KEYS built-in:
+// profile pattern [REPLACE|SKIPEXISTING] [DRYRUN] [RATE n] [MODE auto|dump|logical].
+func ParseCopyArgs(args []string) (string, CopyOptions, error) {
+	var opts CopyOptions
+	if len(args) < 2 {
+		return "", opts, fmt.Errorf("missing profile or pattern")
+	}
+	opts.Pattern = args[1]
+	for i := 2; i < len(args); i++ {
+		switch strings.ToUpper(args[i]) {
+		case "REPLACE":
+			opts.Conflict = ConflictReplace
+		case "SKIPEXISTING":
+			opts.Conflict = ConflictSkip
+		case "DRYRUN":
+			opts.DryRun = true
+		case "RATE":
+			if i+1 >= len(args) {
+				return "", opts, fmt.Errorf("RATE must be followed by keys per second")
+			}
+			n, err := strconv.Atoi(args[i+1])
+			if err != nil || n < 0 {
+				return "", opts, fmt.Errorf("RATE must be followed by keys per second")
+			}
+			opts.Rate = n
+			i++
+		case "MODE":
+			if i+1 >= len(args) || !ValidCopyMode(strings.ToLower(args[i+1])) {
+				return "", opts, fmt.Errorf("MODE must be followed by auto, dump or logical")
+			}
+			opts.Mode = strings.ToLower(args[i+1])
+			i++
+		default:
+			return "", opts, fmt.Errorf("unknown option %q", args[i])
+		}
+	}
+	return args[0], opts, nil
+}
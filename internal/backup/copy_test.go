@@ -0,0 +1,213 @@
+package backup
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/cosmez/redisman-go/internal/conn"
+	"github.com/cosmez/redisman-go/internal/redistest"
+)
+
+// newWriteServer returns a connection to a fake server that reports version
+// and accepts the writes of a logical copy. Keys in existing already exist.
+func newWriteServer(t *testing.T, version string, existing ...string) (*conn.Connection, *redistest.Server) {
+	t.Helper()
+	s := redistest.NewServer(t)
+	s.Handle("INFO", func(args []string) string {
+		return redistest.Bulk("# Server\r\nredis_version:" + version + "\r\n")
+	})
+	s.Handle("EXISTS", func(args []string) string {
+		if slices.Contains(existing, args[1]) {
+			return redistest.Int(1)
+		}
+		return redistest.Int(0)
+	})
+	for _, name := range []string{"SET", "XADD"} {
+		s.Handle(name, func(args []string) string { return redistest.Simple("OK") })
+	}
+	for _, name := range []string{"DEL", "RPUSH", "SADD", "ZADD", "HSET", "PEXPIRE"} {
+		s.Handle(name, func(args []string) string { return redistest.Int(1) })
+	}
+
+	c, err := conn.Connect(s.Host, s.Port, "", "")
+	if err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c, s
+}
+
+func TestCopyDump(t *testing.T) {
+	src, _, _ := newFakeServer(t, map[string]fakeKey{
+		"a": {dump: "payload-a", pttl: 5000},
+		"b": {dump: "payload-b", pttl: -1},
+	})
+
+	tests := []struct {
+		conflict     string
+		wantRestored int
+		wantSkipped  int
+		wantFailed   int
+	}{
+		{conflict: ConflictFail, wantRestored: 1, wantFailed: 1},
+		{conflict: ConflictSkip, wantRestored: 1, wantSkipped: 1},
+		{conflict: ConflictReplace, wantRestored: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.conflict, func(t *testing.T) {
+			dst, _, restored := newFakeServer(t, map[string]fakeKey{"b": {dump: "old"}})
+
+			var progress []int
+			result, err := Copy(src, dst, CopyOptions{
+				Conflict: tt.conflict,
+				Progress: func(done, total int) { progress = append(progress, done, total) },
+			})
+			if err != nil {
+				t.Fatalf("Copy failed: %v", err)
+			}
+			if result.Mode != CopyDump {
+				t.Errorf("Mode = %q, want %q", result.Mode, CopyDump)
+			}
+			if result.Total != 2 || result.Restored != tt.wantRestored || result.Skipped != tt.wantSkipped || result.Failed != tt.wantFailed {
+				t.Errorf("Copy() = %+v", result)
+			}
+			if got := restored()["a"]; len(got) < 2 || !slices.Equal(got[:2], []string{"5000", "payload-a"}) {
+				t.Errorf("RESTORE a args = %q", got)
+			}
+			if !slices.Equal(progress, []int{2, 2}) {
+				t.Errorf("Progress calls = %v, want [2 2]", progress)
+			}
+		})
+	}
+}
+
+func TestCopyLogical(t *testing.T) {
+	src := newLogicalServer(t) // reports 7.2.0
+	dst, s := newWriteServer(t, "6.2.14", "h")
+
+	result, err := Copy(src, dst, CopyOptions{Conflict: ConflictSkip})
+	if err != nil {
+		t.Fatalf("Copy failed: %v", err)
+	}
+	if result.Mode != CopyLogical {
+		t.Errorf("Mode = %q, want %q for an older destination", result.Mode, CopyLogical)
+	}
+	if result.Total != 8 || result.Restored != 6 || result.Skipped != 1 || result.Failed != 0 {
+		t.Errorf("Copy() = %+v", result)
+	}
+
+	var writes []string
+	for _, cmd := range s.Commands() {
+		if cmd[0] != "INFO" && cmd[0] != "EXISTS" {
+			writes = append(writes, fmt.Sprint(cmd))
+		}
+	}
+	want := []string{
+		"[SET s hello]", "[PEXPIRE s 5000]",
+		"[SET bin \xff\x00]",
+		"[RPUSH l a b a]",
+		"[SADD st m1]",
+		"[ZADD z -inf low 1.5 mid]",
+		"[XADD x 1-0 temp 20 hum 5 temp 21]",
+	}
+	if !slices.Equal(writes, want) {
+		t.Errorf("Writes = %q, want %q", writes, want)
+	}
+}
+
+func TestCopyDryRun(t *testing.T) {
+	src, _, _ := newFakeServer(t, map[string]fakeKey{
+		"a": {dump: "payload-a"},
+		"b": {dump: "payload-b"},
+		"c": {dump: "payload-c"},
+	})
+	dst, s := newWriteServer(t, "7.2.0", "b")
+
+	result, err := Copy(src, dst, CopyOptions{DryRun: true, Conflict: ConflictFail})
+	if err != nil {
+		t.Fatalf("Copy failed: %v", err)
+	}
+	if result.Restored != 2 || result.Failed != 1 || len(result.Errors) != 1 {
+		t.Errorf("Copy() = %+v", result)
+	}
+	for _, cmd := range s.Commands() {
+		if cmd[0] != "INFO" && cmd[0] != "EXISTS" {
+			t.Errorf("Dry run sent %q", cmd)
+		}
+	}
+}
+
+func TestCopyRate(t *testing.T) {
+	keys := map[string]fakeKey{}
+	for i := range 25 {
+		keys[fmt.Sprintf("k%02d", i)] = fakeKey{dump: "x"}
+	}
+	src, _, _ := newFakeServer(t, keys)
+	dst, _ := newWriteServer(t, "7.2.0")
+
+	clock := time.Unix(1700000000, 0)
+	var slept time.Duration
+	var batches []int
+	_, err := Copy(src, dst, CopyOptions{
+		DryRun:   true,
+		Rate:     10,
+		Now:      func() time.Time { return clock },
+		Sleep:    func(d time.Duration) { slept += d; clock = clock.Add(d) },
+		Progress: func(done, total int) { batches = append(batches, done) },
+	})
+	if err != nil {
+		t.Fatalf("Copy failed: %v", err)
+	}
+	if !slices.Equal(batches, []int{10, 20, 25}) {
+		t.Errorf("Batches = %v, want [10 20 25]", batches)
+	}
+	if slept != 2*time.Second {
+		t.Errorf("Slept %v, want 2s for 25 keys at 10/s", slept)
+	}
+}
+
+func TestVersionAtLeast(t *testing.T) {
+	tests := []struct {
+		v, min string
+		want   bool
+	}{
+		{"7.2.0", "7.2.0", true},
+		{"7.2.4", "7.2", true},
+		{"7.10.0", "7.9.1", true},
+		{"6.2.14", "7.0.0", false},
+		{"", "7.0.0", false},
+		{"7.0.0", "unknown", false},
+	}
+	for _, tt := range tests {
+		if got := versionAtLeast(tt.v, tt.min); got != tt.want {
+			t.Errorf("versionAtLeast(%q, %q) = %v, want %v", tt.v, tt.min, got, tt.want)
+		}
+	}
+}
+
+func TestParseCopyArgs(t *testing.T) {
+	profile, opts, err := ParseCopyArgs([]string{"staging", "tenant:*", "skipexisting", "DRYRUN", "RATE", "500", "MODE", "logical"})
+	if err != nil {
+		t.Fatalf("ParseCopyArgs failed: %v", err)
+	}
+	want := CopyOptions{Pattern: "tenant:*", Conflict: ConflictSkip, DryRun: true, Rate: 500, Mode: CopyLogical}
+	if profile != "staging" || opts.Pattern != want.Pattern || opts.Conflict != want.Conflict ||
+		opts.DryRun != want.DryRun || opts.Rate != want.Rate || opts.Mode != want.Mode {
+		t.Errorf("ParseCopyArgs() = %q, %+v", profile, opts)
+	}
+
+	for _, args := range [][]string{
+		{"staging"},
+		{"staging", "*", "RATE"},
+		{"staging", "*", "RATE", "fast"},
+		{"staging", "*", "MODE", "rdb"},
+		{"staging", "*", "FORCE"},
+	} {
+		if _, _, err := ParseCopyArgs(args); err == nil {
+			t.Errorf("ParseCopyArgs(%q) expected error", args)
+		}
+	}
+}
@@ -0,0 +1,7 @@
+// Package backup snapshots keys with DUMP/PTTL into a portable file and
+// restores them with RESTORE, or exports them logically as JSON lines that
+// can be imported into any Redis version. Copy moves keys directly between
+// two servers using either approach.
+//
+// C#: No direct equivalent — the C# version had no backup support.
+package backup
@@ -0,0 +1,216 @@
+package backup
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Magic is the first line of every backup file; the number is the format version.
+const Magic = "REDISMAN-BACKUP 1"
+
+// Header describes where and when a backup was taken.
+type Header struct {
+	ServerVersion string    `json:"server_version"`
+	DB            int       `json:"db"`
+	Pattern       string    `json:"pattern"`
+	Created       time.Time `json:"created"`
+}
+
+// Record is one key: its DUMP payload and remaining time to live.
+// Key and Dump are binary-safe (base64 in the file).
+type Record struct {
+	Key  []byte `json:"key"`
+	TTL  int64  `json:"ttl_ms,omitempty"` // remaining TTL in milliseconds at backup time, 0 for none
+	Dump []byte `json:"dump"`
+}
+
+// trailer closes a backup file. Checksum covers every byte before the trailer line.
+type trailer struct {
+	Keys     int    `json:"keys"`
+	Checksum string `json:"checksum"`
+}
+
+// The file is line oriented: the magic line, the header as JSON, one JSON
+// record per key, then the trailer. Every line is a JSON object prefixed
+// with a one-letter tag so the trailer can't be mistaken for a record.
+const (
+	tagHeader  = "H "
+	tagRecord  = "R "
+	tagTrailer = "T "
+)
+
+// Writer writes a backup file.
+type Writer struct {
+	w    *bufio.Writer
+	sum  hash.Hash
+	keys int
+}
+
+// NewWriter writes the magic line and header to w.
+func NewWriter(w io.Writer, hdr Header) (*Writer, error) {
+	bw := &Writer{w: bufio.NewWriter(w), sum: sha256.New()}
+	if err := bw.writeLine(Magic, ""); err != nil {
+		return nil, err
+	}
+	if err := bw.writeJSON(tagHeader, hdr); err != nil {
+		return nil, err
+	}
+	return bw, nil
+}
+
+// Write appends one record.
+func (bw *Writer) Write(rec Record) error {
+	bw.keys++
+	return bw.writeJSON(tagRecord, rec)
+}
+
+// Close writes the trailer with the key count and checksum, and flushes.
+// It does not close the underlying writer.
+func (bw *Writer) Close() error {
+	t := trailer{Keys: bw.keys, Checksum: "sha256:" + hex.EncodeToString(bw.sum.Sum(nil))}
+	data, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	if _, err := bw.w.WriteString(tagTrailer + string(data) + "\n"); err != nil {
+		return err
+	}
+	return bw.w.Flush()
+}
+
+func (bw *Writer) writeJSON(tag string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return bw.writeLine(tag, string(data))
+}
+
+func (bw *Writer) writeLine(tag, body string) error {
+	line := tag + body + "\n"
+	bw.sum.Write([]byte(line))
+	_, err := bw.w.WriteString(line)
+	return err
+}
+
+// Reader reads a backup file written by Writer.
+type Reader struct {
+	r      *bufio.Reader
+	sum    hash.Hash
+	header Header
+	keys   int
+	done   bool
+}
+
+// ErrChecksum is returned when a backup file's content doesn't match its trailer.
+var ErrChecksum = errors.New("backup checksum mismatch")
+
+// NewReader reads the magic line and header from r.
+func NewReader(r io.Reader) (*Reader, error) {
+	br := &Reader{r: bufio.NewReaderSize(r, 64*1024), sum: sha256.New()}
+
+	line, err := br.readLine()
+	if err != nil || line != Magic {
+		return nil, fmt.Errorf("not a redisman backup file (expected %q)", Magic)
+	}
+
+	line, err = br.readLine()
+	if err != nil || !strings.HasPrefix(line, tagHeader) {
+		return nil, fmt.Errorf("backup header missing")
+	}
+	if err := json.Unmarshal([]byte(line[len(tagHeader):]), &br.header); err != nil {
+		return nil, fmt.Errorf("invalid backup header: %w", err)
+	}
+	return br, nil
+}
+
+// Header returns the file header.
+func (br *Reader) Header() Header {
+	return br.header
+}
+
+// Next returns the next record. At the end of the file it verifies the
+// trailer and returns io.EOF, or ErrChecksum if the file was altered or
+// truncated.
+func (br *Reader) Next() (Record, error) {
+	if br.done {
+		return Record{}, io.EOF
+	}
+
+	want := hex.EncodeToString(br.sum.Sum(nil)) // checksum before reading this line
+	line, err := br.readLine()
+	if err == io.EOF {
+		return Record{}, fmt.Errorf("%w: file is truncated", ErrChecksum)
+	}
+	if err != nil {
+		return Record{}, err
+	}
+
+	switch {
+	case strings.HasPrefix(line, tagRecord):
+		var rec Record
+		if err := json.Unmarshal([]byte(line[len(tagRecord):]), &rec); err != nil {
+			return Record{}, fmt.Errorf("invalid backup record %d: %w", br.keys+1, err)
+		}
+		br.keys++
+		return rec, nil
+
+	case strings.HasPrefix(line, tagTrailer):
+		var t trailer
+		if err := json.Unmarshal([]byte(line[len(tagTrailer):]), &t); err != nil {
+			return Record{}, fmt.Errorf("invalid backup trailer: %w", err)
+		}
+		if t.Checksum != "sha256:"+want || t.Keys != br.keys {
+			return Record{}, ErrChecksum
+		}
+		br.done = true
+		return Record{}, io.EOF
+	}
+	return Record{}, fmt.Errorf("unexpected line in backup file after %d records", br.keys)
+}
+
+// readLine reads one line, adding it to the running checksum.
+func (br *Reader) readLine() (string, error) {
+	line, err := br.r.ReadString('\n')
+	if err != nil {
+		if err == io.EOF && line != "" {
+			return "", io.ErrUnexpectedEOF
+		}
+		return "", err
+	}
+	br.sum.Write([]byte(line))
+	return strings.TrimSuffix(line, "\n"), nil
+}
+
+// Verify reads the whole file at path and checks its checksum, returning
+// the header and key count.
+func Verify(path string) (Header, int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Header{}, 0, err
+	}
+	defer f.Close()
+
+	br, err := NewReader(f)
+	if err != nil {
+		return Header{}, 0, err
+	}
+	for {
+		_, err := br.Next()
+		if err == io.EOF {
+			return br.header, br.keys, nil
+		}
+		if err != nil {
+			return br.header, br.keys, err
+		}
+	}
+}
@@ -0,0 +1,543 @@
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cosmez/redisman-go/internal/conn"
+	"github.com/cosmez/redisman-go/internal/resp"
+)
+
+// Entry is one key of a logical export, written as a JSON line. Unlike a
+// DUMP payload it is readable and works across Redis versions.
+//
+// Value depends on Type:
+//
+//	string  "text"
+//	list    ["a", "b"]                            (in order)
+//	set     ["a", "b"]
+//	zset    [{"member": "a", "score": 1.5}]       (score may be "inf" or "-inf")
+//	hash    {"field": "value"}
+//	stream  [{"id": "1-0", "fields": ["f", "v"]}]  (fields in order, names may repeat)
+type Entry struct {
+	Key      string          `json:"key"`
+	Type     string          `json:"type"`
+	TTL      int64           `json:"ttl_ms,omitempty"`   // remaining TTL in milliseconds, 0 for none
+	Encoding string          `json:"encoding,omitempty"` // EncodingBase64 when key and value strings are base64
+	Value    json.RawMessage `json:"value"`
+}
+
+// EncodingBase64 marks an Entry whose key, members, fields and values are
+// base64 because at least one of them is not valid UTF-8. Stream IDs and
+// scores are never encoded.
+const EncodingBase64 = "base64"
+
+type zsetMember struct {
+	Member string `json:"member"`
+	Score  any    `json:"score"` // json.Number, or "inf"/"-inf"
+}
+
+type streamEntry struct {
+	ID string `json:"id"`
+	// Fields are the entry's field names and values, flattened as
+	// [f1, v1, f2, v2, ...]. Stream entries keep their field order and may
+	// repeat a name, which a JSON object would lose.
+	Fields []string `json:"fields"`
+}
+
+// ExportOptions configures ExportKeys.
+type ExportOptions struct {
+	Pattern  string         // SCAN MATCH pattern, "*" if empty
+	Progress func(keys int) // called every DefaultBatchSize keys, may be nil
+}
+
+// ExportKeys writes every key matching opts.Pattern to w as a JSON line,
+// reading collections with the Safe* iterators. Keys that disappear while
+// exporting are skipped. It returns the number of keys written.
+func ExportKeys(c *conn.Connection, w io.Writer, opts ExportOptions) (int, error) {
+	if opts.Pattern == "" {
+		opts.Pattern = "*"
+	}
+	bw := bufio.NewWriter(w)
+
+	total := 0
+	for k := range c.SafeKeys(opts.Pattern) {
+		if errResp, ok := k.(resp.RedisError); ok {
+			return total, fmt.Errorf("scan failed: %s", errResp.Value)
+		}
+		key := k.StringValue()
+		entry, ok, err := exportEntry(c, key)
+		if err != nil {
+			return total, fmt.Errorf("%s: %w", key, err)
+		}
+		if !ok {
+			continue
+		}
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return total, fmt.Errorf("%s: %w", key, err)
+		}
+		bw.Write(line)
+		bw.WriteByte('\n')
+
+		total++
+		if opts.Progress != nil && total%DefaultBatchSize == 0 {
+			opts.Progress(total)
+		}
+	}
+	return total, bw.Flush()
+}
+
+// exportEntry reads one key. It returns false if the key no longer exists.
+func exportEntry(c *conn.Connection, key string) (Entry, bool, error) {
+	replies, err := c.Pipeline([][]string{{"TYPE", key}, {"PTTL", key}})
+	if err != nil {
+		return Entry{}, false, err
+	}
+	typeName := replies[0].StringValue()
+	entry := Entry{Key: key, Type: typeName}
+	if n, ok := replies[1].(resp.RedisInteger); ok && n.IntValue > 0 {
+		entry.TTL = n.IntValue
+	}
+
+	// Strings are gathered first so the whole entry can switch to base64
+	// if any of them is binary.
+	var strs []*string
+	var build func() any
+
+	switch typeName {
+	case "none":
+		return Entry{}, false, nil
+
+	case "string":
+		replies, err := c.Pipeline([][]string{{"GET", key}})
+		if err != nil {
+			return Entry{}, false, err
+		}
+		if b, ok := replies[0].(resp.RedisBulkString); !ok || b.Length == -1 {
+			return Entry{}, false, nil
+		}
+		s := replies[0].StringValue()
+		strs = append(strs, &s)
+		build = func() any { return s }
+
+	case "list", "set":
+		seq := c.SafeList(key)
+		if typeName == "set" {
+			seq = c.SafeSets(key)
+		}
+		items, err := collect(seq)
+		if err != nil {
+			return Entry{}, false, err
+		}
+		list := make([]string, len(items))
+		for i, item := range items {
+			list[i] = item.StringValue()
+		}
+		for i := range list {
+			strs = append(strs, &list[i])
+		}
+		build = func() any { return list }
+
+	case "zset":
+		items, err := collect(c.SafeSortedSets(key))
+		if err != nil {
+			return Entry{}, false, err
+		}
+		members := make([]zsetMember, 0, len(items)/2)
+		for i := 0; i+1 < len(items); i += 2 {
+			members = append(members, zsetMember{Member: items[i].StringValue(), Score: exportScore(items[i+1].StringValue())})
+		}
+		for i := range members {
+			strs = append(strs, &members[i].Member)
+		}
+		build = func() any { return members }
+
+	case "hash":
+		items, err := collect(c.SafeHash(key))
+		if err != nil {
+			return Entry{}, false, err
+		}
+		pairs := make([]string, 0, len(items)*2)
+		for _, item := range items {
+			pairs = append(pairs, flattenStrings(item)...)
+		}
+		for i := range pairs {
+			strs = append(strs, &pairs[i])
+		}
+		build = func() any { return pairsToMap(pairs) }
+
+	case "stream":
+		items, err := collect(c.SafeStream(key))
+		if err != nil {
+			return Entry{}, false, err
+		}
+		type rawEntry struct {
+			id     string
+			fields []string
+		}
+		raw := make([]rawEntry, 0, len(items))
+		for _, item := range items {
+			array, ok := item.(resp.RedisArray)
+			if !ok || len(array.Values) < 2 {
+				return Entry{}, false, fmt.Errorf("unexpected stream entry format")
+			}
+			raw = append(raw, rawEntry{id: array.Values[0].StringValue(), fields: flattenStrings(array.Values[1])})
+		}
+		for i := range raw {
+			for j := range raw[i].fields {
+				strs = append(strs, &raw[i].fields[j])
+			}
+		}
+		build = func() any {
+			out := make([]streamEntry, len(raw))
+			for i, e := range raw {
+				out[i] = streamEntry{ID: e.id, Fields: e.fields}
+			}
+			return out
+		}
+
+	default:
+		return Entry{}, false, fmt.Errorf("unsupported key type %q", typeName)
+	}
+
+	if !allValidUTF8(key, strs) {
+		entry.Encoding = EncodingBase64
+		entry.Key = base64.StdEncoding.EncodeToString([]byte(key))
+		for _, s := range strs {
+			*s = base64.StdEncoding.EncodeToString([]byte(*s))
+		}
+	}
+	entry.Value, err = json.Marshal(build())
+	return entry, err == nil, err
+}
+
+// collect drains a Safe* iterator, turning an error reply into an error.
+func collect(seq iter.Seq[resp.RedisValue]) ([]resp.RedisValue, error) {
+	var items []resp.RedisValue
+	for item := range seq {
+		if errResp, ok := item.(resp.RedisError); ok {
+			return nil, fmt.Errorf("%s", errResp.Value)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// flattenStrings returns the string values of an array reply.
+func flattenStrings(v resp.RedisValue) []string {
+	array, ok := v.(resp.RedisArray)
+	if !ok {
+		return []string{v.StringValue()}
+	}
+	out := make([]string, len(array.Values))
+	for i, elem := range array.Values {
+		out[i] = elem.StringValue()
+	}
+	return out
+}
+
+// pairsToMap turns [f1, v1, f2, v2, ...] into a map.
+func pairsToMap(pairs []string) map[string]string {
+	m := make(map[string]string, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		m[pairs[i]] = pairs[i+1]
+	}
+	return m
+}
+
+func allValidUTF8(key string, strs []*string) bool {
+	if !utf8.ValidString(key) {
+		return false
+	}
+	for _, s := range strs {
+		if !utf8.ValidString(*s) {
+			return false
+		}
+	}
+	return true
+}
+
+// exportScore returns a zset score as a JSON number, or as a string for
+// the infinities JSON can't represent.
+func exportScore(score string) any {
+	f, err := strconv.ParseFloat(score, 64)
+	if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+		return score
+	}
+	return json.Number(score)
+}
+
+// ImportOptions configures ImportKeys.
+type ImportOptions struct {
+	Replace      bool           // delete existing keys before writing them
+	SkipExisting bool           // count existing keys as skipped instead of failed
+	NoTTL        bool           // ignore exported TTLs
+	Progress     func(keys int) // called every DefaultBatchSize keys, may be nil
+}
+
+// importChunk is how many elements go into one RPUSH/SADD/ZADD/HSET.
+const importChunk = 100
+
+// ImportKeys reads JSON lines written by ExportKeys from r and recreates
+// each key with type-appropriate write commands (SET, RPUSH, SADD, ZADD,
+// HSET, XADD), then PEXPIRE. Commands for one key are pipelined. Per-key
+// failures are counted and do not stop the import; a malformed line or a
+// connection error does.
+func ImportKeys(c *conn.Connection, r io.Reader, opts ImportOptions) (RestoreResult, error) {
+	var result RestoreResult
+	br := bufio.NewReader(r)
+
+	for lineNo := 1; ; lineNo++ {
+		line, err := br.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			var entry Entry
+			dec := json.NewDecoder(bytes.NewReader(line))
+			dec.UseNumber()
+			if decErr := dec.Decode(&entry); decErr != nil {
+				return result, fmt.Errorf("line %d: %w", lineNo, decErr)
+			}
+
+			cmds, cmdErr := importCommands(entry, opts)
+			if cmdErr != nil {
+				return result, fmt.Errorf("line %d: %w", lineNo, cmdErr)
+			}
+			if ioErr := importEntry(c, cmds, opts, &result); ioErr != nil {
+				return result, ioErr
+			}
+
+			done := result.Restored + result.Skipped + result.Failed
+			if opts.Progress != nil && done%DefaultBatchSize == 0 {
+				opts.Progress(done)
+			}
+		}
+
+		if err == io.EOF {
+			return result, nil
+		}
+		if err != nil {
+			return result, err
+		}
+	}
+}
+
+// importEntry checks for an existing key, then sends the commands that
+// recreate it.
+func importEntry(c *conn.Connection, cmds [][]string, opts ImportOptions, result *RestoreResult) error {
+	key := cmds[0][1]
+
+	if opts.Replace {
+		cmds = append([][]string{{"DEL", key}}, cmds...)
+	} else {
+		replies, err := c.Pipeline([][]string{{"EXISTS", key}})
+		if err != nil {
+			return err
+		}
+		if n, ok := replies[0].(resp.RedisInteger); ok && n.IntValue > 0 {
+			if opts.SkipExisting {
+				result.Skipped++
+			} else {
+				result.fail(key, "key already exists")
+			}
+			return nil
+		}
+	}
+
+	replies, err := c.Pipeline(cmds)
+	if err != nil {
+		return err
+	}
+	for _, reply := range replies {
+		if errResp, ok := reply.(resp.RedisError); ok {
+			result.fail(key, errResp.Value)
+			return nil
+		}
+	}
+	result.Restored++
+	return nil
+}
+
+// importCommands builds the write commands for an entry. The first command
+// always names the key as its first argument.
+func importCommands(entry Entry, opts ImportOptions) ([][]string, error) {
+	decode := func(s string) (string, error) { return s, nil }
+	switch entry.Encoding {
+	case "":
+	case EncodingBase64:
+		decode = func(s string) (string, error) {
+			b, err := base64.StdEncoding.DecodeString(s)
+			return string(b), err
+		}
+	default:
+		return nil, fmt.Errorf("unknown encoding %q", entry.Encoding)
+	}
+
+	key, err := decode(entry.Key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key: %w", err)
+	}
+	decodeAll := func(strs []string) ([]string, error) {
+		out := make([]string, len(strs))
+		for i, s := range strs {
+			if out[i], err = decode(s); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	}
+	chunked := func(cmd string, args []string, step int) [][]string {
+		var cmds [][]string
+		for i := 0; i < len(args); i += step * importChunk {
+			end := min(i+step*importChunk, len(args))
+			cmds = append(cmds, append([]string{cmd, key}, args[i:end]...))
+		}
+		return cmds
+	}
+
+	var cmds [][]string
+	switch entry.Type {
+	case "string":
+		var s string
+		if err := json.Unmarshal(entry.Value, &s); err != nil {
+			return nil, fmt.Errorf("%s: invalid string value: %w", entry.Key, err)
+		}
+		if s, err = decode(s); err != nil {
+			return nil, err
+		}
+		cmds = [][]string{{"SET", key, s}}
+
+	case "list", "set":
+		var items []string
+		if err := json.Unmarshal(entry.Value, &items); err != nil {
+			return nil, fmt.Errorf("%s: invalid %s value: %w", entry.Key, entry.Type, err)
+		}
+		if items, err = decodeAll(items); err != nil {
+			return nil, err
+		}
+		cmd := "RPUSH"
+		if entry.Type == "set" {
+			cmd = "SADD"
+		}
+		cmds = chunked(cmd, items, 1)
+
+	case "zset":
+		var members []zsetMember
+		dec := json.NewDecoder(bytes.NewReader(entry.Value))
+		dec.UseNumber()
+		if err := dec.Decode(&members); err != nil {
+			return nil, fmt.Errorf("%s: invalid zset value: %w", entry.Key, err)
+		}
+		args := make([]string, 0, len(members)*2)
+		for _, m := range members {
+			member, err := decode(m.Member)
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, fmt.Sprint(m.Score), member)
+		}
+		cmds = chunked("ZADD", args, 2)
+
+	case "hash":
+		var fields map[string]string
+		if err := json.Unmarshal(entry.Value, &fields); err != nil {
+			return nil, fmt.Errorf("%s: invalid hash value: %w", entry.Key, err)
+		}
+		args := make([]string, 0, len(fields)*2)
+		for f, v := range fields {
+			pair, err := decodeAll([]string{f, v})
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, pair...)
+		}
+		cmds = chunked("HSET", args, 2)
+
+	case "stream":
+		var entries []streamEntry
+		if err := json.Unmarshal(entry.Value, &entries); err != nil {
+			return nil, fmt.Errorf("%s: invalid stream value: %w", entry.Key, err)
+		}
+		for _, e := range entries {
+			fields, err := decodeAll(e.Fields)
+			if err != nil {
+				return nil, err
+			}
+			cmds = append(cmds, append([]string{"XADD", key, e.ID}, fields...))
+		}
+		if len(entries) == 0 {
+			// An empty stream exists in Redis: add an entry and trim it away.
+			cmds = [][]string{{"XADD", key, "MAXLEN", "0", "*", "f", "v"}}
+		}
+
+	default:
+		return nil, fmt.Errorf("%s: unsupported type %q", entry.Key, entry.Type)
+	}
+
+	if len(cmds) == 0 {
+		return nil, fmt.Errorf("%s: empty %s value", entry.Key, entry.Type)
+	}
+	if entry.TTL > 0 && !opts.NoTTL {
+		cmds = append(cmds, []string{"PEXPIRE", key, strconv.FormatInt(entry.TTL, 10)})
+	}
+	return cmds, nil
+}
+
+// ExportKeysFile creates the file at path and exports into it. On error the
+// partial file is removed.
+func ExportKeysFile(c *conn.Connection, path string, opts ExportOptions) (int, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := ExportKeys(c, f, opts)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+	}
+	return n, err
+}
+
+// ImportKeysFile imports the JSON lines file at path.
+func ImportKeysFile(c *conn.Connection, path string, opts ImportOptions) (RestoreResult, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return RestoreResult{}, err
+	}
+	defer f.Close()
+	return ImportKeys(c, f, opts)
+}
+
+// ParseImportArgs parses the arguments of the IMPORT-KEYS built-in:
+// file [REPLACE] [SKIPEXISTING] [NOTTL].
+func ParseImportArgs(args []string) (string, ImportOptions, error) {
+	var opts ImportOptions
+	if len(args) == 0 {
+		return "", opts, fmt.Errorf("missing file")
+	}
+	for _, arg := range args[1:] {
+		switch strings.ToUpper(arg) {
+		case "REPLACE":
+			opts.Replace = true
+		case "SKIPEXISTING":
+			opts.SkipExisting = true
+		case "NOTTL":
+			opts.NoTTL = true
+		default:
+			return "", opts, fmt.Errorf("unknown option %q", arg)
+		}
+	}
+	return args[0], opts, nil
+}
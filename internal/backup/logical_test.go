@@ -0,0 +1,205 @@
+package backup
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/cosmez/redisman-go/internal/conn"
+	"github.com/cosmez/redisman-go/internal/redistest"
+)
+
+// newLogicalServer serves one key of each type for ExportKeys.
+func newLogicalServer(t *testing.T) *conn.Connection {
+	t.Helper()
+	types := map[string]string{
+		"s": "string", "bin": "string", "l": "list", "st": "set",
+		"z": "zset", "h": "hash", "x": "stream",
+	}
+
+	s := redistest.NewServer(t)
+	s.Handle("SCAN", func(args []string) string {
+		return redistest.Array(redistest.Bulk("0"), redistest.BulkArray("s", "bin", "l", "st", "z", "h", "x", "gone"))
+	})
+	s.Handle("TYPE", func(args []string) string {
+		if typ, ok := types[args[1]]; ok {
+			return redistest.Simple(typ)
+		}
+		return redistest.Simple("none")
+	})
+	s.Handle("PTTL", func(args []string) string {
+		if args[1] == "s" {
+			return redistest.Int(5000)
+		}
+		return redistest.Int(-1)
+	})
+	s.Handle("GET", func(args []string) string {
+		if args[1] == "bin" {
+			return redistest.Bulk("\xff\x00")
+		}
+		return redistest.Bulk("hello")
+	})
+	s.Handle("LRANGE", func(args []string) string {
+		if args[2] != "0" {
+			return redistest.Array()
+		}
+		return redistest.BulkArray("a", "b", "a")
+	})
+	s.Handle("SSCAN", func(args []string) string {
+		return redistest.Array(redistest.Bulk("0"), redistest.BulkArray("m1"))
+	})
+	s.Handle("ZSCAN", func(args []string) string {
+		return redistest.Array(redistest.Bulk("0"), redistest.BulkArray("low", "-inf", "mid", "1.5"))
+	})
+	s.Handle("HSCAN", func(args []string) string {
+		return redistest.Array(redistest.Bulk("0"), redistest.BulkArray("f", "v"))
+	})
+	s.Handle("XRANGE", func(args []string) string {
+		if args[2] != "-" {
+			return redistest.Array()
+		}
+		return redistest.Array(redistest.Array(redistest.Bulk("1-0"), redistest.BulkArray("temp", "20", "hum", "5", "temp", "21")))
+	})
+
+	c, err := conn.Connect(s.Host, s.Port, "", "")
+	if err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestExportKeys(t *testing.T) {
+	c := newLogicalServer(t)
+
+	var buf bytes.Buffer
+	n, err := ExportKeys(c, &buf, ExportOptions{})
+	if err != nil {
+		t.Fatalf("ExportKeys failed: %v", err)
+	}
+	if n != 7 {
+		t.Errorf("ExportKeys() = %d keys, want 7", n)
+	}
+
+	want := []string{
+		`{"key":"s","type":"string","ttl_ms":5000,"value":"hello"}`,
+		`{"key":"Ymlu","type":"string","encoding":"base64","value":"/wA="}`,
+		`{"key":"l","type":"list","value":["a","b","a"]}`,
+		`{"key":"st","type":"set","value":["m1"]}`,
+		`{"key":"z","type":"zset","value":[{"member":"low","score":"-inf"},{"member":"mid","score":1.5}]}`,
+		`{"key":"h","type":"hash","value":{"f":"v"}}`,
+		`{"key":"x","type":"stream","value":[{"id":"1-0","fields":["temp","20","hum","5","temp","21"]}]}`,
+	}
+	got := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if !slices.Equal(got, want) {
+		t.Errorf("ExportKeys() lines:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestImportKeys(t *testing.T) {
+	input := strings.Join([]string{
+		`{"key":"s","type":"string","ttl_ms":5000,"value":"hello"}`,
+		`{"key":"/wA=","type":"string","encoding":"base64","value":"/wA="}`,
+		`{"key":"l","type":"list","value":["a","b","a"]}`,
+		`{"key":"z","type":"zset","value":[{"member":"low","score":"-inf"},{"member":"mid","score":1.5}]}`,
+		`{"key":"h","type":"hash","value":{"f":"v"}}`,
+		`{"key":"x","type":"stream","value":[{"id":"1-0","fields":["temp","20","hum","5","temp","21"]}]}`,
+		`{"key":"empty","type":"stream","value":[]}`,
+		``,
+		`{"key":"taken","type":"set","value":["m"]}`,
+	}, "\n")
+
+	s := redistest.NewServer(t)
+	s.Handle("EXISTS", func(args []string) string {
+		if args[1] == "taken" {
+			return redistest.Int(1)
+		}
+		return redistest.Int(0)
+	})
+	for _, name := range []string{"SET", "XADD"} {
+		s.Handle(name, func(args []string) string { return redistest.Simple("OK") })
+	}
+	for _, name := range []string{"RPUSH", "SADD", "ZADD", "HSET", "PEXPIRE", "DEL"} {
+		s.Handle(name, func(args []string) string { return redistest.Int(1) })
+	}
+	c, err := conn.Connect(s.Host, s.Port, "", "")
+	if err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer c.Close()
+
+	result, err := ImportKeys(c, strings.NewReader(input), ImportOptions{SkipExisting: true})
+	if err != nil {
+		t.Fatalf("ImportKeys failed: %v", err)
+	}
+	if result.Restored != 7 || result.Skipped != 1 || result.Failed != 0 {
+		t.Errorf("ImportKeys() = %+v, want 7 restored, 1 skipped", result)
+	}
+
+	var writes []string
+	for _, cmd := range s.Commands() {
+		if cmd[0] != "EXISTS" && cmd[0] != "INFO" {
+			writes = append(writes, strings.Join(cmd, " "))
+		}
+	}
+	want := []string{
+		"SET s hello",
+		"PEXPIRE s 5000",
+		"SET \xff\x00 \xff\x00",
+		"RPUSH l a b a",
+		"ZADD z -inf low 1.5 mid",
+		"HSET h f v",
+		"XADD x 1-0 temp 20 hum 5 temp 21",
+		"XADD empty MAXLEN 0 * f v",
+	}
+	if !slices.Equal(writes, want) {
+		t.Errorf("Write commands = %q, want %q", writes, want)
+	}
+}
+
+func TestImportKeysReplace(t *testing.T) {
+	s := redistest.NewServer(t)
+	s.Handle("DEL", func(args []string) string { return redistest.Int(1) })
+	s.Handle("SADD", func(args []string) string { return redistest.Err("WRONGTYPE nope") })
+	c, err := conn.Connect(s.Host, s.Port, "", "")
+	if err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer c.Close()
+
+	result, err := ImportKeys(c, strings.NewReader(`{"key":"k","type":"set","ttl_ms":10,"value":["m"]}`), ImportOptions{Replace: true, NoTTL: true})
+	if err != nil {
+		t.Fatalf("ImportKeys failed: %v", err)
+	}
+	if result.Failed != 1 || len(result.Errors) != 1 {
+		t.Errorf("ImportKeys() = %+v, want 1 failure", result)
+	}
+	cmds := s.Commands()
+	last := cmds[len(cmds)-1]
+	if cmds[len(cmds)-2][0] != "DEL" || last[0] != "SADD" {
+		t.Errorf("Expected DEL then SADD without PEXPIRE, got %q", cmds)
+	}
+}
+
+func TestImportKeysBadLine(t *testing.T) {
+	c, _, _ := newFakeServer(t, map[string]fakeKey{})
+	for _, input := range []string{`{"key":`, `{"key":"k","type":"blob","value":1}`, `{"key":"k","type":"list","value":[]}`} {
+		if _, err := ImportKeys(c, strings.NewReader(input), ImportOptions{}); err == nil {
+			t.Errorf("ImportKeys(%q) should fail", input)
+		}
+	}
+}
+
+func TestEntryRoundTrip(t *testing.T) {
+	// Scores survive as exact JSON numbers.
+	entry := Entry{Key: "z", Type: "zset", Value: json.RawMessage(`[{"member":"a","score":0.1000000000000000055511151231257827}]`)}
+	cmds, err := importCommands(entry, ImportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmds[0][2] != "0.1000000000000000055511151231257827" {
+		t.Errorf("Score = %q, want the exact JSON number", cmds[0][2])
+	}
+}
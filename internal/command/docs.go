@@ -40,6 +40,22 @@ func NewRegistry() (*Registry, error) {
 		{Command: "SAFEKEYS", Summary: "Safely iterate over keys using SCAN", Arguments: "[pattern]", Group: "application"},
 		{Command: "VIEW", Summary: "View the contents of a key", Arguments: "key", Group: "application"},
 		{Command: "EXPORT", Summary: "Export the result of a command to a file", Arguments: "file command [args...]", Group: "application"},
+		{Command: "BACKUP", Summary: "Back up keys matching a pattern to a file with DUMP", Arguments: "pattern file", Group: "application"},
+		{Command: "RESTORE-FILE", Summary: "Restore keys from a BACKUP file", Arguments: "file [REPLACE] [SKIPEXISTING] [TTL keep|absolute|none]", Group: "application"},
+		{Command: "EXPORT-KEYS", Summary: "Export keys matching a pattern as JSON lines", Arguments: "pattern file", Group: "application"},
+		{Command: "IMPORT-KEYS", Summary: "Import keys from an EXPORT-KEYS file", Arguments: "file [REPLACE] [SKIPEXISTING] [NOTTL]", Group: "application"},
+		{Command: "COPY-KEYS", Summary: "Copy keys matching a pattern to another server", Arguments: "profile pattern [REPLACE|SKIPEXISTING] [DRYRUN] [RATE n] [MODE auto|dump|logical]", Group: "application"},
+		{Command: "DELPATTERN", Summary: "Delete keys matching a pattern after a preview and confirmation", Arguments: "pattern [RATE n]", Group: "application"},
+		{Command: "EXPIREPATTERN", Summary: "Set a TTL on keys matching a pattern after a preview and confirmation", Arguments: "pattern seconds [RATE n]", Group: "application"},
+		{Command: "ANALYZE", Summary: "Report memory by type, the biggest keys and a size histogram", Arguments: "[pattern] [TOP n] [SAMPLE fraction] [LIMIT n] [JSON]", Group: "application"},
+		{Command: "PREFIXES", Summary: "Report key count, memory and TTL coverage grouped by key prefix", Arguments: "[pattern] [DEPTH n] [DELIM delimiter] [JSON]", Group: "application"},
+		{Command: "RENAME-KEY", Summary: "Rename a key after a preview; refuses to overwrite unless REPLACE is given", Arguments: "key newname [REPLACE]", Group: "application"},
+		{Command: "DUPLICATE-KEY", Summary: "Copy a key to a new name, optionally in another database, after a preview", Arguments: "key dest [DB n] [REPLACE]", Group: "application"},
+		{Command: "MOVE-KEY", Summary: "Move a key to another database after a preview", Arguments: "key db", Group: "application"},
+		{Command: "DASHBOARD", Summary: "Show a live server dashboard in the TUI: ops/sec, memory, hit ratio, clients, replication, persistence and keyspace", Arguments: "[seconds]", Group: "application"},
+		{Command: "WATCHKEYS", Summary: "Show a live feed of key changes from keyspace notifications, enabling them if the server allows", Arguments: "[pattern] [EVENT name[,name...]]", Group: "application"},
+		{Command: "FORMAT", Summary: "Show or set the file format used by > and >> redirects", Arguments: "[text|json|csv]", Group: "application"},
+		{Command: "PAGING", Summary: "Show or set how long results are paged", Arguments: "[pager|prompt|none] [page-size]", Group: "application"},
 	}
 	docs = append(docs, appCommands...)
 
@@ -0,0 +1,25 @@
+package command
+
+// valueReadCommands lists commands whose first argument is a key and whose
+// reply carries that key's stored values (as opposed to lengths, scores
+// only, TTLs, etc.). Codec rules are applied to these replies.
+var valueReadCommands = map[string]bool{
+	"GET": true, "GETDEL": true, "GETEX": true, "GETSET": true,
+	"HGET": true, "HMGET": true, "HGETALL": true, "HVALS": true, "HRANDFIELD": true,
+	"LINDEX": true, "LRANGE": true, "LPOP": true, "RPOP": true,
+	"SMEMBERS": true, "SRANDMEMBER": true, "SPOP": true,
+	"ZRANGE": true, "ZRANGEBYSCORE": true, "ZREVRANGE": true, "ZREVRANGEBYSCORE": true,
+	"ZRANGEBYLEX": true, "ZREVRANGEBYLEX": true, "ZRANDMEMBER": true,
+	"ZPOPMIN": true, "ZPOPMAX": true,
+}
+
+// ValueKey returns the key whose stored values appear in the reply to parsed,
+// or "" if the command does not read values from a single key.
+//
+// C#: No direct equivalent — used to apply codec rules by key pattern.
+func ValueKey(parsed *ParsedCommand) string {
+	if parsed == nil || len(parsed.Args) == 0 || !valueReadCommands[parsed.Name] {
+		return ""
+	}
+	return parsed.Args[0]
+}
@@ -25,33 +25,67 @@ func Parse(input string, reg *Registry) (*ParsedCommand, error) {
 		Text: input,
 	}
 
+	// Separators are only recognized outside double quotes, so values such as
+	// `SET k "a | b"` or `SET k "x#:y"` are left intact.
+
+	// 0. Detect and strip `|> filter` suffix. A jq-style filter may contain
+	// "|" itself, so it runs to the end of the line and is stripped before
+	// looking for a shell pipe. Filters have no ">" operator, so a trailing
+	// `> file` or `>> file` is the redirect of the filter's results.
+	if filterIdx := indexUnquoted(input, " |>"); filterIdx != -1 {
+		filter := input[filterIdx+3:]
+		if target, appendMode, idx := splitRedirect(filter); idx != -1 {
+			parsed.Redirect = target
+			parsed.Append = appendMode
+			filter = filter[:idx]
+		}
+		parsed.Filter = strings.TrimSpace(filter)
+		input = input[:filterIdx]
+	}
+
 	// 1. Detect and strip `| shell cmd` suffix
 	// We do this FIRST to avoid extracting `gzip | jq .` as a codec name
 	// if the user types `GET key #:gzip | jq .`
-	if pipeIdx := strings.Index(input, " | "); pipeIdx != -1 {
+	// Everything after the first pipe belongs to the shell, including further
+	// pipes, quotes and redirections (`| grep x | wc -l`).
+	if pipeIdx := indexUnquoted(input, " | "); pipeIdx != -1 {
 		parsed.Pipe = strings.TrimSpace(input[pipeIdx+3:])
 		input = input[:pipeIdx]
 	}
 
-	// 2. Detect and strip `#:codec` suffix
-	if codecIdx := strings.LastIndex(input, "#:"); codecIdx != -1 {
+	// 2. Detect and strip `> file` / `>> file` suffix. ">" is also a stream
+	// ID for XREADGROUP, so see findRedirect for when it is left alone.
+	if target, appendMode, idx := findRedirect(input); idx != -1 {
+		if parsed.Redirect != "" {
+			return nil, fmt.Errorf("cannot redirect to both %s and %s", target, parsed.Redirect)
+		}
+		parsed.Redirect = target
+		parsed.Append = appendMode
+		input = input[:idx]
+		if parsed.Pipe != "" {
+			return nil, fmt.Errorf("cannot redirect and pipe the same output; redirect inside the pipe instead (| cmd > %s)", target)
+		}
+	}
+
+	// 3. Detect and strip `#:codec` suffix
+	if codecIdx := lastIndexUnquoted(input, "#:"); codecIdx != -1 {
 		parsed.Modifier = strings.TrimSpace(input[codecIdx+2:])
 		input = input[:codecIdx]
 	}
 
-	// 3. Tokenize remaining text
+	// 4. Tokenize remaining text
 	tokens := tokenize(input)
 	if len(tokens) == 0 {
 		return parsed, nil
 	}
 
-	// 4. Extract Name and Args
+	// 5. Extract Name and Args
 	parsed.Name = strings.ToUpper(tokens[0])
 	if len(tokens) > 1 {
 		parsed.Args = tokens[1:]
 	}
 
-	// 5. Look up documentation
+	// 6. Look up documentation
 	if reg != nil {
 		// Try exact match first
 		parsed.Doc = reg.Get(parsed.Name)
@@ -64,7 +98,7 @@ func Parse(input string, reg *Registry) (*ParsedCommand, error) {
 		}
 	}
 
-	// 6. Build RESP bytes
+	// 7. Build RESP bytes
 	var buf bytes.Buffer
 	// Array header: *N\r\n
 	buf.WriteString(fmt.Sprintf("*%d\r\n", len(tokens)))
@@ -72,8 +106,10 @@ func Parse(input string, reg *Registry) (*ParsedCommand, error) {
 	for i, token := range tokens {
 		tokenBytes := []byte(token)
 
-		// Special case: Serialize the value argument of SET if a modifier is present
-		if parsed.Name == "SET" && i == 2 && parsed.Modifier != "" {
+		// Special case: Serialize the value argument of SET, or the message of
+		// PUBLISH and SPUBLISH, if a modifier is present (`#:raw` means "no
+		// codec", so it is sent as typed).
+		if encodedCommands[parsed.Name] && i == 2 && parsed.Modifier != "" && !strings.EqualFold(parsed.Modifier, serializer.RawModifier) {
 			codec, err := serializer.Get(parsed.Modifier)
 			if err != nil {
 				return nil, fmt.Errorf("failed to get serializer %q: %w", parsed.Modifier, err)
@@ -96,3 +132,86 @@ func Parse(input string, reg *Registry) (*ParsedCommand, error) {
 
 	return parsed, nil
 }
+
+// encodedCommands have their third token (SET's value, PUBLISH's message)
+// encoded with the `#:codec` modifier.
+var encodedCommands = map[string]bool{"SET": true, "PUBLISH": true, "SPUBLISH": true}
+
+// findRedirect locates a trailing `> file` or `>> file` outside quotes and
+// returns the file name, whether it appends, and the index where the
+// redirect starts (-1 if there is none). The file name may be quoted.
+//
+// XREAD and XREADGROUP end with stream IDs, where ">" means "new messages",
+// so `XREADGROUP ... STREAMS s1 s2 > >` or `... > 0-0` are not redirects: for
+// them only `>>` redirects. A `>` followed by ">" or a stream ID is not a
+// redirect for any command.
+func findRedirect(input string) (string, bool, int) {
+	target, appendMode, idx := splitRedirect(input)
+	if idx == -1 || appendMode {
+		return target, appendMode, idx
+	}
+	if isStreamID(target) {
+		return "", false, -1
+	}
+	if name := tokenize(input[:idx]); len(name) > 0 && readsStreams(name[0]) {
+		return "", false, -1
+	}
+	return target, appendMode, idx
+}
+
+// splitRedirect locates the last `> file` or `>> file` outside quotes that
+// is followed by exactly one (possibly quoted) word, and returns that word,
+// whether it appends, and the index where the redirect starts (-1 if there
+// is none).
+func splitRedirect(input string) (string, bool, int) {
+	idx, appendMode := lastIndexUnquoted(input, " > "), false
+	if appendIdx := lastIndexUnquoted(input, " >> "); appendIdx > idx {
+		idx, appendMode = appendIdx, true
+	}
+	if idx == -1 {
+		return "", false, -1
+	}
+
+	opLen := 3
+	if appendMode {
+		opLen = 4
+	}
+	targets := tokenize(input[idx+opLen:])
+	if len(targets) != 1 {
+		return "", false, -1
+	}
+	return targets[0], appendMode, idx
+}
+
+// readsStreams reports whether name is a command whose last arguments are
+// stream IDs.
+func readsStreams(name string) bool {
+	return strings.EqualFold(name, "XREAD") || strings.EqualFold(name, "XREADGROUP")
+}
+
+// isStreamID reports whether s looks like a stream ID: ">", "$", "+", "-",
+// or <ms> / <ms>-<seq> / <ms>-*.
+func isStreamID(s string) bool {
+	switch s {
+	case ">", "$", "+", "-":
+		return true
+	}
+	ms, seq, hasSeq := strings.Cut(s, "-")
+	if !isDigits(ms) {
+		return false
+	}
+	return !hasSeq || seq == "*" || isDigits(seq)
+}
+
+// isDigits reports whether s is a non-empty run of ASCII digits.
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
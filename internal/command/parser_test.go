@@ -62,6 +62,9 @@ func TestParse(t *testing.T) {
 		expectedArgs []string
 		expectedMod  string
 		expectedPipe string
+		expectedFilt string
+		expectedFile string
+		expectedApp  bool
 		expectedRESP []byte
 		wantErr      bool
 	}{
@@ -97,6 +100,118 @@ func TestParse(t *testing.T) {
 			expectedPipe: "jq .",
 			expectedRESP: []byte("*2\r\n$3\r\nGET\r\n$5\r\nmykey\r\n"),
 		},
+		{
+			name:         "With Filter",
+			input:        "GET mykey#:gzip |> .items[] | .id",
+			expectedName: "GET",
+			expectedArgs: []string{"mykey"},
+			expectedMod:  "gzip",
+			expectedFilt: ".items[] | .id",
+			expectedRESP: []byte("*2\r\n$3\r\nGET\r\n$5\r\nmykey\r\n"),
+		},
+		{
+			name:         "Filter Then Redirect",
+			input:        "GET k |> .a > out.json",
+			expectedName: "GET",
+			expectedArgs: []string{"k"},
+			expectedFilt: ".a",
+			expectedFile: "out.json",
+			expectedRESP: []byte("*2\r\n$3\r\nGET\r\n$1\r\nk\r\n"),
+		},
+		{
+			name:         "Filter Then Append To Numbered File",
+			input:        `GET k |> .items[] | .id >> "ids 1.txt"`,
+			expectedName: "GET",
+			expectedArgs: []string{"k"},
+			expectedFilt: ".items[] | .id",
+			expectedFile: "ids 1.txt",
+			expectedApp:  true,
+			expectedRESP: []byte("*2\r\n$3\r\nGET\r\n$1\r\nk\r\n"),
+		},
+		{
+			name:         "Redirect Then Filter",
+			input:        "GET k > out.json |> .a",
+			expectedName: "GET",
+			expectedArgs: []string{"k"},
+			expectedFilt: ".a",
+			expectedFile: "out.json",
+			expectedRESP: []byte("*2\r\n$3\r\nGET\r\n$1\r\nk\r\n"),
+		},
+		{
+			name:    "Two Redirects",
+			input:   "GET k > a.json |> .a > b.json",
+			wantErr: true,
+		},
+		{
+			name:         "Chained Shell Pipe",
+			input:        `GET mykey | jq '.items[] | .id' | wc -l`,
+			expectedName: "GET",
+			expectedArgs: []string{"mykey"},
+			expectedPipe: `jq '.items[] | .id' | wc -l`,
+			expectedRESP: []byte("*2\r\n$3\r\nGET\r\n$5\r\nmykey\r\n"),
+		},
+		{
+			name:         "Pipe Inside Quotes",
+			input:        `SET k "a | b #:c"`,
+			expectedName: "SET",
+			expectedArgs: []string{"k", "a | b #:c"},
+			expectedRESP: []byte("*3\r\n$3\r\nSET\r\n$1\r\nk\r\n$9\r\na | b #:c\r\n"),
+		},
+		{
+			name:         "With Redirect",
+			input:        "SAFEKEYS user:* > keys.txt",
+			expectedName: "SAFEKEYS",
+			expectedArgs: []string{"user:*"},
+			expectedFile: "keys.txt",
+			expectedRESP: []byte("*2\r\n$8\r\nSAFEKEYS\r\n$6\r\nuser:*\r\n"),
+		},
+		{
+			name:         "With Codec and Append",
+			input:        `GET mykey#:gzip >> "my out.json"`,
+			expectedName: "GET",
+			expectedArgs: []string{"mykey"},
+			expectedMod:  "gzip",
+			expectedFile: "my out.json",
+			expectedApp:  true,
+			expectedRESP: []byte("*2\r\n$3\r\nGET\r\n$5\r\nmykey\r\n"),
+		},
+		{
+			name:         "Stream ID Is Not A Redirect",
+			input:        "XREADGROUP GROUP g c STREAMS s >",
+			expectedName: "XREADGROUP",
+			expectedArgs: []string{"GROUP", "g", "c", "STREAMS", "s", ">"},
+			expectedRESP: []byte("*7\r\n$10\r\nXREADGROUP\r\n$5\r\nGROUP\r\n$1\r\ng\r\n$1\r\nc\r\n$7\r\nSTREAMS\r\n$1\r\ns\r\n$1\r\n>\r\n"),
+		},
+		{
+			name:         "Multi-Stream IDs Are Not A Redirect",
+			input:        "XREADGROUP GROUP g c STREAMS s1 s2 > >",
+			expectedName: "XREADGROUP",
+			expectedArgs: []string{"GROUP", "g", "c", "STREAMS", "s1", "s2", ">", ">"},
+			expectedRESP: []byte("*9\r\n$10\r\nXREADGROUP\r\n$5\r\nGROUP\r\n$1\r\ng\r\n$1\r\nc\r\n$7\r\nSTREAMS\r\n$2\r\ns1\r\n$2\r\ns2\r\n$1\r\n>\r\n$1\r\n>\r\n"),
+		},
+		{
+			name:         "Pending ID Is Not A Redirect",
+			input:        "XREADGROUP GROUP g c STREAMS s1 s2 > 0-0",
+			expectedName: "XREADGROUP",
+			expectedArgs: []string{"GROUP", "g", "c", "STREAMS", "s1", "s2", ">", "0-0"},
+			expectedRESP: []byte("*9\r\n$10\r\nXREADGROUP\r\n$5\r\nGROUP\r\n$1\r\ng\r\n$1\r\nc\r\n$7\r\nSTREAMS\r\n$2\r\ns1\r\n$2\r\ns2\r\n$1\r\n>\r\n$3\r\n0-0\r\n"),
+		},
+		{
+			name:         "XREADGROUP Does Not Redirect",
+			input:        "xreadgroup GROUP g c STREAMS s1 s2 > id",
+			expectedName: "XREADGROUP",
+			expectedArgs: []string{"GROUP", "g", "c", "STREAMS", "s1", "s2", ">", "id"},
+			expectedRESP: []byte("*9\r\n$10\r\nxreadgroup\r\n$5\r\nGROUP\r\n$1\r\ng\r\n$1\r\nc\r\n$7\r\nSTREAMS\r\n$2\r\ns1\r\n$2\r\ns2\r\n$1\r\n>\r\n$2\r\nid\r\n"),
+		},
+		{
+			name:         "XREAD Appends",
+			input:        "XREAD STREAMS s1 s2 0 0 >> out.txt",
+			expectedName: "XREAD",
+			expectedArgs: []string{"STREAMS", "s1", "s2", "0", "0"},
+			expectedFile: "out.txt",
+			expectedApp:  true,
+			expectedRESP: []byte("*6\r\n$5\r\nXREAD\r\n$7\r\nSTREAMS\r\n$2\r\ns1\r\n$2\r\ns2\r\n$1\r\n0\r\n$1\r\n0\r\n"),
+		},
 		{
 			name:         "SET with Codec",
 			input:        "SET key value#:base64",
@@ -106,6 +221,27 @@ func TestParse(t *testing.T) {
 			// "value" in base64 is "dmFsdWU=" (8 bytes)
 			expectedRESP: []byte("*3\r\n$3\r\nSET\r\n$3\r\nkey\r\n$8\r\ndmFsdWU=\r\n"),
 		},
+		{
+			name:         "PUBLISH with Codec",
+			input:        "PUBLISH ch value#:base64",
+			expectedName: "PUBLISH",
+			expectedArgs: []string{"ch", "value"},
+			expectedMod:  "base64",
+			expectedRESP: []byte("*3\r\n$7\r\nPUBLISH\r\n$2\r\nch\r\n$8\r\ndmFsdWU=\r\n"),
+		},
+		{
+			name:         "SET with Raw",
+			input:        "SET key value#:raw",
+			expectedName: "SET",
+			expectedArgs: []string{"key", "value"},
+			expectedMod:  "raw",
+			expectedRESP: []byte("*3\r\n$3\r\nSET\r\n$3\r\nkey\r\n$5\r\nvalue\r\n"),
+		},
+		{
+			name:    "Redirect Before Pipe",
+			input:   "GET mykey > out.txt | wc -c",
+			wantErr: true,
+		},
 		{
 			name:    "Unknown Codec",
 			input:   "SET key value#:unknown",
@@ -138,6 +274,12 @@ func TestParse(t *testing.T) {
 			if got.Pipe != tt.expectedPipe {
 				t.Errorf("Parse() Pipe = %v, want %v", got.Pipe, tt.expectedPipe)
 			}
+			if got.Filter != tt.expectedFilt {
+				t.Errorf("Parse() Filter = %v, want %v", got.Filter, tt.expectedFilt)
+			}
+			if got.Redirect != tt.expectedFile || got.Append != tt.expectedApp {
+				t.Errorf("Parse() Redirect = %q (append %v), want %q (append %v)", got.Redirect, got.Append, tt.expectedFile, tt.expectedApp)
+			}
 			if !bytes.Equal(got.CommandBytes, tt.expectedRESP) {
 				t.Errorf("Parse() CommandBytes = %q, want %q", got.CommandBytes, tt.expectedRESP)
 			}
@@ -195,3 +337,27 @@ func TestRegistry(t *testing.T) {
 		}
 	})
 }
+
+func TestValueKey(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"GET session:1", "session:1"},
+		{"hgetall user:1", "user:1"},
+		{"LRANGE q 0 -1", "q"},
+		{"TTL session:1", ""},
+		{"GET", ""},
+		{"INFO", ""},
+	}
+
+	for _, tt := range tests {
+		parsed, err := Parse(tt.input, nil)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", tt.input, err)
+		}
+		if got := ValueKey(parsed); got != tt.want {
+			t.Errorf("ValueKey(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
@@ -59,3 +59,44 @@ func tokenize(input string) []string {
 
 	return tokens
 }
+
+// unquotedIndexes returns the byte offsets of every occurrence of sep in
+// input that lies outside double quotes, using the same quoting and escape
+// rules as tokenize.
+//
+// C#: No direct equivalent — the C# version used IndexOf, which also matched
+// separators inside quoted values.
+func unquotedIndexes(input, sep string) []int {
+	var idx []int
+	inQuotes := false
+	escaped := false
+	for i := 0; i < len(input); i++ {
+		switch {
+		case escaped:
+			escaped = false
+		case input[i] == '\\':
+			escaped = true
+		case input[i] == '"':
+			inQuotes = !inQuotes
+		case !inQuotes && strings.HasPrefix(input[i:], sep):
+			idx = append(idx, i)
+		}
+	}
+	return idx
+}
+
+// indexUnquoted returns the first unquoted occurrence of sep in input, or -1.
+func indexUnquoted(input, sep string) int {
+	if idx := unquotedIndexes(input, sep); len(idx) > 0 {
+		return idx[0]
+	}
+	return -1
+}
+
+// lastIndexUnquoted returns the last unquoted occurrence of sep in input, or -1.
+func lastIndexUnquoted(input, sep string) int {
+	if idx := unquotedIndexes(input, sep); len(idx) > 0 {
+		return idx[len(idx)-1]
+	}
+	return -1
+}
@@ -19,6 +19,9 @@ type ParsedCommand struct {
 	CommandBytes []byte      // RESP-encoded command ready to send to Redis
 	Modifier     string      // codec name e.g. "gzip", empty if none
 	Pipe         string      // shell command after "|", empty if none
+	Filter       string      // built-in JSON filter after "|>", empty if none
+	Redirect     string      // file after ">" or ">>", empty if none
+	Append       bool        // true for ">>": append to Redirect instead of truncating
 	Doc          *CommandDoc // documentation, nil if not found
 }
 
@@ -0,0 +1,194 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds user settings loaded from the JSON config file.
+//
+// C#: No direct equivalent — the C# version had no configuration file.
+type Config struct {
+	Codecs    map[string]CodecConfig `json:"codecs"`     // external codecs by name, usable as #:name
+	RulesFile string                 `json:"rules_file"` // key pattern → codec rules; defaults to RulesPath
+	PipeShell string                 `json:"pipe_shell"` // shell for `| cmd`; empty for $SHELL, PipeShellNone to run without one
+	Paging    string                 `json:"paging"`     // REPL paging mode: "pager" (default), "prompt" or "none"
+	PageSize  int                    `json:"page_size"`  // items between prompts in "prompt" mode; 0 for the default
+	Format    string                 `json:"format"`     // format for `> file` redirects: "text" (default), "json" or "csv"
+	Profiles  Profiles               `json:"profiles"`   // named servers for `redisman copy` and COPY-KEYS
+	KeyView   string                 `json:"key_view"`   // TUI key pane: "list" (default) or "tree"
+	KeyDelim  string                 `json:"key_delim"`  // separates key name segments in the TUI key tree; empty for ":"
+}
+
+// Profile names a Redis server and its credentials.
+//
+// Example:
+//
+//	"profiles": {
+//	  "staging": {"host": "10.0.0.5", "port": 6380, "password": "s3cret"}
+//	}
+type Profile struct {
+	Host     string `json:"host"`     // defaults to localhost
+	Port     int    `json:"port"`     // defaults to 6379
+	Username string `json:"username"` // ACL username, optional
+	Password string `json:"password"` // optional
+}
+
+// Addr returns the profile's host and port in the form conn.Connect takes.
+func (p Profile) Addr() (host, port string) {
+	return p.Host, strconv.Itoa(p.Port)
+}
+
+// Profiles maps profile names to servers.
+type Profiles map[string]Profile
+
+// Lookup returns the named profile with defaults applied. A name that is not
+// a profile but looks like host:port is accepted as an ad-hoc server.
+func (ps Profiles) Lookup(name string) (Profile, error) {
+	p, ok := ps[name]
+	if !ok {
+		host, port, err := net.SplitHostPort(name)
+		n, portErr := strconv.Atoi(port)
+		if err != nil || portErr != nil {
+			return Profile{}, fmt.Errorf("unknown profile %q (known: %s)", name, strings.Join(ps.Names(), ", "))
+		}
+		p = Profile{Host: host, Port: n}
+	}
+	if p.Host == "" {
+		p.Host = "localhost"
+	}
+	if p.Port == 0 {
+		p.Port = 6379
+	}
+	return p, nil
+}
+
+// Names returns the profile names in sorted order.
+func (ps Profiles) Names() []string {
+	names := make([]string, 0, len(ps))
+	for name := range ps {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// PipeShellNone disables the shell for pipes: `| cmd args` runs cmd directly
+// with quote-aware argument splitting.
+const PipeShellNone = "none"
+
+// CodecConfig describes an external codec that pipes values through a
+// subprocess. Commands are argv arrays executed without a shell.
+//
+// Example:
+//
+//	"codecs": {
+//	  "mycorp": {
+//	    "encode": ["mycorp-envelope", "--seal"],
+//	    "decode": ["mycorp-envelope", "--open"],
+//	    "timeout": "3s"
+//	  }
+//	}
+type CodecConfig struct {
+	Encode  []string `json:"encode"`  // argv used on write (SET), optional
+	Decode  []string `json:"decode"`  // argv used on read (GET/VIEW), optional
+	Timeout string   `json:"timeout"` // Go duration string, e.g. "5s"; empty for the default
+}
+
+// TimeoutDuration parses Timeout. An empty string returns 0 so the caller
+// can apply its own default.
+func (cc CodecConfig) TimeoutDuration() (time.Duration, error) {
+	if cc.Timeout == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(cc.Timeout)
+}
+
+// DefaultPath returns the default config file location,
+// e.g. ~/.config/redisman/config.json on Linux.
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		home, _ := os.UserHomeDir()
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "redisman", "config.json")
+}
+
+// RulesPath returns the codec rules file to use for a config loaded from
+// configPath: RulesFile if set, otherwise "codec.rules" next to the config.
+func (cfg *Config) RulesPath(configPath string) string {
+	if cfg.RulesFile != "" {
+		return cfg.RulesFile
+	}
+	return filepath.Join(filepath.Dir(configPath), "codec.rules")
+}
+
+// Load reads and validates the config file at path. A missing file is not
+// an error — it yields an empty Config so redisman works without any setup.
+func Load(path string) (*Config, error) {
+	cfg := &Config{}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// validate checks fields that would otherwise fail later at use time.
+func (cfg *Config) validate() error {
+	switch cfg.Paging {
+	case "", "pager", "prompt", "none":
+	default:
+		return fmt.Errorf("paging must be \"pager\", \"prompt\" or \"none\", got %q", cfg.Paging)
+	}
+	switch cfg.Format {
+	case "", "text", "json", "csv":
+	default:
+		return fmt.Errorf("format must be \"text\", \"json\" or \"csv\", got %q", cfg.Format)
+	}
+	switch cfg.KeyView {
+	case "", "list", "tree":
+	default:
+		return fmt.Errorf("key_view must be \"list\" or \"tree\", got %q", cfg.KeyView)
+	}
+	if cfg.PageSize < 0 {
+		return fmt.Errorf("page_size must not be negative, got %d", cfg.PageSize)
+	}
+	for name, p := range cfg.Profiles {
+		if p.Port < 0 || p.Port > 65535 {
+			return fmt.Errorf("profile %q has invalid port %d", name, p.Port)
+		}
+	}
+	for name, cc := range cfg.Codecs {
+		if len(cc.Encode) == 0 && len(cc.Decode) == 0 {
+			return fmt.Errorf("codec %q needs an encode or decode command", name)
+		}
+		if _, err := cc.TimeoutDuration(); err != nil {
+			return fmt.Errorf("codec %q has invalid timeout %q: %w", name, cc.Timeout, err)
+		}
+	}
+	return nil
+}
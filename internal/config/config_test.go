@@ -0,0 +1,125 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	cfg, err := Load(filepath.Join(t.TempDir(), "nope.json"))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(cfg.Codecs) != 0 {
+		t.Errorf("Expected no codecs, got %v", cfg.Codecs)
+	}
+}
+
+func TestLoadCodecs(t *testing.T) {
+	path := writeConfig(t, `{
+		"codecs": {
+			"mycorp": {"encode": ["seal"], "decode": ["open", "-v"], "timeout": "3s"}
+		}
+	}`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	cc, ok := cfg.Codecs["mycorp"]
+	if !ok {
+		t.Fatal("Expected codec mycorp")
+	}
+	if len(cc.Decode) != 2 || cc.Decode[1] != "-v" {
+		t.Errorf("Unexpected decode argv: %v", cc.Decode)
+	}
+	d, err := cc.TimeoutDuration()
+	if err != nil || d != 3*time.Second {
+		t.Errorf("TimeoutDuration() = %v, %v; want 3s", d, err)
+	}
+}
+
+func TestProfilesLookup(t *testing.T) {
+	path := writeConfig(t, `{
+		"profiles": {
+			"prod": {"host": "10.0.0.1", "port": 6380, "username": "ops", "password": "pw"},
+			"local": {}
+		}
+	}`)
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		wantHost string
+		wantPort string
+		wantErr  bool
+	}{
+		{name: "prod", wantHost: "10.0.0.1", wantPort: "6380"},
+		{name: "local", wantHost: "localhost", wantPort: "6379"},
+		{name: "cache:7000", wantHost: "cache", wantPort: "7000"},
+		{name: "missing", wantErr: true},
+		{name: "cache:port", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := cfg.Profiles.Lookup(tt.name)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error, got %+v", p)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Lookup failed: %v", err)
+			}
+			host, port := p.Addr()
+			if host != tt.wantHost || port != tt.wantPort {
+				t.Errorf("Addr() = %s, %s; want %s, %s", host, port, tt.wantHost, tt.wantPort)
+			}
+		})
+	}
+
+	if p, _ := cfg.Profiles.Lookup("prod"); p.Username != "ops" || p.Password != "pw" {
+		t.Errorf("Credentials not loaded: %+v", p)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "Bad JSON", content: `{"codecs": `},
+		{name: "No Commands", content: `{"codecs": {"x": {}}}`},
+		{name: "Bad Timeout", content: `{"codecs": {"x": {"decode": ["cat"], "timeout": "soon"}}}`},
+		{name: "Bad Paging", content: `{"paging": "more"}`},
+		{name: "Negative Page Size", content: `{"page_size": -1}`},
+		{name: "Bad Format", content: `{"format": "xml"}`},
+		{name: "Bad Profile Port", content: `{"profiles": {"x": {"port": 70000}}}`},
+		{name: "Bad Key View", content: `{"key_view": "grid"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Load(writeConfig(t, tt.content)); err == nil {
+				t.Error("Expected error, got nil")
+			}
+		})
+	}
+}
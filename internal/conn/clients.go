@@ -0,0 +1,112 @@
+package conn
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cosmez/redisman-go/internal/resp"
+)
+
+// ClientInfo is one connection from CLIENT LIST. Fields that the server did
+// not send are left zero; every field is also kept in Fields as sent.
+type ClientInfo struct {
+	ID     int64
+	Addr   string
+	Name   string
+	User   string // Redis 6.0+
+	Age    time.Duration
+	Idle   time.Duration
+	DB     int
+	Cmd    string // last command run
+	Memory int64  // tot-mem in bytes, Redis 6.0+
+	Flags  string
+	Fields map[string]string
+}
+
+// ParseClientList parses a CLIENT LIST reply: one line per client of
+// space-separated key=value fields.
+func ParseClientList(text string) []ClientInfo {
+	var clients []ClientInfo
+	for line := range strings.Lines(text) {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		fields := make(map[string]string)
+		for field := range strings.FieldsSeq(line) {
+			if k, v, ok := strings.Cut(field, "="); ok {
+				fields[k] = v
+			}
+		}
+		integer := func(name string) int64 {
+			n, _ := strconv.ParseInt(fields[name], 10, 64)
+			return n
+		}
+		clients = append(clients, ClientInfo{
+			ID:     integer("id"),
+			Addr:   fields["addr"],
+			Name:   fields["name"],
+			User:   fields["user"],
+			Age:    time.Duration(integer("age")) * time.Second,
+			Idle:   time.Duration(integer("idle")) * time.Second,
+			DB:     int(integer("db")),
+			Cmd:    fields["cmd"],
+			Memory: integer("tot-mem"),
+			Flags:  fields["flags"],
+			Fields: fields,
+		})
+	}
+	return clients
+}
+
+// ClientList runs CLIENT LIST with optional filter arguments (e.g. TYPE
+// normal) and parses the reply.
+func (c *Connection) ClientList(args ...string) ([]ClientInfo, error) {
+	reply, err := c.Do(append([]string{"CLIENT", "LIST"}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseClientList(reply.StringValue()), nil
+}
+
+// ClientID returns the ID of this connection, as CLIENT LIST reports it.
+func (c *Connection) ClientID() (int64, error) {
+	reply, err := c.Do("CLIENT", "ID")
+	if err != nil {
+		return 0, err
+	}
+	n, ok := reply.(resp.RedisInteger)
+	if !ok {
+		return 0, fmt.Errorf("expected integer for CLIENT ID, got %T", reply)
+	}
+	return n.IntValue, nil
+}
+
+// CurrentDB returns the database this connection uses, from CLIENT INFO
+// (Redis 6.2+).
+func (c *Connection) CurrentDB() (int, error) {
+	reply, err := c.Do("CLIENT", "INFO")
+	if err != nil {
+		return 0, err
+	}
+	clients := ParseClientList(reply.StringValue())
+	if len(clients) != 1 {
+		return 0, fmt.Errorf("unexpected CLIENT INFO reply")
+	}
+	return clients[0].DB, nil
+}
+
+// ClientKill closes the connection with the given ID. It is an error if no
+// such client exists any more.
+func (c *Connection) ClientKill(id int64) error {
+	reply, err := c.Do("CLIENT", "KILL", "ID", strconv.FormatInt(id, 10))
+	if err != nil {
+		return err
+	}
+	if n, ok := reply.(resp.RedisInteger); ok && n.IntValue == 0 {
+		return fmt.Errorf("client %d is already gone", id)
+	}
+	return nil
+}
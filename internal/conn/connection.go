@@ -23,6 +23,7 @@ import (
 type Connection struct {
 	Host       string
 	Port       string
+	user, pass string // kept for Dial
 	reader     *bufio.Reader
 	conn       net.Conn
 	ServerInfo map[string]string
@@ -45,6 +46,8 @@ func Connect(host, port, user, pass string) (*Connection, error) {
 	c := &Connection{
 		Host:   host,
 		Port:   port,
+		user:   user,
+		pass:   pass,
 		conn:   conn,
 		reader: bufio.NewReader(conn),
 	}
@@ -92,6 +95,12 @@ func Connect(host, port, user, pass string) (*Connection, error) {
 	return c, nil
 }
 
+// Dial opens another connection to the same server with the same
+// credentials, for commands such as MONITOR that take over a connection.
+func (c *Connection) Dial() (*Connection, error) {
+	return Connect(c.Host, c.Port, c.user, c.pass)
+}
+
 // Send writes a parsed command to the Redis server.
 func (c *Connection) Send(cmd *command.ParsedCommand) error {
 	_, err := c.conn.Write(cmd.CommandBytes)
@@ -106,6 +115,55 @@ func (c *Connection) Send(cmd *command.ParsedCommand) error {
 // C#: No direct equivalent — the C# version always routed through the parser.
 func (c *Connection) SendRaw(args ...string) error {
 	var buf bytes.Buffer
+	encodeRaw(&buf, args)
+	_, err := c.conn.Write(buf.Bytes())
+	return err
+}
+
+// Do sends a command built from raw arguments and returns its reply. An
+// error reply is returned as an error, so callers only handle data replies.
+func (c *Connection) Do(args ...string) (resp.RedisValue, error) {
+	if err := c.SendRaw(args...); err != nil {
+		return nil, fmt.Errorf("failed to send %s: %w", args[0], err)
+	}
+	reply, err := c.Receive(10 * time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("failed to receive %s reply: %w", args[0], err)
+	}
+	if errResp, ok := reply.(resp.RedisError); ok {
+		return nil, fmt.Errorf("%s failed: %s", args[0], errResp.Value)
+	}
+	return reply, nil
+}
+
+// Pipeline sends all commands in a single write, then reads one reply per
+// command in order. Error replies come back as resp.RedisError values; the
+// returned error is only for I/O failures, after which the connection
+// should be considered unusable.
+//
+// C#: No direct equivalent — the C# version sent one command per round trip.
+func (c *Connection) Pipeline(cmds [][]string) ([]resp.RedisValue, error) {
+	var buf bytes.Buffer
+	for _, args := range cmds {
+		encodeRaw(&buf, args)
+	}
+	if _, err := c.conn.Write(buf.Bytes()); err != nil {
+		return nil, fmt.Errorf("failed to send pipeline: %w", err)
+	}
+
+	replies := make([]resp.RedisValue, 0, len(cmds))
+	for range cmds {
+		reply, err := c.Receive(30 * time.Second)
+		if err != nil {
+			return replies, fmt.Errorf("failed to receive pipeline reply: %w", err)
+		}
+		replies = append(replies, reply)
+	}
+	return replies, nil
+}
+
+// encodeRaw appends args to buf as a RESP array of bulk strings.
+func encodeRaw(buf *bytes.Buffer, args []string) {
 	buf.WriteString(fmt.Sprintf("*%d\r\n", len(args)))
 	for _, arg := range args {
 		b := []byte(arg)
@@ -113,8 +171,6 @@ func (c *Connection) SendRaw(args ...string) error {
 		buf.Write(b)
 		buf.WriteString("\r\n")
 	}
-	_, err := c.conn.Write(buf.Bytes())
-	return err
 }
 
 // Receive reads a single RESP value from the server, optionally with a timeout.
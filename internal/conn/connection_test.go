@@ -2,8 +2,13 @@ package conn
 
 import (
 	"bufio"
+	"context"
+	"errors"
+	"fmt"
 	"net"
 	"reflect"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -130,6 +135,32 @@ func TestSafeKeys(t *testing.T) {
 	}
 }
 
+func TestScanPage(t *testing.T) {
+	c, serverConn := setupMockConnection()
+	defer c.Close()
+	defer serverConn.Close()
+
+	got := make(chan string, 1)
+	go func() {
+		buf := make([]byte, 1024)
+		n, _ := serverConn.Read(buf)
+		got <- string(buf[:n])
+		serverConn.Write([]byte("*2\r\n$2\r\n17\r\n*2\r\n$4\r\nkey1\r\n$4\r\nkey2\r\n"))
+	}()
+
+	next, keys, err := c.ScanPage("0", "key*", 50)
+	if err != nil {
+		t.Fatalf("ScanPage failed: %v", err)
+	}
+	if next != "17" || !reflect.DeepEqual(keys, []string{"key1", "key2"}) {
+		t.Errorf("ScanPage() = %q, %v", next, keys)
+	}
+	want := "*6\r\n$4\r\nSCAN\r\n$1\r\n0\r\n$5\r\nMATCH\r\n$4\r\nkey*\r\n$5\r\nCOUNT\r\n$2\r\n50\r\n"
+	if cmd := <-got; cmd != want {
+		t.Errorf("Sent %q, want %q", cmd, want)
+	}
+}
+
 func TestSendRaw(t *testing.T) {
 	c, serverConn := setupMockConnection()
 	defer c.Close()
@@ -154,6 +185,37 @@ func TestSendRaw(t *testing.T) {
 	}
 }
 
+func TestPipeline(t *testing.T) {
+	c, serverConn := setupMockConnection()
+	defer c.Close()
+	defer serverConn.Close()
+
+	go func() {
+		r := bufio.NewReader(serverConn)
+		for i := 0; i < 2; i++ {
+			if _, err := resp.ParseValue(r); err != nil {
+				t.Errorf("Server read failed: %v", err)
+				return
+			}
+		}
+		serverConn.Write([]byte("$3\r\nabc\r\n-ERR nope\r\n"))
+	}()
+
+	replies, err := c.Pipeline([][]string{{"GET", "a"}, {"BAD"}})
+	if err != nil {
+		t.Fatalf("Pipeline failed: %v", err)
+	}
+	if len(replies) != 2 {
+		t.Fatalf("Expected 2 replies, got %d", len(replies))
+	}
+	if replies[0].StringValue() != "abc" {
+		t.Errorf("Expected abc, got %v", replies[0])
+	}
+	if _, ok := replies[1].(resp.RedisError); !ok {
+		t.Errorf("Expected error reply, got %T", replies[1])
+	}
+}
+
 func TestGetKeyValue_String(t *testing.T) {
 	c, serverConn := setupMockConnection()
 	defer c.Close()
@@ -187,3 +249,204 @@ func TestGetKeyValue_String(t *testing.T) {
 		t.Error("Expected nil collection for string type")
 	}
 }
+
+func TestDo(t *testing.T) {
+	c, serverConn := setupMockConnection()
+	defer c.Close()
+	defer serverConn.Close()
+
+	go func() {
+		buf := make([]byte, 1024)
+		serverConn.Read(buf)
+		serverConn.Write([]byte(":3\r\n"))
+		serverConn.Read(buf)
+		serverConn.Write([]byte("-WRONGTYPE Operation against a key holding the wrong kind of value\r\n"))
+	}()
+
+	reply, err := c.Do("LLEN", "k")
+	if n, ok := reply.(resp.RedisInteger); err != nil || !ok || n.IntValue != 3 {
+		t.Errorf("Do(LLEN) = %v, %v", reply, err)
+	}
+	if _, err := c.Do("LLEN", "s"); err == nil || !strings.Contains(err.Error(), "WRONGTYPE") {
+		t.Errorf("Do() error = %v, want the WRONGTYPE reply", err)
+	}
+}
+
+func TestParseClientList(t *testing.T) {
+	text := "id=3 addr=10.0.0.5:51234 laddr=10.0.0.1:6379 fd=8 name=worker age=3600 idle=12 flags=N db=2 sub=0 psub=0 cmd=blpop user=default tot-mem=22400\n" +
+		"id=7 addr=[::1]:40000 fd=9 name= age=5 idle=0 flags=x db=0 cmd=client|list\n"
+	clients := ParseClientList(text)
+	if len(clients) != 2 {
+		t.Fatalf("Got %d clients, want 2", len(clients))
+	}
+	want := ClientInfo{ID: 3, Addr: "10.0.0.5:51234", Name: "worker", User: "default", Age: time.Hour, Idle: 12 * time.Second,
+		DB: 2, Cmd: "blpop", Memory: 22400, Flags: "N", Fields: clients[0].Fields}
+	if !reflect.DeepEqual(clients[0], want) {
+		t.Errorf("clients[0] = %+v, want %+v", clients[0], want)
+	}
+	if c := clients[1]; c.Addr != "[::1]:40000" || c.Name != "" || c.Cmd != "client|list" || c.Fields["laddr"] != "" {
+		t.Errorf("clients[1] = %+v", c)
+	}
+}
+
+func TestClientKill(t *testing.T) {
+	c, serverConn := setupMockConnection()
+	defer c.Close()
+	defer serverConn.Close()
+
+	got := make(chan string, 1)
+	go func() {
+		buf := make([]byte, 1024)
+		n, _ := serverConn.Read(buf)
+		got <- string(buf[:n])
+		serverConn.Write([]byte(":1\r\n"))
+		serverConn.Read(buf)
+		serverConn.Write([]byte(":0\r\n"))
+	}()
+
+	if err := c.ClientKill(12); err != nil {
+		t.Errorf("ClientKill failed: %v", err)
+	}
+	if cmd := <-got; cmd != "*4\r\n$6\r\nCLIENT\r\n$4\r\nKILL\r\n$2\r\nID\r\n$2\r\n12\r\n" {
+		t.Errorf("Sent %q", cmd)
+	}
+	if err := c.ClientKill(12); err == nil {
+		t.Error("Killing a client that is gone should fail")
+	}
+}
+
+func TestParseMonitorLine(t *testing.T) {
+	e, err := ParseMonitorLine(`1700000000.123456 [2 127.0.0.1:60866] "set" "a b" "say \"hi\"\n\x00\\"`)
+	if err != nil {
+		t.Fatalf("ParseMonitorLine failed: %v", err)
+	}
+	if e.DB != 2 || e.Client != "127.0.0.1:60866" || e.Command() != "SET" {
+		t.Errorf("Got db %d, client %q, command %q", e.DB, e.Client, e.Command())
+	}
+	if want := []string{"set", "a b", "say \"hi\"\n\x00\\"}; !slices.Equal(e.Args, want) {
+		t.Errorf("Args = %q, want %q", e.Args, want)
+	}
+	if e.Time.UnixMicro() != 1700000000123456 {
+		t.Errorf("Time = %v", e.Time.UnixMicro())
+	}
+
+	e, err = ParseMonitorLine(`1700000000.000001 [0 lua] "get" "k"`)
+	if err != nil || e.Client != "lua" || len(e.Args) != 2 {
+		t.Errorf("Lua line: %+v, %v", e, err)
+	}
+	for _, bad := range []string{"OK", `x [0 lua] "get"`, `1.0 [0 lua] "get`, `1.0 [0 lua] get`} {
+		if _, err := ParseMonitorLine(bad); !errors.Is(err, ErrMonitorLine) {
+			t.Errorf("ParseMonitorLine(%q) = %v, want ErrMonitorLine", bad, err)
+		}
+	}
+}
+
+func TestMonitor(t *testing.T) {
+	c, serverConn := setupMockConnection()
+	defer c.Close()
+	defer serverConn.Close()
+
+	go func() {
+		buf := make([]byte, 1024)
+		serverConn.Read(buf)
+		serverConn.Write([]byte("+OK\r\n+1700000000.5 [0 10.0.0.1:5000] \"get\" \"a\"\r\n+garbage\r\n+1700000001.5 [1 10.0.0.2:5000] \"ping\"\r\n"))
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var cmds []string
+	var errs int
+	for e, err := range c.Monitor(ctx) {
+		if err != nil {
+			errs++
+			continue
+		}
+		cmds = append(cmds, e.Command())
+		if len(cmds) == 2 {
+			cancel()
+		}
+	}
+	if !slices.Equal(cmds, []string{"GET", "PING"}) || errs != 1 {
+		t.Errorf("Got %v and %d errors, want GET, PING and one error", cmds, errs)
+	}
+}
+
+func TestParsePubSubMessage(t *testing.T) {
+	bulk := func(s string) resp.RedisValue { return resp.RedisBulkString{Value: s, Length: len(s)} }
+	tests := []struct {
+		in   resp.RedisValue
+		want PubSubMessage
+	}{
+		{resp.RedisArray{Values: []resp.RedisValue{bulk("message"), bulk("news"), bulk("hi")}},
+			PubSubMessage{Kind: "message", Channel: "news", Payload: "hi"}},
+		{resp.RedisArray{Values: []resp.RedisValue{bulk("pmessage"), bulk("news.*"), bulk("news.eu"), bulk("hi")}},
+			PubSubMessage{Kind: "pmessage", Pattern: "news.*", Channel: "news.eu", Payload: "hi"}},
+		{resp.RedisArray{Values: []resp.RedisValue{bulk("smessage"), bulk("orders"), bulk("1")}},
+			PubSubMessage{Kind: "smessage", Channel: "orders", Payload: "1"}},
+		{resp.RedisArray{Values: []resp.RedisValue{bulk("psubscribe"), bulk("news.*"), resp.RedisInteger{IntValue: 2}}},
+			PubSubMessage{Kind: "psubscribe", Channel: "news.*", Count: 2}},
+	}
+	for _, tt := range tests {
+		got, err := ParsePubSubMessage(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParsePubSubMessage() = %+v, %v; want %+v", got, err, tt.want)
+		}
+	}
+	if got, _ := ParsePubSubMessage(tests[0].in); !got.IsMessage() {
+		t.Error("message should be a message")
+	}
+	for _, bad := range []resp.RedisValue{bulk("message"), resp.RedisArray{Values: []resp.RedisValue{bulk("message"), bulk("news")}}} {
+		if _, err := ParsePubSubMessage(bad); err == nil {
+			t.Errorf("ParsePubSubMessage(%+v) should fail", bad)
+		}
+	}
+}
+
+func TestListen(t *testing.T) {
+	c, serverConn := setupMockConnection()
+	defer c.Close()
+	defer serverConn.Close()
+
+	got := make(chan string, 1)
+	go func() {
+		buf := make([]byte, 1024)
+		n, _ := serverConn.Read(buf)
+		got <- string(buf[:n])
+		serverConn.Write([]byte("*3\r\n$10\r\npsubscribe\r\n$6\r\nnews.*\r\n:1\r\n" +
+			"*4\r\n$8\r\npmessage\r\n$6\r\nnews.*\r\n$7\r\nnews.eu\r\n$2\r\nhi\r\n"))
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var msgs []PubSubMessage
+	for m, err := range c.Listen(ctx, "PSUBSCRIBE", "news.*") {
+		if err != nil {
+			t.Fatalf("Listen failed: %v", err)
+		}
+		if msgs = append(msgs, m); len(msgs) == 2 {
+			cancel()
+		}
+	}
+	if cmd := <-got; cmd != "*2\r\n$10\r\nPSUBSCRIBE\r\n$6\r\nnews.*\r\n" {
+		t.Errorf("Sent %q", cmd)
+	}
+	if len(msgs) != 2 || msgs[0].Kind != "psubscribe" || msgs[1].Channel != "news.eu" || msgs[1].Payload != "hi" {
+		t.Errorf("Got %+v", msgs)
+	}
+}
+
+func TestCurrentDB(t *testing.T) {
+	c, serverConn := setupMockConnection()
+	defer c.Close()
+	defer serverConn.Close()
+
+	go func() {
+		buf := make([]byte, 1024)
+		serverConn.Read(buf)
+		info := "id=3 addr=127.0.0.1:5000 name= db=4 cmd=client|info\n"
+		fmt.Fprintf(serverConn, "$%d\r\n%s\r\n", len(info), info)
+	}()
+	if db, err := c.CurrentDB(); err != nil || db != 4 {
+		t.Errorf("CurrentDB() = %d, %v; want 4", db, err)
+	}
+}
@@ -114,22 +114,34 @@ func extractStringArray(v resp.RedisValue) []string {
 }
 
 func (c *Connection) getServerInfo() error {
+	info, err := c.Info()
+	if err != nil {
+		return err
+	}
+	c.ServerInfo = info
+	return nil
+}
+
+// Info sends INFO and returns its fields as a map, e.g. "used_memory" or
+// "db0". Section headers and blank lines are skipped. ServerInfo holds the
+// reply fetched at connect; Info fetches a fresh one.
+func (c *Connection) Info() (map[string]string, error) {
 	infoCmd, _ := command.Parse("INFO", nil)
 	if err := c.Send(infoCmd); err != nil {
-		return fmt.Errorf("failed to send INFO command: %w", err)
+		return nil, fmt.Errorf("failed to send INFO command: %w", err)
 	}
 
 	response, err := c.Receive(5 * time.Second)
 	if err != nil {
-		return fmt.Errorf("failed to receive INFO response: %w", err)
+		return nil, fmt.Errorf("failed to receive INFO response: %w", err)
 	}
 
 	bulkStr, ok := response.(resp.RedisBulkString)
 	if !ok {
-		return fmt.Errorf("expected bulk string for INFO, got %T", response)
+		return nil, fmt.Errorf("expected bulk string for INFO, got %T", response)
 	}
 
-	c.ServerInfo = make(map[string]string)
+	info := make(map[string]string)
 	lines := strings.Split(bulkStr.Value, "\r\n")
 
 	for _, line := range lines {
@@ -140,9 +152,9 @@ func (c *Connection) getServerInfo() error {
 
 		parts := strings.SplitN(line, ":", 2)
 		if len(parts) == 2 {
-			c.ServerInfo[parts[0]] = parts[1]
+			info[parts[0]] = parts[1]
 		}
 	}
 
-	return nil
+	return info, nil
 }
@@ -3,9 +3,9 @@ package conn
 import (
 	"fmt"
 	"iter"
+	"strconv"
 	"time"
 
-	"github.com/cosmez/redisman-go/internal/command"
 	"github.com/cosmez/redisman-go/internal/resp"
 )
 
@@ -20,14 +20,13 @@ import (
 //
 // Go:
 // We use Go 1.23's iter.Seq. If an error occurs, we yield a RedisError and stop.
+// Each SCAN reply is read in full before its keys are yielded, so the caller
+// may send other commands on c between yields.
 func (c *Connection) SafeKeys(pattern string) iter.Seq[resp.RedisValue] {
 	return func(yield func(resp.RedisValue) bool) {
 		cursor := "0"
 		for {
-			cmdStr := fmt.Sprintf("SCAN %s MATCH %s COUNT 100", cursor, pattern)
-			cmd, _ := command.Parse(cmdStr, nil)
-
-			if err := c.Send(cmd); err != nil {
+			if err := c.SendRaw("SCAN", cursor, "MATCH", pattern, "COUNT", "100"); err != nil {
 				yield(resp.RedisError{Value: fmt.Sprintf("SCAN send failed: %v", err)})
 				return
 			}
@@ -72,15 +71,44 @@ func (c *Connection) SafeKeys(pattern string) iter.Seq[resp.RedisValue] {
 	}
 }
 
+// ScanPage runs a single SCAN step from cursor and returns the next cursor
+// ("0" when the scan is complete) and the keys of this page. Unlike
+// SafeKeys it lets the caller release the connection between pages.
+func (c *Connection) ScanPage(cursor, pattern string, count int) (next string, keys []string, err error) {
+	if err := c.SendRaw("SCAN", cursor, "MATCH", pattern, "COUNT", strconv.Itoa(count)); err != nil {
+		return "", nil, fmt.Errorf("SCAN send failed: %w", err)
+	}
+
+	response, err := c.Receive(10 * time.Second)
+	if err != nil {
+		return "", nil, fmt.Errorf("SCAN receive failed: %w", err)
+	}
+	if errResp, ok := response.(resp.RedisError); ok {
+		return "", nil, fmt.Errorf("SCAN failed: %s", errResp.Value)
+	}
+
+	array, ok := response.(resp.RedisArray)
+	if !ok || len(array.Values) < 2 {
+		return "", nil, fmt.Errorf("unexpected SCAN response format")
+	}
+	keysArray, ok := array.Values[1].(resp.RedisArray)
+	if !ok {
+		return "", nil, fmt.Errorf("unexpected SCAN keys array format")
+	}
+
+	keys = make([]string, 0, len(keysArray.Values))
+	for _, key := range keysArray.Values {
+		keys = append(keys, key.StringValue())
+	}
+	return array.Values[0].StringValue(), keys, nil
+}
+
 // SafeSets iterates over all members of a Set using the SSCAN command.
 func (c *Connection) SafeSets(key string) iter.Seq[resp.RedisValue] {
 	return func(yield func(resp.RedisValue) bool) {
 		cursor := "0"
 		for {
-			cmdStr := fmt.Sprintf("SSCAN %s %s COUNT 100", key, cursor)
-			cmd, _ := command.Parse(cmdStr, nil)
-
-			if err := c.Send(cmd); err != nil {
+			if err := c.SendRaw("SSCAN", key, cursor, "COUNT", "100"); err != nil {
 				yield(resp.RedisError{Value: fmt.Sprintf("SSCAN send failed: %v", err)})
 				return
 			}
@@ -132,10 +160,7 @@ func (c *Connection) SafeHash(key string) iter.Seq[resp.RedisValue] {
 	return func(yield func(resp.RedisValue) bool) {
 		cursor := "0"
 		for {
-			cmdStr := fmt.Sprintf("HSCAN %s %s COUNT 100", key, cursor)
-			cmd, _ := command.Parse(cmdStr, nil)
-
-			if err := c.Send(cmd); err != nil {
+			if err := c.SendRaw("HSCAN", key, cursor, "COUNT", "100"); err != nil {
 				yield(resp.RedisError{Value: fmt.Sprintf("HSCAN send failed: %v", err)})
 				return
 			}
@@ -186,10 +211,7 @@ func (c *Connection) SafeSortedSets(key string) iter.Seq[resp.RedisValue] {
 	return func(yield func(resp.RedisValue) bool) {
 		cursor := "0"
 		for {
-			cmdStr := fmt.Sprintf("ZSCAN %s %s COUNT 100", key, cursor)
-			cmd, _ := command.Parse(cmdStr, nil)
-
-			if err := c.Send(cmd); err != nil {
+			if err := c.SendRaw("ZSCAN", key, cursor, "COUNT", "100"); err != nil {
 				yield(resp.RedisError{Value: fmt.Sprintf("ZSCAN send failed: %v", err)})
 				return
 			}
@@ -241,10 +263,7 @@ func (c *Connection) SafeList(key string) iter.Seq[resp.RedisValue] {
 	return func(yield func(resp.RedisValue) bool) {
 		start := 0
 		for {
-			cmdStr := fmt.Sprintf("LRANGE %s %d %d", key, start, start+99)
-			cmd, _ := command.Parse(cmdStr, nil)
-
-			if err := c.Send(cmd); err != nil {
+			if err := c.SendRaw("LRANGE", key, strconv.Itoa(start), strconv.Itoa(start+99)); err != nil {
 				yield(resp.RedisError{Value: fmt.Sprintf("LRANGE send failed: %v", err)})
 				return
 			}
@@ -290,10 +309,7 @@ func (c *Connection) SafeStream(key string) iter.Seq[resp.RedisValue] {
 	return func(yield func(resp.RedisValue) bool) {
 		cursor := "-" // Start from the beginning
 		for {
-			cmdStr := fmt.Sprintf("XRANGE %s %s + COUNT 100", key, cursor)
-			cmd, _ := command.Parse(cmdStr, nil)
-
-			if err := c.Send(cmd); err != nil {
+			if err := c.SendRaw("XRANGE", key, cursor, "+", "COUNT", "100"); err != nil {
 				yield(resp.RedisError{Value: fmt.Sprintf("XRANGE send failed: %v", err)})
 				return
 			}
@@ -5,7 +5,6 @@ import (
 	"iter"
 	"time"
 
-	"github.com/cosmez/redisman-go/internal/command"
 	"github.com/cosmez/redisman-go/internal/resp"
 )
 
@@ -15,8 +14,8 @@ import (
 // C#:
 // public (string typeName, IRedisValue single, IEnumerable<IRedisValue> collection) GetKeyValue(string key)
 func (c *Connection) GetKeyValue(key string) (typeName string, single resp.RedisValue, collection iter.Seq[resp.RedisValue], err error) {
-	typeCmd, _ := command.Parse(fmt.Sprintf("TYPE %s", key), nil)
-	if err := c.Send(typeCmd); err != nil {
+	// SendRaw keeps keys with spaces, quotes or binary data intact.
+	if err := c.SendRaw("TYPE", key); err != nil {
 		return "", nil, nil, fmt.Errorf("failed to send TYPE command: %w", err)
 	}
 
@@ -38,8 +37,7 @@ func (c *Connection) GetKeyValue(key string) (typeName string, single resp.Redis
 
 	switch typeName {
 	case "string":
-		getCmd, _ := command.Parse(fmt.Sprintf("GET %s", key), nil)
-		if err := c.Send(getCmd); err != nil {
+		if err := c.SendRaw("GET", key); err != nil {
 			return typeName, nil, nil, fmt.Errorf("failed to send GET command: %w", err)
 		}
 		single, err = c.Receive(5 * time.Second)
@@ -64,3 +62,19 @@ func (c *Connection) GetKeyValue(key string) (typeName string, single resp.Redis
 		return typeName, nil, nil, fmt.Errorf("unsupported key type: %s", typeName)
 	}
 }
+
+// DBSize returns the number of keys in the selected database.
+func (c *Connection) DBSize() (int64, error) {
+	if err := c.SendRaw("DBSIZE"); err != nil {
+		return 0, fmt.Errorf("failed to send DBSIZE command: %w", err)
+	}
+	response, err := c.Receive(5 * time.Second)
+	if err != nil {
+		return 0, fmt.Errorf("failed to receive DBSIZE response: %w", err)
+	}
+	n, ok := response.(resp.RedisInteger)
+	if !ok {
+		return 0, fmt.Errorf("expected integer for DBSIZE, got %T", response)
+	}
+	return n.IntValue, nil
+}
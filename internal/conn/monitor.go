@@ -0,0 +1,140 @@
+package conn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cosmez/redisman-go/internal/resp"
+)
+
+// MonitorEvent is one command reported by MONITOR.
+type MonitorEvent struct {
+	Time   time.Time
+	DB     int
+	Client string // ip:port, "lua" or a unix socket path
+	Args   []string
+	Line   string // the line as sent by the server
+}
+
+// Command returns the upper-cased command name, e.g. "GET".
+func (e MonitorEvent) Command() string {
+	if len(e.Args) == 0 {
+		return ""
+	}
+	return strings.ToUpper(e.Args[0])
+}
+
+// ErrMonitorLine is wrapped by the errors of ParseMonitorLine. Monitor keeps
+// reading after such an error; any other error ends the stream.
+var ErrMonitorLine = errors.New("malformed MONITOR line")
+
+// ParseMonitorLine parses a MONITOR line such as
+//
+//	1339518083.107412 [0 127.0.0.1:60866] "set" "key" "a \"b\""
+//
+// Arguments are quoted as by Redis's sdscatrepr, with \xHH for
+// non-printable bytes.
+func ParseMonitorLine(line string) (MonitorEvent, error) {
+	e := MonitorEvent{Line: line}
+	stamp, rest, ok := strings.Cut(line, " [")
+	if !ok {
+		return e, fmt.Errorf("%w %q", ErrMonitorLine, line)
+	}
+	secs, err := strconv.ParseFloat(stamp, 64)
+	if err != nil {
+		return e, fmt.Errorf("%w: bad timestamp %q", ErrMonitorLine, stamp)
+	}
+	e.Time = time.UnixMicro(int64(secs*1e6 + 0.5))
+
+	source, args, ok := strings.Cut(rest, "] ")
+	if !ok {
+		return e, fmt.Errorf("%w %q", ErrMonitorLine, line)
+	}
+	db, client, _ := strings.Cut(source, " ")
+	if e.DB, err = strconv.Atoi(db); err != nil {
+		return e, fmt.Errorf("%w: bad database %q", ErrMonitorLine, db)
+	}
+	e.Client = client
+	if e.Args, err = parseQuotedArgs(args); err != nil {
+		return e, fmt.Errorf("%w %q: %w", ErrMonitorLine, line, err)
+	}
+	return e, nil
+}
+
+// parseQuotedArgs splits space-separated double-quoted arguments and
+// resolves their escapes.
+func parseQuotedArgs(s string) ([]string, error) {
+	var args []string
+	for s = strings.TrimLeft(s, " "); s != ""; s = strings.TrimLeft(s, " ") {
+		if s[0] != '"' {
+			return args, fmt.Errorf("expected a quoted argument at %q", s)
+		}
+		var b strings.Builder
+		i := 1
+		for ; i < len(s) && s[i] != '"'; i++ {
+			if s[i] != '\\' || i+1 >= len(s) {
+				b.WriteByte(s[i])
+				continue
+			}
+			i++
+			switch s[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case 'a':
+				b.WriteByte('\a')
+			case 'b':
+				b.WriteByte('\b')
+			case 'x':
+				if i+2 < len(s) {
+					if n, err := strconv.ParseUint(s[i+1:i+3], 16, 8); err == nil {
+						b.WriteByte(byte(n))
+						i += 2
+						continue
+					}
+				}
+				b.WriteByte('x')
+			default: // \\ and \"
+				b.WriteByte(s[i])
+			}
+		}
+		if i >= len(s) {
+			return args, fmt.Errorf("unterminated argument")
+		}
+		args = append(args, b.String())
+		s = s[i+1:]
+	}
+	return args, nil
+}
+
+// Monitor sends MONITOR and yields each command the server reports until ctx
+// is cancelled, using the same polling loop as Subscribe. Lines that cannot
+// be parsed are yielded as errors wrapping ErrMonitorLine and the stream
+// goes on; a read failure or error reply is yielded last. MONITOR takes over
+// the connection for good, so it should be run on a connection from Dial and
+// closed afterwards.
+func (c *Connection) Monitor(ctx context.Context) iter.Seq2[MonitorEvent, error] {
+	return func(yield func(MonitorEvent, error) bool) {
+		if _, err := c.Do("MONITOR"); err != nil {
+			yield(MonitorEvent{}, err)
+			return
+		}
+		for msg := range c.Subscribe(ctx) {
+			if errResp, ok := msg.(resp.RedisError); ok {
+				yield(MonitorEvent{}, errors.New(errResp.Value))
+				return
+			}
+			if !yield(ParseMonitorLine(msg.StringValue())) {
+				return
+			}
+		}
+	}
+}
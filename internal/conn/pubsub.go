@@ -0,0 +1,145 @@
+package conn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"strings"
+
+	"github.com/cosmez/redisman-go/internal/resp"
+)
+
+// PubSubMessage is a push received while subscribed: a published message, or
+// the confirmation of a (un)subscribe.
+type PubSubMessage struct {
+	Kind    string // message, pmessage, smessage, subscribe, psubscribe, unsubscribe, ...
+	Pattern string // the matching pattern of a pmessage
+	Channel string // the channel; the pattern for psubscribe and punsubscribe
+	Payload string
+	Count   int64 // subscriptions left after a (un)subscribe
+}
+
+// IsMessage reports whether m carries a published payload rather than a
+// subscription confirmation.
+func (m PubSubMessage) IsMessage() bool {
+	return m.Kind == "message" || m.Kind == "pmessage" || m.Kind == "smessage"
+}
+
+// ParsePubSubMessage decodes a push such as ["message", channel, payload],
+// ["pmessage", pattern, channel, payload] or ["subscribe", channel, count].
+func ParsePubSubMessage(v resp.RedisValue) (PubSubMessage, error) {
+	arr, ok := v.(resp.RedisArray)
+	if !ok || len(arr.Values) < 2 {
+		return PubSubMessage{}, fmt.Errorf("unexpected Pub/Sub reply %T", v)
+	}
+	m := PubSubMessage{Kind: strings.ToLower(arr.Values[0].StringValue())}
+	fields := arr.Values[1:]
+	switch {
+	case m.Kind == "pmessage" && len(fields) == 3:
+		m.Pattern, m.Channel, m.Payload = fields[0].StringValue(), fields[1].StringValue(), fields[2].StringValue()
+	case m.IsMessage() && len(fields) == 2:
+		m.Channel, m.Payload = fields[0].StringValue(), fields[1].StringValue()
+	case strings.HasSuffix(m.Kind, "subscribe") && len(fields) == 2:
+		m.Channel = fields[0].StringValue()
+		if n, ok := fields[1].(resp.RedisInteger); ok {
+			m.Count = n.IntValue
+		}
+	case m.Kind == "pong":
+		m.Payload = fields[0].StringValue()
+	default:
+		return m, fmt.Errorf("unexpected Pub/Sub %q reply with %d fields", m.Kind, len(fields))
+	}
+	return m, nil
+}
+
+// Listen sends a subscribe command (SUBSCRIBE, PSUBSCRIBE or SSUBSCRIBE) for
+// channels and yields what the server pushes until ctx is cancelled, using
+// the polling loop of Subscribe. Pushes that cannot be decoded are yielded as
+// errors; an error reply or a read failure ends the stream. A subscribed
+// connection accepts no other commands, so Listen should be run on a
+// connection from Dial and closed afterwards.
+func (c *Connection) Listen(ctx context.Context, subscribe string, channels ...string) iter.Seq2[PubSubMessage, error] {
+	return func(yield func(PubSubMessage, error) bool) {
+		if err := c.SendRaw(append([]string{subscribe}, channels...)...); err != nil {
+			yield(PubSubMessage{}, err)
+			return
+		}
+		for v := range c.Subscribe(ctx) {
+			if errResp, ok := v.(resp.RedisError); ok {
+				yield(PubSubMessage{}, errors.New(errResp.Value))
+				return
+			}
+			if !yield(ParsePubSubMessage(v)) {
+				return
+			}
+		}
+	}
+}
+
+// Publish sends message to channel (SPUBLISH for a shard channel) and
+// returns how many subscribers received it.
+func (c *Connection) Publish(shard bool, channel, message string) (int64, error) {
+	cmd := "PUBLISH"
+	if shard {
+		cmd = "SPUBLISH"
+	}
+	reply, err := c.Do(cmd, channel, message)
+	if err != nil {
+		return 0, err
+	}
+	n, ok := reply.(resp.RedisInteger)
+	if !ok {
+		return 0, fmt.Errorf("expected integer for %s, got %T", cmd, reply)
+	}
+	return n.IntValue, nil
+}
+
+// PubSubChannels returns the active channels matching pattern, all of them
+// if it is empty, with PUBSUB CHANNELS (SHARDCHANNELS for shard channels).
+func (c *Connection) PubSubChannels(shard bool, pattern string) ([]string, error) {
+	args := []string{"PUBSUB", "CHANNELS"}
+	if shard {
+		args[1] = "SHARDCHANNELS"
+	}
+	if pattern != "" {
+		args = append(args, pattern)
+	}
+	reply, err := c.Do(args...)
+	if err != nil {
+		return nil, err
+	}
+	arr, ok := reply.(resp.RedisArray)
+	if !ok {
+		return nil, fmt.Errorf("expected array for PUBSUB %s, got %T", args[1], reply)
+	}
+	channels := make([]string, len(arr.Values))
+	for i, v := range arr.Values {
+		channels[i] = v.StringValue()
+	}
+	return channels, nil
+}
+
+// PubSubNumSub returns the number of subscribers of each channel with
+// PUBSUB NUMSUB (SHARDNUMSUB for shard channels). Pattern subscribers are
+// not counted.
+func (c *Connection) PubSubNumSub(shard bool, channels ...string) (map[string]int64, error) {
+	sub := "NUMSUB"
+	if shard {
+		sub = "SHARDNUMSUB"
+	}
+	reply, err := c.Do(append([]string{"PUBSUB", sub}, channels...)...)
+	if err != nil {
+		return nil, err
+	}
+	arr, ok := reply.(resp.RedisArray)
+	if !ok || len(arr.Values)%2 != 0 {
+		return nil, fmt.Errorf("unexpected reply to PUBSUB %s", sub)
+	}
+	counts := make(map[string]int64, len(arr.Values)/2)
+	for i := 0; i < len(arr.Values); i += 2 {
+		n, _ := arr.Values[i+1].(resp.RedisInteger)
+		counts[arr.Values[i].StringValue()] = n.IntValue
+	}
+	return counts, nil
+}
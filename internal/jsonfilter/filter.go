@@ -0,0 +1,542 @@
+package jsonfilter
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Filter is a compiled jq-style filter expression.
+//
+// Supported syntax (a practical subset of jq):
+//
+//	.                 identity
+//	.foo  ."a b"      object field
+//	.[0]  .[-1]       array index (negative counts from the end)
+//	.[2:4]            array or string slice
+//	.[]               iterate array elements or object values
+//	.foo?             suppress type errors for the preceding step
+//	a | b             pipe results of a into b
+//	a, b              emit results of a, then of b
+//	(a)               grouping
+//	keys  keys_unsorted  length  type
+//
+// C#: No direct equivalent — the C# version relied on piping to jq.
+type Filter struct {
+	root expr
+	src  string
+}
+
+// Compile parses a filter expression.
+func Compile(src string) (*Filter, error) {
+	p := &parser{src: []rune(src)}
+	root, err := p.parsePipe()
+	if err != nil {
+		return nil, fmt.Errorf("filter %q: %w", src, err)
+	}
+	p.skipSpace()
+	if !p.eof() {
+		return nil, fmt.Errorf("filter %q: unexpected %q at offset %d", src, string(p.src[p.pos]), p.pos)
+	}
+	return &Filter{root: root, src: src}, nil
+}
+
+// Run applies the filter to a JSON document and returns each result as
+// compact JSON, in order.
+func (f *Filter) Run(doc string) ([]string, error) {
+	input, err := decode(doc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON input: %w", err)
+	}
+	results, err := f.root.eval(input)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]string, len(results))
+	for i, r := range results {
+		out[i] = encode(r)
+	}
+	return out, nil
+}
+
+// Run compiles expr and applies it to doc in one step.
+func Run(expr, doc string) ([]string, error) {
+	f, err := Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	return f.Run(doc)
+}
+
+// --- AST ---
+
+type expr interface {
+	eval(v any) ([]any, error)
+}
+
+type pipeExpr struct{ left, right expr }
+
+func (e pipeExpr) eval(v any) ([]any, error) {
+	lefts, err := e.left.eval(v)
+	if err != nil {
+		return nil, err
+	}
+	var out []any
+	for _, l := range lefts {
+		rs, err := e.right.eval(l)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, rs...)
+	}
+	return out, nil
+}
+
+type commaExpr struct{ parts []expr }
+
+func (e commaExpr) eval(v any) ([]any, error) {
+	var out []any
+	for _, p := range e.parts {
+		rs, err := p.eval(v)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, rs...)
+	}
+	return out, nil
+}
+
+type stepKind int
+
+const (
+	stepField stepKind = iota
+	stepIndex
+	stepIterate
+	stepSlice
+)
+
+type step struct {
+	kind     stepKind
+	key      string
+	index    int
+	from, to *int
+	optional bool
+}
+
+// pathExpr applies steps to the output of base (identity when base is nil).
+type pathExpr struct {
+	base  expr
+	steps []step
+}
+
+func (e pathExpr) eval(v any) ([]any, error) {
+	values := []any{v}
+	if e.base != nil {
+		var err error
+		if values, err = e.base.eval(v); err != nil {
+			return nil, err
+		}
+	}
+	for _, s := range e.steps {
+		var next []any
+		for _, cur := range values {
+			rs, err := s.apply(cur)
+			if err != nil {
+				if s.optional {
+					continue
+				}
+				return nil, err
+			}
+			next = append(next, rs...)
+		}
+		values = next
+	}
+	return values, nil
+}
+
+func (s step) apply(v any) ([]any, error) {
+	switch s.kind {
+	case stepField:
+		switch t := v.(type) {
+		case nil:
+			return []any{nil}, nil
+		case *object:
+			val, _ := t.get(s.key)
+			return []any{val}, nil
+		}
+		return nil, fmt.Errorf("cannot index %s with %q", typeName(v), s.key)
+	case stepIndex:
+		switch t := v.(type) {
+		case nil:
+			return []any{nil}, nil
+		case []any:
+			i := s.index
+			if i < 0 {
+				i += len(t)
+			}
+			if i < 0 || i >= len(t) {
+				return []any{nil}, nil
+			}
+			return []any{t[i]}, nil
+		}
+		return nil, fmt.Errorf("cannot index %s with number", typeName(v))
+	case stepIterate:
+		switch t := v.(type) {
+		case []any:
+			return t, nil
+		case *object:
+			out := make([]any, len(t.keys))
+			for i, k := range t.keys {
+				out[i] = t.vals[k]
+			}
+			return out, nil
+		}
+		return nil, fmt.Errorf("cannot iterate over %s", typeName(v))
+	case stepSlice:
+		switch t := v.(type) {
+		case nil:
+			return []any{nil}, nil
+		case []any:
+			from, to := sliceBounds(s.from, s.to, len(t))
+			return []any{append([]any{}, t[from:to]...)}, nil
+		case string:
+			r := []rune(t)
+			from, to := sliceBounds(s.from, s.to, len(r))
+			return []any{string(r[from:to])}, nil
+		}
+		return nil, fmt.Errorf("cannot slice %s", typeName(v))
+	}
+	return nil, fmt.Errorf("unknown step")
+}
+
+// sliceBounds resolves optional, possibly negative slice bounds against n.
+func sliceBounds(from, to *int, n int) (int, int) {
+	clamp := func(i int) int {
+		if i < 0 {
+			i += n
+		}
+		return max(0, min(i, n))
+	}
+	lo, hi := 0, n
+	if from != nil {
+		lo = clamp(*from)
+	}
+	if to != nil {
+		hi = clamp(*to)
+	}
+	if hi < lo {
+		hi = lo
+	}
+	return lo, hi
+}
+
+type funcExpr struct{ name string }
+
+func (e funcExpr) eval(v any) ([]any, error) {
+	switch e.name {
+	case "keys", "keys_unsorted":
+		switch t := v.(type) {
+		case *object:
+			keys := append([]string(nil), t.keys...)
+			if e.name == "keys" {
+				sort.Strings(keys)
+			}
+			out := make([]any, len(keys))
+			for i, k := range keys {
+				out[i] = k
+			}
+			return []any{out}, nil
+		case []any:
+			out := make([]any, len(t))
+			for i := range t {
+				out[i] = i
+			}
+			return []any{out}, nil
+		}
+		return nil, fmt.Errorf("%s has no keys", typeName(v))
+	case "length":
+		switch t := v.(type) {
+		case nil:
+			return []any{0}, nil
+		case string:
+			return []any{utf8.RuneCountInString(t)}, nil
+		case []any:
+			return []any{len(t)}, nil
+		case *object:
+			return []any{len(t.keys)}, nil
+		case json.Number:
+			return []any{json.Number(strings.TrimPrefix(t.String(), "-"))}, nil
+		}
+		return nil, fmt.Errorf("%s has no length", typeName(v))
+	case "type":
+		return []any{typeName(v)}, nil
+	}
+	return nil, fmt.Errorf("unknown function %q", e.name)
+}
+
+// --- Parser ---
+
+type parser struct {
+	src []rune
+	pos int
+}
+
+func (p *parser) eof() bool { return p.pos >= len(p.src) }
+
+func (p *parser) peek() rune {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *parser) skipSpace() {
+	for !p.eof() && unicode.IsSpace(p.src[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *parser) expect(r rune) error {
+	p.skipSpace()
+	if p.peek() != r {
+		if p.eof() {
+			return fmt.Errorf("expected %q, got end of filter", r)
+		}
+		return fmt.Errorf("expected %q at offset %d", r, p.pos)
+	}
+	p.pos++
+	return nil
+}
+
+// parsePipe: comma ('|' comma)*
+func (p *parser) parsePipe() (expr, error) {
+	left, err := p.parseComma()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if p.peek() != '|' {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseComma()
+		if err != nil {
+			return nil, err
+		}
+		left = pipeExpr{left: left, right: right}
+	}
+}
+
+// parseComma: term (',' term)*
+func (p *parser) parseComma() (expr, error) {
+	first, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	parts := []expr{first}
+	for {
+		p.skipSpace()
+		if p.peek() != ',' {
+			break
+		}
+		p.pos++
+		next, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, next)
+	}
+	if len(parts) == 1 {
+		return first, nil
+	}
+	return commaExpr{parts: parts}, nil
+}
+
+// parseTerm: path | '(' pipe ')' postfix* | function
+func (p *parser) parseTerm() (expr, error) {
+	p.skipSpace()
+	switch r := p.peek(); {
+	case r == '.':
+		p.pos++
+		var steps []step
+		// A field may follow the leading dot directly: .foo or ."foo"
+		if p.peek() == '"' || isIdentStart(p.peek()) {
+			key, err := p.parseKey()
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, step{kind: stepField, key: key})
+			p.parseOptional(steps)
+		}
+		more, err := p.parsePostfix()
+		if err != nil {
+			return nil, err
+		}
+		return pathExpr{steps: append(steps, more...)}, nil
+	case r == '(':
+		p.pos++
+		inner, err := p.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(')'); err != nil {
+			return nil, err
+		}
+		steps, err := p.parsePostfix()
+		if err != nil {
+			return nil, err
+		}
+		return pathExpr{base: inner, steps: steps}, nil
+	case isIdentStart(r):
+		name := p.parseIdent()
+		return funcExpr{name: name}, nil
+	case p.eof():
+		return nil, fmt.Errorf("unexpected end of filter")
+	default:
+		return nil, fmt.Errorf("unexpected %q at offset %d", string(r), p.pos)
+	}
+}
+
+// parsePostfix parses trailing .field and [..] steps.
+func (p *parser) parsePostfix() ([]step, error) {
+	var steps []step
+	for {
+		switch p.peek() {
+		case '.':
+			p.pos++
+			key, err := p.parseKey()
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, step{kind: stepField, key: key})
+		case '[':
+			p.pos++
+			s, err := p.parseBracket()
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, s)
+		default:
+			return steps, nil
+		}
+		p.parseOptional(steps)
+	}
+}
+
+// parseOptional marks the last step optional if followed by '?'.
+func (p *parser) parseOptional(steps []step) {
+	if p.peek() == '?' && len(steps) > 0 {
+		p.pos++
+		steps[len(steps)-1].optional = true
+	}
+}
+
+// parseBracket parses the inside of [...] after the opening bracket.
+func (p *parser) parseBracket() (step, error) {
+	p.skipSpace()
+	if p.peek() == ']' {
+		p.pos++
+		return step{kind: stepIterate}, nil
+	}
+	if p.peek() == '"' {
+		key, err := p.parseString()
+		if err != nil {
+			return step{}, err
+		}
+		return step{kind: stepField, key: key}, p.expect(']')
+	}
+
+	from, hasFrom, err := p.parseInt()
+	if err != nil {
+		return step{}, err
+	}
+	p.skipSpace()
+	if p.peek() != ':' {
+		if !hasFrom {
+			return step{}, fmt.Errorf("expected index, string or ':' at offset %d", p.pos)
+		}
+		return step{kind: stepIndex, index: from}, p.expect(']')
+	}
+	p.pos++ // ':'
+	to, hasTo, err := p.parseInt()
+	if err != nil {
+		return step{}, err
+	}
+	s := step{kind: stepSlice}
+	if hasFrom {
+		s.from = &from
+	}
+	if hasTo {
+		s.to = &to
+	}
+	return s, p.expect(']')
+}
+
+// parseInt parses an optional signed integer.
+func (p *parser) parseInt() (int, bool, error) {
+	p.skipSpace()
+	start := p.pos
+	if p.peek() == '-' {
+		p.pos++
+	}
+	for unicode.IsDigit(p.peek()) {
+		p.pos++
+	}
+	if p.pos == start {
+		return 0, false, nil
+	}
+	n, err := strconv.Atoi(string(p.src[start:p.pos]))
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid number %q", string(p.src[start:p.pos]))
+	}
+	return n, true, nil
+}
+
+// parseKey parses an identifier or quoted string used as an object key.
+func (p *parser) parseKey() (string, error) {
+	if p.peek() == '"' {
+		return p.parseString()
+	}
+	if !isIdentStart(p.peek()) {
+		return "", fmt.Errorf("expected field name at offset %d", p.pos)
+	}
+	return p.parseIdent(), nil
+}
+
+func (p *parser) parseIdent() string {
+	start := p.pos
+	for !p.eof() && (isIdentStart(p.peek()) || unicode.IsDigit(p.peek())) {
+		p.pos++
+	}
+	return string(p.src[start:p.pos])
+}
+
+// parseString parses a double-quoted JSON string literal.
+func (p *parser) parseString() (string, error) {
+	start := p.pos
+	p.pos++ // opening quote
+	for !p.eof() && p.peek() != '"' {
+		if p.peek() == '\\' {
+			p.pos++
+		}
+		p.pos++
+	}
+	if p.eof() {
+		return "", fmt.Errorf("unterminated string at offset %d", start)
+	}
+	p.pos++ // closing quote
+	var s string
+	if err := json.Unmarshal([]byte(string(p.src[start:p.pos])), &s); err != nil {
+		return "", fmt.Errorf("invalid string at offset %d: %w", start, err)
+	}
+	return s, nil
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
@@ -0,0 +1,74 @@
+package jsonfilter
+
+import (
+	"reflect"
+	"testing"
+)
+
+const sampleDoc = `{"name":"cart","items":[{"id":1,"tags":["a"]},{"id":2,"tags":[]}],"meta":{"z":1,"a":2},"odd key":true}`
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter string
+		want   []string
+	}{
+		{name: "Identity", filter: ".", want: []string{sampleDoc}},
+		{name: "Field", filter: ".name", want: []string{`"cart"`}},
+		{name: "Nested Iterate", filter: ".items[].id", want: []string{"1", "2"}},
+		{name: "Index", filter: ".items[0].id", want: []string{"1"}},
+		{name: "Negative Index", filter: ".items[-1].id", want: []string{"2"}},
+		{name: "Out Of Range", filter: ".items[5]", want: []string{"null"}},
+		{name: "Quoted Field", filter: `."odd key"`, want: []string{"true"}},
+		{name: "Bracket Field", filter: `.["odd key"]`, want: []string{"true"}},
+		{name: "Missing Field", filter: ".nope.deeper", want: []string{"null"}},
+		{name: "Pipe", filter: ".items[] | .tags | length", want: []string{"1", "0"}},
+		{name: "Comma", filter: ".name, .items[0].id", want: []string{`"cart"`, "1"}},
+		{name: "Keys Sorted", filter: ".meta | keys", want: []string{`["a","z"]`}},
+		{name: "Keys Unsorted", filter: ".meta | keys_unsorted", want: []string{`["z","a"]`}},
+		{name: "Object Order Preserved", filter: ".meta", want: []string{`{"z":1,"a":2}`}},
+		{name: "Slice", filter: ".items[1:] | length", want: []string{"1"}},
+		{name: "String Slice", filter: ".name[1:3]", want: []string{`"ar"`}},
+		{name: "Type", filter: ".items | type", want: []string{`"array"`}},
+		{name: "Grouping", filter: "(.items[0]).id", want: []string{"1"}},
+		{name: "Optional", filter: ".name[]?", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Run(tt.filter, sampleDoc)
+			if err != nil {
+				t.Fatalf("Run(%q) failed: %v", tt.filter, err)
+			}
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Run(%q) = %v, want %v", tt.filter, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter string
+		doc    string
+	}{
+		{name: "Syntax", filter: ".items[", doc: sampleDoc},
+		{name: "Trailing Garbage", filter: ".a )", doc: sampleDoc},
+		{name: "Unknown Function", filter: "frobnicate", doc: sampleDoc},
+		{name: "Index String", filter: ".name.x", doc: sampleDoc},
+		{name: "Iterate Number", filter: ".items[0].id[]", doc: sampleDoc},
+		{name: "Invalid Input", filter: ".", doc: `{"a":`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Run(tt.filter, tt.doc); err == nil {
+				t.Errorf("Run(%q) expected error, got nil", tt.filter)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package jsonfilter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// object is a JSON object that remembers key order, so filtered output keeps
+// the layout of the stored document.
+type object struct {
+	keys []string
+	vals map[string]any
+}
+
+func (o *object) get(key string) (any, bool) {
+	v, ok := o.vals[key]
+	return v, ok
+}
+
+// decode parses a JSON document into nil, bool, json.Number, string,
+// []any or *object values.
+func decode(doc string) (any, error) {
+	dec := json.NewDecoder(strings.NewReader(doc))
+	dec.UseNumber()
+	v, err := decodeValue(dec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("trailing data after JSON value")
+	}
+	return v, nil
+}
+
+func decodeValue(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return tok, nil
+	}
+
+	if delim == '[' {
+		arr := []any{}
+		for dec.More() {
+			v, err := decodeValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, v)
+		}
+		_, err := dec.Token() // ']'
+		return arr, err
+	}
+
+	obj := &object{vals: map[string]any{}}
+	for dec.More() {
+		keyTok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := keyTok.(string)
+		v, err := decodeValue(dec)
+		if err != nil {
+			return nil, err
+		}
+		if _, dup := obj.vals[key]; !dup {
+			obj.keys = append(obj.keys, key)
+		}
+		obj.vals[key] = v
+	}
+	_, err = dec.Token() // '}'
+	return obj, err
+}
+
+// encode writes v as compact JSON, preserving object key order.
+func encode(v any) string {
+	var buf bytes.Buffer
+	encodeTo(&buf, v)
+	return buf.String()
+}
+
+func encodeTo(buf *bytes.Buffer, v any) {
+	switch t := v.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		fmt.Fprint(buf, t)
+	case json.Number:
+		buf.WriteString(t.String())
+	case int:
+		fmt.Fprint(buf, t)
+	case string:
+		enc := json.NewEncoder(buf)
+		enc.SetEscapeHTML(false)
+		enc.Encode(t)
+		buf.Truncate(buf.Len() - 1) // Encode appends '\n'
+	case []any:
+		buf.WriteByte('[')
+		for i, e := range t {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			encodeTo(buf, e)
+		}
+		buf.WriteByte(']')
+	case *object:
+		buf.WriteByte('{')
+		for i, k := range t.keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			encodeTo(buf, k)
+			buf.WriteByte(':')
+			encodeTo(buf, t.vals[k])
+		}
+		buf.WriteByte('}')
+	}
+}
+
+// typeName returns the jq type name of v.
+func typeName(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number, int:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case *object:
+		return "object"
+	}
+	return "unknown"
+}
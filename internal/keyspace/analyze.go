@@ -0,0 +1,365 @@
+package keyspace
+
+import (
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/cosmez/redisman-go/internal/conn"
+	"github.com/cosmez/redisman-go/internal/resp"
+)
+
+// DefaultTop is how many of the biggest keys per type a report keeps.
+const DefaultTop = 10
+
+// lengthCommands maps a type to the command that returns its length.
+// Types not listed (module types) are reported without a length.
+var lengthCommands = map[string]string{
+	"string": "STRLEN",
+	"list":   "LLEN",
+	"hash":   "HLEN",
+	"set":    "SCARD",
+	"zset":   "ZCARD",
+	"stream": "XLEN",
+}
+
+// histogramBounds are the upper bounds in bytes of the memory histogram
+// buckets; the last bucket holds everything larger.
+var histogramBounds = []int64{1 << 10, 10 << 10, 100 << 10, 1 << 20, 10 << 20}
+
+// AnalyzeOptions configures Analyze.
+type AnalyzeOptions struct {
+	Pattern  string         // SCAN MATCH pattern, "*" if empty
+	Top      int            // biggest keys kept per type, DefaultTop if 0
+	Sample   float64        // fraction of scanned keys to analyze, all if 0 or 1
+	Limit    int            // stop after analyzing this many keys, 0 for no limit
+	Progress func(keys int) // called after each batch with the keys scanned, may be nil
+	Rand     func() float64 // random source for Sample, rand.Float64 if nil
+}
+
+// KeyStat is the size of one key.
+type KeyStat struct {
+	Key    string `json:"key"`
+	Type   string `json:"type"`
+	Memory int64  `json:"memory"`           // bytes from MEMORY USAGE, 0 if unavailable
+	Length int64  `json:"length,omitempty"` // bytes for strings, elements for collections
+}
+
+// TypeStats totals the analyzed keys of one type.
+type TypeStats struct {
+	Type   string    `json:"type"`
+	Keys   int       `json:"keys"`
+	Memory int64     `json:"memory"`
+	Length int64     `json:"length"`
+	Top    []KeyStat `json:"top"` // biggest keys by memory, largest first
+}
+
+// Bucket counts the keys whose memory is at most Max bytes (and above the
+// previous bucket). The last bucket has Max 0 and counts everything larger.
+type Bucket struct {
+	Max  int64 `json:"max"`
+	Keys int   `json:"keys"`
+}
+
+// Report is the result of Analyze. With sampling, counts and totals cover
+// only the analyzed keys; multiply by 1/Sample to estimate the keyspace.
+type Report struct {
+	Pattern   string      `json:"pattern"`
+	Scanned   int         `json:"scanned"`  // keys returned by SCAN
+	Analyzed  int         `json:"analyzed"` // keys measured
+	Sample    float64     `json:"sample"`   // fraction of scanned keys analyzed, 1 for all
+	Memory    int64       `json:"memory"`
+	Types     []TypeStats `json:"types"` // largest total memory first
+	Histogram []Bucket    `json:"histogram"`
+}
+
+// Analyze walks the keys matching opts.Pattern with SafeKeys and, per batch
+// of DefaultBatchSize, pipelines TYPE and MEMORY USAGE and then the length
+// command for each type. Keys deleted while analyzing are skipped. Only the
+// top keys are kept in memory, so the keyspace size does not matter.
+func Analyze(c *conn.Connection, opts AnalyzeOptions) (Report, error) {
+	if opts.Pattern == "" {
+		opts.Pattern = "*"
+	}
+	if opts.Top <= 0 {
+		opts.Top = DefaultTop
+	}
+	if opts.Sample <= 0 || opts.Sample > 1 {
+		opts.Sample = 1
+	}
+	random := rand.Float64
+	if opts.Rand != nil {
+		random = opts.Rand
+	}
+
+	report := Report{Pattern: opts.Pattern, Sample: opts.Sample}
+	types := map[string]*TypeStats{}
+	buckets := make([]int, len(histogramBounds)+1)
+
+	var batch []string
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		stats, err := measure(c, batch)
+		if err != nil {
+			return err
+		}
+		for _, st := range stats {
+			ts := types[st.Type]
+			if ts == nil {
+				ts = &TypeStats{Type: st.Type}
+				types[st.Type] = ts
+			}
+			ts.Keys++
+			ts.Memory += st.Memory
+			ts.Length += st.Length
+			ts.Top = addTop(ts.Top, st, opts.Top)
+
+			report.Analyzed++
+			report.Memory += st.Memory
+			buckets[bucketIndex(st.Memory)]++
+		}
+		batch = batch[:0]
+		if opts.Progress != nil {
+			opts.Progress(report.Scanned)
+		}
+		return nil
+	}
+
+	for key := range c.SafeKeys(opts.Pattern) {
+		if errResp, ok := key.(resp.RedisError); ok {
+			return report, fmt.Errorf("scan failed: %s", errResp.Value)
+		}
+		report.Scanned++
+		if opts.Sample < 1 && random() >= opts.Sample {
+			continue
+		}
+		batch = append(batch, key.StringValue())
+		if len(batch) == DefaultBatchSize || (opts.Limit > 0 && report.Analyzed+len(batch) >= opts.Limit) {
+			if err := flush(); err != nil {
+				return report, err
+			}
+			if opts.Limit > 0 && report.Analyzed >= opts.Limit {
+				break
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return report, err
+	}
+
+	for _, ts := range types {
+		report.Types = append(report.Types, *ts)
+	}
+	slices.SortFunc(report.Types, func(a, b TypeStats) int {
+		if a.Memory != b.Memory {
+			return cmpDesc(a.Memory, b.Memory)
+		}
+		return strings.Compare(a.Type, b.Type)
+	})
+	for i, n := range buckets {
+		var bound int64
+		if i < len(histogramBounds) {
+			bound = histogramBounds[i]
+		}
+		report.Histogram = append(report.Histogram, Bucket{Max: bound, Keys: n})
+	}
+	return report, nil
+}
+
+// measure pipelines TYPE and MEMORY USAGE for keys, then the length command
+// for each key's type. Keys that no longer exist are left out.
+func measure(c *conn.Connection, keys []string) ([]KeyStat, error) {
+	cmds := make([][]string, 0, len(keys)*2)
+	for _, key := range keys {
+		cmds = append(cmds, []string{"TYPE", key}, []string{"MEMORY", "USAGE", key})
+	}
+	replies, err := c.Pipeline(cmds)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]KeyStat, 0, len(keys))
+	var lenCmds [][]string
+	var lenIdx []int
+	for i, key := range keys {
+		typeName := replies[2*i].StringValue()
+		if _, ok := replies[2*i].(resp.RedisError); ok || typeName == "none" {
+			continue
+		}
+		st := KeyStat{Key: key, Type: typeName}
+		if n, ok := replies[2*i+1].(resp.RedisInteger); ok {
+			st.Memory = n.IntValue
+		}
+		if cmd, ok := lengthCommands[typeName]; ok {
+			lenCmds = append(lenCmds, []string{cmd, key})
+			lenIdx = append(lenIdx, len(stats))
+		}
+		stats = append(stats, st)
+	}
+
+	if len(lenCmds) > 0 {
+		lens, err := c.Pipeline(lenCmds)
+		if err != nil {
+			return nil, err
+		}
+		for i, reply := range lens {
+			if n, ok := reply.(resp.RedisInteger); ok {
+				stats[lenIdx[i]].Length = n.IntValue
+			}
+		}
+	}
+	return stats, nil
+}
+
+// addTop inserts st into top, which is sorted by memory (largest first)
+// and holds at most n keys.
+func addTop(top []KeyStat, st KeyStat, n int) []KeyStat {
+	if len(top) == n && st.Memory <= top[n-1].Memory {
+		return top
+	}
+	i, _ := slices.BinarySearchFunc(top, st, func(a, b KeyStat) int { return cmpDesc(a.Memory, b.Memory) })
+	top = slices.Insert(top, i, st)
+	if len(top) > n {
+		top = top[:n]
+	}
+	return top
+}
+
+func bucketIndex(memory int64) int {
+	for i, bound := range histogramBounds {
+		if memory <= bound {
+			return i
+		}
+	}
+	return len(histogramBounds)
+}
+
+// cmpDesc orders larger values first.
+func cmpDesc(a, b int64) int {
+	switch {
+	case a > b:
+		return -1
+	case a < b:
+		return 1
+	}
+	return 0
+}
+
+// FormatBytes renders n bytes with a binary unit, e.g. "1.5 MB".
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit && exp < 4; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTP"[exp])
+}
+
+// WriteText writes the report as aligned plain-text tables.
+func (r Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Analyzed %d of %d keys matching %s", r.Analyzed, r.Scanned, r.Pattern)
+	if r.Sample < 1 {
+		fmt.Fprintf(tw, " (%.0f%% sample)", r.Sample*100)
+	}
+	fmt.Fprintf(tw, ", %s total\n\n", FormatBytes(r.Memory))
+
+	fmt.Fprintln(tw, "TYPE\tKEYS\tMEMORY\tAVG\tELEMENTS")
+	for _, ts := range r.Types {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%d\n", ts.Type, ts.Keys, FormatBytes(ts.Memory), FormatBytes(ts.Memory/int64(ts.Keys)), ts.Length)
+	}
+
+	fmt.Fprintln(tw, "\nSIZE\tKEYS\t")
+	for _, b := range r.Histogram {
+		fmt.Fprintf(tw, "%s\t%d\t%s\n", bucketLabel(b), b.Keys, histogramBar(b.Keys, r.Analyzed))
+	}
+
+	for _, ts := range r.Types {
+		fmt.Fprintf(tw, "\nBIGGEST %s KEYS\tMEMORY\tLENGTH\n", strings.ToUpper(ts.Type))
+		for _, st := range ts.Top {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", st.Key, FormatBytes(st.Memory), lengthLabel(st))
+		}
+	}
+	return tw.Flush()
+}
+
+func bucketLabel(b Bucket) string {
+	if b.Max == 0 {
+		return "> " + FormatBytes(histogramBounds[len(histogramBounds)-1])
+	}
+	return "<= " + FormatBytes(b.Max)
+}
+
+// histogramBar renders n out of total as a bar of up to 40 characters.
+func histogramBar(n, total int) string {
+	if total == 0 || n == 0 {
+		return ""
+	}
+	return strings.Repeat("#", max(1, 40*n/total))
+}
+
+func lengthLabel(st KeyStat) string {
+	if _, ok := lengthCommands[st.Type]; !ok {
+		return "-"
+	}
+	if st.Type == "string" {
+		return FormatBytes(st.Length)
+	}
+	return strconv.FormatInt(st.Length, 10)
+}
+
+// ParseAnalyzeArgs parses the arguments of the ANALYZE built-in:
+// [pattern] [TOP n] [SAMPLE fraction] [LIMIT n] [JSON].
+func ParseAnalyzeArgs(args []string) (opts AnalyzeOptions, asJSON bool, err error) {
+	for i := 0; i < len(args); i++ {
+		opt := strings.ToUpper(args[i])
+		switch opt {
+		case "JSON":
+			asJSON = true
+			continue
+		case "TOP", "SAMPLE", "LIMIT":
+		default:
+			if i > 0 || opts.Pattern != "" {
+				return opts, false, fmt.Errorf("unknown option %q", args[i])
+			}
+			opts.Pattern = args[i]
+			continue
+		}
+
+		if i+1 >= len(args) {
+			return opts, false, fmt.Errorf("%s needs a value", opt)
+		}
+		val := args[i+1]
+		i++
+		switch opt {
+		case "TOP", "LIMIT":
+			n, err := strconv.Atoi(val)
+			if err != nil || n <= 0 {
+				return opts, false, fmt.Errorf("%s must be a positive integer", opt)
+			}
+			if opt == "TOP" {
+				opts.Top = n
+			} else {
+				opts.Limit = n
+			}
+		case "SAMPLE":
+			f, err := strconv.ParseFloat(val, 64)
+			if err != nil || f <= 0 || f > 1 {
+				return opts, false, fmt.Errorf("SAMPLE must be a fraction between 0 and 1")
+			}
+			opts.Sample = f
+		}
+	}
+	return opts, asJSON, nil
+}
@@ -0,0 +1,178 @@
+package keyspace
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cosmez/redisman-go/internal/conn"
+	"github.com/cosmez/redisman-go/internal/redistest"
+)
+
+// fakeSize is what the analyze server reports for a key.
+type fakeSize struct {
+	typeName string
+	memory   int64
+	length   int64
+}
+
+// newAnalyzeServer answers SCAN, TYPE, MEMORY USAGE and the length commands
+// for keys, listed in SCAN order by names.
+func newAnalyzeServer(t *testing.T, names []string, keys map[string]fakeSize) *conn.Connection {
+	t.Helper()
+	s := redistest.NewServer(t)
+	s.Handle("SCAN", func(args []string) string {
+		return redistest.Array(redistest.Bulk("0"), redistest.BulkArray(names...))
+	})
+	s.Handle("TYPE", func(args []string) string {
+		if k, ok := keys[args[1]]; ok {
+			return redistest.Simple(k.typeName)
+		}
+		return redistest.Simple("none")
+	})
+	s.Handle("MEMORY", func(args []string) string {
+		if k, ok := keys[args[2]]; ok {
+			return redistest.Int(k.memory)
+		}
+		return redistest.NullBulk
+	})
+	for _, cmd := range []string{"STRLEN", "LLEN", "HLEN", "SCARD", "ZCARD", "XLEN"} {
+		s.Handle(cmd, func(args []string) string { return redistest.Int(keys[args[1]].length) })
+	}
+
+	c, err := conn.Connect(s.Host, s.Port, "", "")
+	if err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestAnalyze(t *testing.T) {
+	keys := map[string]fakeSize{
+		"s:small": {"string", 100, 10},
+		"s:big":   {"string", 2 << 20, 2 << 20},
+		"l:1":     {"list", 5000, 40},
+		"l:2":     {"list", 50000, 400},
+		"l:3":     {"list", 800, 3},
+		"json:1":  {"ReJSON-RL", 300, 0},
+	}
+	c := newAnalyzeServer(t, []string{"s:small", "s:big", "l:1", "l:2", "l:3", "json:1", "gone"}, keys)
+
+	report, err := Analyze(c, AnalyzeOptions{Top: 2})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if report.Scanned != 7 || report.Analyzed != 6 {
+		t.Errorf("Scanned %d, analyzed %d; want 7, 6", report.Scanned, report.Analyzed)
+	}
+	if report.Memory != 100+2<<20+5000+50000+800+300 {
+		t.Errorf("Memory = %d", report.Memory)
+	}
+
+	if len(report.Types) != 3 || report.Types[0].Type != "string" || report.Types[1].Type != "list" {
+		t.Fatalf("Types = %+v, want string, list, ReJSON-RL by memory", report.Types)
+	}
+	lists := report.Types[1]
+	if lists.Keys != 3 || lists.Length != 443 {
+		t.Errorf("List totals = %+v", lists)
+	}
+	if len(lists.Top) != 2 || lists.Top[0].Key != "l:2" || lists.Top[1].Key != "l:1" {
+		t.Errorf("List top = %+v, want l:2, l:1", lists.Top)
+	}
+	if json := report.Types[2]; json.Top[0].Length != 0 {
+		t.Errorf("Module type should have no length, got %+v", json.Top[0])
+	}
+
+	// Buckets: <=1K: small, l:3, json:1; <=10K: l:1; <=100K: l:2; >10M: none; s:big is 2M.
+	wantBuckets := []int{3, 1, 1, 0, 1, 0}
+	for i, b := range report.Histogram {
+		if b.Keys != wantBuckets[i] {
+			t.Errorf("Histogram = %+v, want counts %v", report.Histogram, wantBuckets)
+			break
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteText(&buf); err != nil {
+		t.Fatalf("WriteText failed: %v", err)
+	}
+	for _, want := range []string{"Analyzed 6 of 7 keys", "BIGGEST LIST KEYS", "s:big", "2.0 MB", "<= 1.0 KB"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Text report missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestAnalyzeSampleAndLimit(t *testing.T) {
+	var names []string
+	keys := map[string]fakeSize{}
+	for i := range 250 {
+		name := fmt.Sprintf("k%03d", i)
+		names = append(names, name)
+		keys[name] = fakeSize{"string", int64(i), 1}
+	}
+	c := newAnalyzeServer(t, names, keys)
+
+	// Every other key is sampled.
+	n := 0
+	report, err := Analyze(c, AnalyzeOptions{Sample: 0.5, Rand: func() float64 { n++; return float64(n%2) * 0.9 }})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if report.Scanned != 250 || report.Analyzed != 125 || report.Sample != 0.5 {
+		t.Errorf("Sampled report: scanned %d, analyzed %d, sample %v", report.Scanned, report.Analyzed, report.Sample)
+	}
+
+	report, err = Analyze(c, AnalyzeOptions{Limit: 30})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if report.Analyzed != 30 {
+		t.Errorf("Limited report analyzed %d keys, want 30", report.Analyzed)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1536, "1.5 KB"},
+		{5 << 20, "5.0 MB"},
+		{3 << 30, "3.0 GB"},
+	}
+	for _, tt := range tests {
+		if got := FormatBytes(tt.n); got != tt.want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestParseAnalyzeArgs(t *testing.T) {
+	opts, asJSON, err := ParseAnalyzeArgs([]string{"user:*", "top", "5", "SAMPLE", "0.1", "LIMIT", "1000", "json"})
+	if err != nil {
+		t.Fatalf("ParseAnalyzeArgs failed: %v", err)
+	}
+	if opts.Pattern != "user:*" || opts.Top != 5 || opts.Sample != 0.1 || opts.Limit != 1000 || !asJSON {
+		t.Errorf("ParseAnalyzeArgs() = %+v, %v", opts, asJSON)
+	}
+
+	if opts, _, err := ParseAnalyzeArgs(nil); err != nil || opts.Pattern != "" {
+		t.Errorf("ParseAnalyzeArgs(nil) = %+v, %v", opts, err)
+	}
+
+	for _, args := range [][]string{
+		{"*", "TOP"},
+		{"*", "TOP", "0"},
+		{"*", "SAMPLE", "2"},
+		{"*", "extra"},
+	} {
+		if _, _, err := ParseAnalyzeArgs(args); err == nil {
+			t.Errorf("ParseAnalyzeArgs(%q) expected error", args)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package keyspace
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cosmez/redisman-go/internal/conn"
+	"github.com/cosmez/redisman-go/internal/resp"
+)
+
+// DefaultBatchSize is how many keys are sent per pipeline.
+const DefaultBatchSize = 100
+
+// SampleSize is how many key names a preview shows before confirmation.
+const SampleSize = 10
+
+// maxErrors caps Result.Errors.
+const maxErrors = 10
+
+// Options configures Delete and Expire.
+type Options struct {
+	BatchSize int                   // keys per pipeline, DefaultBatchSize if 0
+	Rate      int                   // maximum keys per second, 0 for no limit
+	Progress  func(done, total int) // called after each batch, may be nil
+	Now       func() time.Time      // clock for rate limiting, time.Now if nil
+	Sleep     func(d time.Duration) // used for rate limiting, time.Sleep if nil
+}
+
+// Result counts what a bulk operation did with each key.
+type Result struct {
+	Done    int     // keys unlinked or given a TTL
+	Missing int     // keys that no longer existed
+	Failed  int     // keys the server returned an error for
+	Errors  []error // first few failures, for reporting
+}
+
+// Scan collects every key matching pattern. Keys are gathered before any
+// bulk operation so that a preview can be shown and the count confirmed.
+// progress, if not nil, is called every DefaultBatchSize keys.
+func Scan(c *conn.Connection, pattern string, progress func(keys int)) ([]string, error) {
+	var keys []string
+	for key := range c.SafeKeys(pattern) {
+		if errResp, ok := key.(resp.RedisError); ok {
+			return keys, fmt.Errorf("scan failed: %s", errResp.Value)
+		}
+		keys = append(keys, key.StringValue())
+		if progress != nil && len(keys)%DefaultBatchSize == 0 {
+			progress(len(keys))
+		}
+	}
+	return keys, nil
+}
+
+// Sample returns up to SampleSize keys for a preview.
+func Sample(keys []string) []string {
+	return keys[:min(len(keys), SampleSize)]
+}
+
+// Delete UNLINKs keys, one command per key in pipelined batches, so that
+// keys in different cluster slots never share a command.
+func Delete(c *conn.Connection, keys []string, opts Options) (Result, error) {
+	return run(c, keys, opts, func(key string) []string {
+		return []string{"UNLINK", key}
+	})
+}
+
+// Expire sets a TTL of seconds on keys in pipelined batches.
+func Expire(c *conn.Connection, keys []string, seconds int64, opts Options) (Result, error) {
+	ttl := strconv.FormatInt(seconds, 10)
+	return run(c, keys, opts, func(key string) []string {
+		return []string{"EXPIRE", key, ttl}
+	})
+}
+
+// run pipelines cmd(key) for every key in batches, honoring opts.Rate. The
+// command must reply 1 when it acted on the key and 0 when it was missing.
+func run(c *conn.Connection, keys []string, opts Options, cmd func(key string) []string) (Result, error) {
+	var result Result
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+	if opts.Rate > 0 && opts.Rate < opts.BatchSize {
+		opts.BatchSize = opts.Rate
+	}
+	now, sleep := time.Now, time.Sleep
+	if opts.Now != nil {
+		now = opts.Now
+	}
+	if opts.Sleep != nil {
+		sleep = opts.Sleep
+	}
+
+	start := now()
+	for done := 0; done < len(keys); {
+		if opts.Rate > 0 {
+			due := start.Add(time.Duration(done) * time.Second / time.Duration(opts.Rate))
+			if wait := due.Sub(now()); wait > 0 {
+				sleep(wait)
+			}
+		}
+
+		batch := keys[done:min(done+opts.BatchSize, len(keys))]
+		cmds := make([][]string, len(batch))
+		for i, key := range batch {
+			cmds[i] = cmd(key)
+		}
+		replies, err := c.Pipeline(cmds)
+		if err != nil {
+			return result, err
+		}
+		for i, reply := range replies {
+			switch v := reply.(type) {
+			case resp.RedisError:
+				result.Failed++
+				if len(result.Errors) < maxErrors {
+					result.Errors = append(result.Errors, fmt.Errorf("%s: %s", batch[i], v.Value))
+				}
+			case resp.RedisInteger:
+				if v.IntValue == 0 {
+					result.Missing++
+				} else {
+					result.Done++
+				}
+			default:
+				result.Done++
+			}
+		}
+
+		done += len(batch)
+		if opts.Progress != nil {
+			opts.Progress(done, len(keys))
+		}
+	}
+	return result, nil
+}
+
+// ParseArgs parses the arguments of DELPATTERN (pattern [RATE n]) and, with
+// withTTL, EXPIREPATTERN (pattern seconds [RATE n]). seconds is 0 without
+// withTTL.
+func ParseArgs(args []string, withTTL bool) (pattern string, seconds int64, opts Options, err error) {
+	need := 1
+	if withTTL {
+		need = 2
+	}
+	if len(args) < need {
+		return "", 0, opts, fmt.Errorf("missing arguments")
+	}
+	pattern = args[0]
+	if withTTL {
+		seconds, err = strconv.ParseInt(args[1], 10, 64)
+		if err != nil || seconds <= 0 {
+			return "", 0, opts, fmt.Errorf("seconds must be a positive integer")
+		}
+	}
+
+	rest := args[need:]
+	for i := 0; i < len(rest); i++ {
+		switch strings.ToUpper(rest[i]) {
+		case "RATE":
+			if i+1 >= len(rest) {
+				return "", 0, opts, fmt.Errorf("RATE must be followed by keys per second")
+			}
+			n, err := strconv.Atoi(rest[i+1])
+			if err != nil || n < 0 {
+				return "", 0, opts, fmt.Errorf("RATE must be followed by keys per second")
+			}
+			opts.Rate = n
+			i++
+		default:
+			return "", 0, opts, fmt.Errorf("unknown option %q", rest[i])
+		}
+	}
+	return pattern, seconds, opts, nil
+}
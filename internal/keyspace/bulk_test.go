@@ -0,0 +1,183 @@
+package keyspace
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cosmez/redisman-go/internal/conn"
+	"github.com/cosmez/redisman-go/internal/redistest"
+)
+
+// newBulkServer serves keys from SCAN and answers UNLINK and EXPIRE with 1
+// for keys it holds, 0 for others and an error for "bad".
+func newBulkServer(t *testing.T, keys ...string) (*conn.Connection, *redistest.Server) {
+	t.Helper()
+	var mu sync.Mutex
+	held := map[string]bool{}
+	for _, k := range keys {
+		held[k] = true
+	}
+
+	s := redistest.NewServer(t)
+	s.Handle("SCAN", func(args []string) string {
+		return redistest.Array(redistest.Bulk("0"), redistest.BulkArray(keys...))
+	})
+	reply := func(args []string) string {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case args[1] == "bad":
+			return redistest.Err("WRONGTYPE nope")
+		case held[args[1]]:
+			if args[0] == "UNLINK" {
+				delete(held, args[1])
+			}
+			return redistest.Int(1)
+		}
+		return redistest.Int(0)
+	}
+	s.Handle("UNLINK", reply)
+	s.Handle("EXPIRE", reply)
+
+	c, err := conn.Connect(s.Host, s.Port, "", "")
+	if err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c, s
+}
+
+func TestDelete(t *testing.T) {
+	c, s := newBulkServer(t, "tmp:1", "tmp:2", "bad")
+
+	keys, err := Scan(c, "tmp:*", nil)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	keys = append(keys, "tmp:gone")
+
+	var progress []int
+	result, err := Delete(c, keys, Options{
+		BatchSize: 2,
+		Progress:  func(done, total int) { progress = append(progress, done, total) },
+	})
+	if err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if result.Done != 2 || result.Missing != 1 || result.Failed != 1 || len(result.Errors) != 1 {
+		t.Errorf("Delete() = %+v", result)
+	}
+	if !slices.Equal(progress, []int{2, 4, 4, 4}) {
+		t.Errorf("Progress calls = %v", progress)
+	}
+
+	var unlinked []string
+	for _, cmd := range s.Commands() {
+		if cmd[0] == "UNLINK" {
+			if len(cmd) != 2 {
+				t.Errorf("UNLINK should take one key, got %q", cmd)
+			}
+			unlinked = append(unlinked, cmd[1])
+		}
+	}
+	if !slices.Equal(unlinked, keys) {
+		t.Errorf("UNLINKed %q, want %q", unlinked, keys)
+	}
+}
+
+func TestExpire(t *testing.T) {
+	c, s := newBulkServer(t, "a", "b")
+
+	result, err := Expire(c, []string{"a", "b"}, 60, Options{})
+	if err != nil {
+		t.Fatalf("Expire failed: %v", err)
+	}
+	if result.Done != 2 {
+		t.Errorf("Expire() = %+v", result)
+	}
+	for _, cmd := range s.Commands() {
+		if cmd[0] == "EXPIRE" && cmd[2] != "60" {
+			t.Errorf("EXPIRE sent %q", cmd)
+		}
+	}
+}
+
+func TestRate(t *testing.T) {
+	var keys []string
+	for i := range 25 {
+		keys = append(keys, fmt.Sprintf("k%02d", i))
+	}
+	c, _ := newBulkServer(t, keys...)
+
+	clock := time.Unix(1700000000, 0)
+	var slept time.Duration
+	var batches []int
+	_, err := Delete(c, keys, Options{
+		Rate:     10,
+		Now:      func() time.Time { return clock },
+		Sleep:    func(d time.Duration) { slept += d; clock = clock.Add(d) },
+		Progress: func(done, total int) { batches = append(batches, done) },
+	})
+	if err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if !slices.Equal(batches, []int{10, 20, 25}) {
+		t.Errorf("Batches = %v, want [10 20 25]", batches)
+	}
+	if slept != 2*time.Second {
+		t.Errorf("Slept %v, want 2s for 25 keys at 10/s", slept)
+	}
+}
+
+func TestSample(t *testing.T) {
+	keys := make([]string, 25)
+	if got := len(Sample(keys)); got != SampleSize {
+		t.Errorf("len(Sample(25 keys)) = %d, want %d", got, SampleSize)
+	}
+	if got := len(Sample(keys[:3])); got != 3 {
+		t.Errorf("len(Sample(3 keys)) = %d, want 3", got)
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		withTTL     bool
+		wantPattern string
+		wantSeconds int64
+		wantRate    int
+		wantErr     bool
+	}{
+		{name: "Delete", args: []string{"tmp:*"}, wantPattern: "tmp:*"},
+		{name: "Delete Rate", args: []string{"tmp:*", "rate", "50"}, wantPattern: "tmp:*", wantRate: 50},
+		{name: "Expire", args: []string{"s:*", "3600"}, withTTL: true, wantPattern: "s:*", wantSeconds: 3600},
+		{name: "Expire Rate", args: []string{"s:*", "60", "RATE", "5"}, withTTL: true, wantPattern: "s:*", wantSeconds: 60, wantRate: 5},
+		{name: "Missing Pattern", args: nil, wantErr: true},
+		{name: "Missing Seconds", args: []string{"s:*"}, withTTL: true, wantErr: true},
+		{name: "Zero Seconds", args: []string{"s:*", "0"}, withTTL: true, wantErr: true},
+		{name: "Rate Without Value", args: []string{"tmp:*", "RATE"}, wantErr: true},
+		{name: "Unknown Option", args: []string{"tmp:*", "FAST"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pattern, seconds, opts, err := ParseArgs(tt.args, tt.withTTL)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseArgs failed: %v", err)
+			}
+			if pattern != tt.wantPattern || seconds != tt.wantSeconds || opts.Rate != tt.wantRate {
+				t.Errorf("ParseArgs() = %q, %d, rate %d", pattern, seconds, opts.Rate)
+			}
+		})
+	}
+}
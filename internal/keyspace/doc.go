@@ -0,0 +1,6 @@
+// Package keyspace runs bulk operations and size reports over the keys
+// matching a pattern, walking them with SafeKeys so the server is never
+// blocked by KEYS, and the single-key rename, duplicate and move actions.
+//
+// C#: No direct equivalent — the C# version had no bulk operations or reports.
+package keyspace
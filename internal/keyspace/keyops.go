@@ -0,0 +1,168 @@
+package keyspace
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cosmez/redisman-go/internal/conn"
+	"github.com/cosmez/redisman-go/internal/resp"
+)
+
+// Single-key operations.
+const (
+	OpRename    = "rename"
+	OpDuplicate = "duplicate"
+	OpMove      = "move"
+)
+
+// KeyAction renames, duplicates or moves one key.
+type KeyAction struct {
+	Op      string // OpRename, OpDuplicate or OpMove
+	Key     string
+	Dest    string // new name, unused by OpMove
+	DB      int    // target database for OpMove, and for OpDuplicate if >= 0
+	Replace bool   // overwrite an existing destination (RENAME instead of RENAMENX, COPY ... REPLACE)
+}
+
+// Command returns the Redis command that performs a.
+func (a KeyAction) Command() []string {
+	switch a.Op {
+	case OpRename:
+		if a.Replace {
+			return []string{"RENAME", a.Key, a.Dest}
+		}
+		return []string{"RENAMENX", a.Key, a.Dest}
+	case OpDuplicate:
+		args := []string{"COPY", a.Key, a.Dest}
+		if a.DB >= 0 {
+			args = append(args, "DB", strconv.Itoa(a.DB))
+		}
+		if a.Replace {
+			args = append(args, "REPLACE")
+		}
+		return args
+	default:
+		return []string{"MOVE", a.Key, strconv.Itoa(a.DB)}
+	}
+}
+
+// Describe returns a short sentence for confirmations, e.g.
+// `Rename "a" to "b"`.
+func (a KeyAction) Describe() string {
+	switch a.Op {
+	case OpRename:
+		return fmt.Sprintf("Rename %q to %q", a.Key, a.Dest)
+	case OpDuplicate:
+		if a.DB >= 0 {
+			return fmt.Sprintf("Copy %q to %q in db %d", a.Key, a.Dest, a.DB)
+		}
+		return fmt.Sprintf("Copy %q to %q", a.Key, a.Dest)
+	default:
+		return fmt.Sprintf("Move %q to db %d", a.Key, a.DB)
+	}
+}
+
+// Preview describes what a will do: the source key's type and TTL and, for
+// a destination in the current database, whether it exists and would be
+// overwritten. It fails if the source key does not exist.
+func (a KeyAction) Preview(c *conn.Connection) ([]string, error) {
+	cmds := [][]string{{"TYPE", a.Key}, {"PTTL", a.Key}}
+	sameDB := a.Op == OpRename || (a.Op == OpDuplicate && a.DB < 0)
+	if sameDB {
+		cmds = append(cmds, []string{"EXISTS", a.Dest})
+	}
+	replies, err := c.Pipeline(cmds)
+	if err != nil {
+		return nil, err
+	}
+	if errResp, ok := replies[0].(resp.RedisError); ok {
+		return nil, fmt.Errorf("TYPE failed: %s", errResp.Value)
+	}
+	typeName := replies[0].StringValue()
+	if typeName == "none" {
+		return nil, fmt.Errorf("key %q does not exist", a.Key)
+	}
+
+	ttl := "no TTL"
+	if n, ok := replies[1].(resp.RedisInteger); ok && n.IntValue >= 0 {
+		ttl = "TTL " + (time.Duration(n.IntValue) * time.Millisecond).Round(time.Second).String()
+	}
+	lines := []string{a.Describe(), fmt.Sprintf("%s: %s, %s", a.Key, typeName, ttl)}
+
+	if sameDB {
+		if n, ok := replies[2].(resp.RedisInteger); ok && n.IntValue > 0 {
+			if a.Replace {
+				lines = append(lines, fmt.Sprintf("%s exists and will be overwritten", a.Dest))
+			} else {
+				lines = append(lines, fmt.Sprintf("%s already exists; it is kept unless REPLACE is given", a.Dest))
+			}
+		} else {
+			lines = append(lines, fmt.Sprintf("%s does not exist", a.Dest))
+		}
+	}
+	return lines, nil
+}
+
+// Run performs a. A refusal (destination exists, source gone) is an error.
+func (a KeyAction) Run(c *conn.Connection) error {
+	reply, err := c.Do(a.Command()...)
+	if err != nil {
+		return err
+	}
+	if n, ok := reply.(resp.RedisInteger); ok && n.IntValue == 0 {
+		switch a.Op {
+		case OpMove:
+			return fmt.Errorf("%q was not moved: it exists in db %d or is gone", a.Key, a.DB)
+		default:
+			return fmt.Errorf("%q already exists (use REPLACE to overwrite)", a.Dest)
+		}
+	}
+	return nil
+}
+
+// ParseKeyActionArgs parses the arguments of RENAME-KEY (key newname
+// [REPLACE]), DUPLICATE-KEY (key dest [DB n] [REPLACE]) and MOVE-KEY
+// (key db).
+func ParseKeyActionArgs(op string, args []string) (KeyAction, error) {
+	a := KeyAction{Op: op, DB: -1}
+	if op == OpMove {
+		if len(args) != 2 {
+			return a, fmt.Errorf("expected a key and a database")
+		}
+		db, err := strconv.Atoi(args[1])
+		if err != nil || db < 0 {
+			return a, fmt.Errorf("database must be a non-negative integer")
+		}
+		a.Key, a.DB = args[0], db
+		return a, nil
+	}
+
+	if len(args) < 2 {
+		return a, fmt.Errorf("expected a key and a destination")
+	}
+	a.Key, a.Dest = args[0], args[1]
+	for i := 2; i < len(args); i++ {
+		switch opt := strings.ToUpper(args[i]); {
+		case opt == "REPLACE":
+			a.Replace = true
+		case opt == "DB" && op == OpDuplicate:
+			if i+1 >= len(args) {
+				return a, fmt.Errorf("DB needs a value")
+			}
+			db, err := strconv.Atoi(args[i+1])
+			if err != nil || db < 0 {
+				return a, fmt.Errorf("DB must be a non-negative integer")
+			}
+			a.DB = db
+			i++
+		default:
+			return a, fmt.Errorf("unknown option %q", args[i])
+		}
+	}
+	if a.Key == a.Dest && a.DB < 0 {
+		return a, fmt.Errorf("source and destination are the same key")
+	}
+	return a, nil
+}
@@ -0,0 +1,118 @@
+package keyspace
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/cosmez/redisman-go/internal/conn"
+	"github.com/cosmez/redisman-go/internal/redistest"
+)
+
+func TestParseKeyActionArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		op      string
+		args    []string
+		want    []string
+		wantErr bool
+	}{
+		{"Rename", OpRename, []string{"a", "b"}, []string{"RENAMENX", "a", "b"}, false},
+		{"Rename Replace", OpRename, []string{"a", "b", "replace"}, []string{"RENAME", "a", "b"}, false},
+		{"Rename Same Key", OpRename, []string{"a", "a"}, nil, true},
+		{"Rename DB", OpRename, []string{"a", "b", "DB", "1"}, nil, true},
+		{"Duplicate", OpDuplicate, []string{"a", "b"}, []string{"COPY", "a", "b"}, false},
+		{"Duplicate DB Replace", OpDuplicate, []string{"a", "a", "DB", "2", "REPLACE"}, []string{"COPY", "a", "a", "DB", "2", "REPLACE"}, false},
+		{"Duplicate Bad DB", OpDuplicate, []string{"a", "b", "DB", "x"}, nil, true},
+		{"Duplicate Missing DB", OpDuplicate, []string{"a", "b", "DB"}, nil, true},
+		{"Move", OpMove, []string{"a", "3"}, []string{"MOVE", "a", "3"}, false},
+		{"Move Negative", OpMove, []string{"a", "-1"}, nil, true},
+		{"Too Few", OpDuplicate, []string{"a"}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := ParseKeyActionArgs(tt.op, tt.args)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %+v", a)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := a.Command(); !slices.Equal(got, tt.want) {
+				t.Errorf("Command() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// newKeyOpsServer answers TYPE, PTTL, EXISTS, RENAMENX and MOVE for the
+// keys in types; "taken" is an existing destination.
+func newKeyOpsServer(t *testing.T, types map[string]string) *conn.Connection {
+	t.Helper()
+	s := redistest.NewServer(t)
+	s.Handle("TYPE", func(args []string) string {
+		if typ, ok := types[args[1]]; ok {
+			return redistest.Simple(typ)
+		}
+		return redistest.Simple("none")
+	})
+	s.Handle("PTTL", func(args []string) string { return redistest.Int(90000) })
+	s.Handle("EXISTS", func(args []string) string {
+		if _, ok := types[args[1]]; ok {
+			return redistest.Int(1)
+		}
+		return redistest.Int(0)
+	})
+	s.Handle("RENAMENX", func(args []string) string {
+		if _, ok := types[args[2]]; ok {
+			return redistest.Int(0)
+		}
+		return redistest.Int(1)
+	})
+	s.Handle("MOVE", func(args []string) string { return redistest.Int(1) })
+
+	c, err := conn.Connect(s.Host, s.Port, "", "")
+	if err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestKeyActionPreviewAndRun(t *testing.T) {
+	c := newKeyOpsServer(t, map[string]string{"user:1": "hash", "taken": "string"})
+
+	rename := KeyAction{Op: OpRename, Key: "user:1", Dest: "taken", DB: -1}
+	lines, err := rename.Preview(c)
+	if err != nil {
+		t.Fatalf("Preview failed: %v", err)
+	}
+	preview := strings.Join(lines, "\n")
+	for _, want := range []string{`Rename "user:1" to "taken"`, "user:1: hash, TTL 1m30s", "taken already exists"} {
+		if !strings.Contains(preview, want) {
+			t.Errorf("Preview missing %q:\n%s", want, preview)
+		}
+	}
+	if err := rename.Run(c); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("Run onto an existing key: err = %v", err)
+	}
+
+	rename.Dest = "user:2"
+	if err := rename.Run(c); err != nil {
+		t.Errorf("Run failed: %v", err)
+	}
+
+	// A move has no destination name, so EXISTS is not asked.
+	move := KeyAction{Op: OpMove, Key: "user:1", DB: 1}
+	if lines, err := move.Preview(c); err != nil || len(lines) != 2 {
+		t.Errorf("Move preview = %q, %v", lines, err)
+	}
+
+	missing := KeyAction{Op: OpDuplicate, Key: "nope", Dest: "x", DB: -1}
+	if _, err := missing.Preview(c); err == nil {
+		t.Error("Preview of a missing key should fail")
+	}
+}
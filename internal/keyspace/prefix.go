@@ -0,0 +1,234 @@
+package keyspace
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/cosmez/redisman-go/internal/conn"
+	"github.com/cosmez/redisman-go/internal/resp"
+)
+
+// Prefix report defaults.
+const (
+	DefaultDepth     = 2
+	DefaultDelimiter = ":"
+)
+
+// NoPrefix groups keys that do not contain the delimiter.
+const NoPrefix = "(no prefix)"
+
+// PrefixOptions configures Prefixes.
+type PrefixOptions struct {
+	Pattern   string         // SCAN MATCH pattern, "*" if empty
+	Depth     int            // prefix levels to group by, DefaultDepth if 0
+	Delimiter string         // separates key name segments, DefaultDelimiter if empty
+	Progress  func(keys int) // called after each batch with the keys scanned, may be nil
+}
+
+// PrefixStats aggregates the keys under one prefix.
+type PrefixStats struct {
+	Prefix   string         `json:"prefix"` // including the trailing delimiter, e.g. "user:session:"
+	Keys     int            `json:"keys"`
+	Memory   int64          `json:"memory"`   // bytes from MEMORY USAGE
+	WithTTL  int            `json:"with_ttl"` // keys that have an expiry
+	Children []*PrefixStats `json:"children,omitempty"`
+}
+
+// TTLCoverage returns the fraction of keys that have an expiry.
+func (p *PrefixStats) TTLCoverage() float64 {
+	if p.Keys == 0 {
+		return 0
+	}
+	return float64(p.WithTTL) / float64(p.Keys)
+}
+
+// PrefixReport is the result of Prefixes: a tree of prefixes, each level
+// sorted by memory, then key count, largest first.
+type PrefixReport struct {
+	Pattern   string         `json:"pattern"`
+	Delimiter string         `json:"delimiter"`
+	Depth     int            `json:"depth"`
+	Total     PrefixStats    `json:"total"`
+	Prefixes  []*PrefixStats `json:"prefixes"`
+}
+
+// Prefixes walks the keys matching opts.Pattern with SafeKeys, pipelines
+// MEMORY USAGE and PTTL for each batch, and aggregates the results by key
+// prefix. A key with n segments counts toward its first min(n-1, Depth)
+// prefixes, so "svc:user:42" at depth 2 counts toward "svc:" and
+// "svc:user:". Keys without the delimiter are grouped under NoPrefix.
+func Prefixes(c *conn.Connection, opts PrefixOptions) (PrefixReport, error) {
+	if opts.Pattern == "" {
+		opts.Pattern = "*"
+	}
+	if opts.Depth <= 0 {
+		opts.Depth = DefaultDepth
+	}
+	if opts.Delimiter == "" {
+		opts.Delimiter = DefaultDelimiter
+	}
+
+	report := PrefixReport{Pattern: opts.Pattern, Delimiter: opts.Delimiter, Depth: opts.Depth}
+	report.Total.Prefix = "*"
+	index := map[string]*PrefixStats{} // every node by prefix
+
+	add := func(key string, memory int64, hasTTL bool) {
+		count := func(p *PrefixStats) {
+			p.Keys++
+			p.Memory += memory
+			if hasTTL {
+				p.WithTTL++
+			}
+		}
+		count(&report.Total)
+
+		segments := strings.Split(key, opts.Delimiter)
+		levels := min(len(segments)-1, opts.Depth)
+		if levels == 0 {
+			segments, levels = []string{NoPrefix}, 1
+		}
+
+		siblings := &report.Prefixes
+		for level := 1; level <= levels; level++ {
+			prefix := strings.Join(segments[:level], opts.Delimiter) + opts.Delimiter
+			if segments[0] == NoPrefix {
+				prefix = NoPrefix
+			}
+			node := index[prefix]
+			if node == nil {
+				node = &PrefixStats{Prefix: prefix}
+				index[prefix] = node
+				*siblings = append(*siblings, node)
+			}
+			count(node)
+			siblings = &node.Children
+		}
+	}
+
+	var batch []string
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		cmds := make([][]string, 0, len(batch)*2)
+		for _, key := range batch {
+			cmds = append(cmds, []string{"MEMORY", "USAGE", key}, []string{"PTTL", key})
+		}
+		replies, err := c.Pipeline(cmds)
+		if err != nil {
+			return err
+		}
+		for i, key := range batch {
+			pttl, ok := replies[2*i+1].(resp.RedisInteger)
+			if ok && pttl.IntValue == -2 {
+				continue // deleted since SCAN
+			}
+			var memory int64
+			if n, ok := replies[2*i].(resp.RedisInteger); ok {
+				memory = n.IntValue
+			}
+			add(key, memory, ok && pttl.IntValue > 0)
+		}
+		batch = batch[:0]
+		if opts.Progress != nil {
+			opts.Progress(report.Total.Keys)
+		}
+		return nil
+	}
+
+	for key := range c.SafeKeys(opts.Pattern) {
+		if errResp, ok := key.(resp.RedisError); ok {
+			return report, fmt.Errorf("scan failed: %s", errResp.Value)
+		}
+		batch = append(batch, key.StringValue())
+		if len(batch) == DefaultBatchSize {
+			if err := flush(); err != nil {
+				return report, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return report, err
+	}
+
+	sortPrefixes(report.Prefixes)
+	return report, nil
+}
+
+// sortPrefixes sorts each level by memory, then key count, then name.
+func sortPrefixes(nodes []*PrefixStats) {
+	slices.SortFunc(nodes, func(a, b *PrefixStats) int {
+		if a.Memory != b.Memory {
+			return cmpDesc(a.Memory, b.Memory)
+		}
+		if a.Keys != b.Keys {
+			return cmpDesc(int64(a.Keys), int64(b.Keys))
+		}
+		return strings.Compare(a.Prefix, b.Prefix)
+	})
+	for _, n := range nodes {
+		sortPrefixes(n.Children)
+	}
+}
+
+// FormatPercent renders a fraction as a whole percentage, e.g. "42%".
+func FormatPercent(f float64) string {
+	return strconv.Itoa(int(f*100+0.5)) + "%"
+}
+
+// WriteText writes the prefix tree as an aligned table, children indented
+// under their parent.
+func (r PrefixReport) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "%d keys matching %s, %s total, %s with TTL\n\n",
+		r.Total.Keys, r.Pattern, FormatBytes(r.Total.Memory), FormatPercent(r.Total.TTLCoverage()))
+	fmt.Fprintln(tw, "PREFIX\tKEYS\tMEMORY\tTTL")
+
+	var walk func(nodes []*PrefixStats, indent string)
+	walk = func(nodes []*PrefixStats, indent string) {
+		for _, n := range nodes {
+			fmt.Fprintf(tw, "%s%s\t%d\t%s\t%s\n", indent, n.Prefix, n.Keys, FormatBytes(n.Memory), FormatPercent(n.TTLCoverage()))
+			walk(n.Children, indent+"  ")
+		}
+	}
+	walk(r.Prefixes, "")
+	return tw.Flush()
+}
+
+// ParsePrefixArgs parses the arguments of the PREFIXES built-in:
+// [pattern] [DEPTH n] [DELIM delimiter] [JSON].
+func ParsePrefixArgs(args []string) (opts PrefixOptions, asJSON bool, err error) {
+	for i := 0; i < len(args); i++ {
+		opt := strings.ToUpper(args[i])
+		switch opt {
+		case "JSON":
+			asJSON = true
+		case "DEPTH":
+			if i+1 >= len(args) {
+				return opts, false, fmt.Errorf("DEPTH needs a value")
+			}
+			n, err := strconv.Atoi(args[i+1])
+			if err != nil || n <= 0 {
+				return opts, false, fmt.Errorf("DEPTH must be a positive integer")
+			}
+			opts.Depth = n
+			i++
+		case "DELIM":
+			if i+1 >= len(args) || args[i+1] == "" {
+				return opts, false, fmt.Errorf("DELIM needs a value")
+			}
+			opts.Delimiter = args[i+1]
+			i++
+		default:
+			if i > 0 || opts.Pattern != "" {
+				return opts, false, fmt.Errorf("unknown option %q", args[i])
+			}
+			opts.Pattern = args[i]
+		}
+	}
+	return opts, asJSON, nil
+}
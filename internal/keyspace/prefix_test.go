@@ -0,0 +1,131 @@
+package keyspace
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/cosmez/redisman-go/internal/conn"
+	"github.com/cosmez/redisman-go/internal/redistest"
+)
+
+// newPrefixServer answers SCAN, MEMORY USAGE and PTTL; keys maps each name
+// to its memory and PTTL.
+func newPrefixServer(t *testing.T, names []string, keys map[string][2]int64) *conn.Connection {
+	t.Helper()
+	s := redistest.NewServer(t)
+	s.Handle("SCAN", func(args []string) string {
+		return redistest.Array(redistest.Bulk("0"), redistest.BulkArray(names...))
+	})
+	s.Handle("MEMORY", func(args []string) string {
+		if k, ok := keys[args[2]]; ok {
+			return redistest.Int(k[0])
+		}
+		return redistest.NullBulk
+	})
+	s.Handle("PTTL", func(args []string) string {
+		if k, ok := keys[args[1]]; ok {
+			return redistest.Int(k[1])
+		}
+		return redistest.Int(-2)
+	})
+
+	c, err := conn.Connect(s.Host, s.Port, "", "")
+	if err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestPrefixes(t *testing.T) {
+	keys := map[string][2]int64{
+		"svc:user:1":    {100, -1},
+		"svc:user:2":    {100, 5000},
+		"svc:order:1":   {1000, -1},
+		"svc:cfg":       {10, -1},
+		"cache:page:/a": {50, 60000},
+		"counter":       {5, -1},
+	}
+	names := []string{"svc:user:1", "svc:user:2", "svc:order:1", "svc:cfg", "cache:page:/a", "counter", "gone"}
+	c := newPrefixServer(t, names, keys)
+
+	report, err := Prefixes(c, PrefixOptions{})
+	if err != nil {
+		t.Fatalf("Prefixes failed: %v", err)
+	}
+	if report.Total.Keys != 6 || report.Total.Memory != 1265 || report.Total.WithTTL != 2 {
+		t.Errorf("Total = %+v", report.Total)
+	}
+	if len(report.Prefixes) != 3 || report.Prefixes[0].Prefix != "svc:" || report.Prefixes[2].Prefix != NoPrefix {
+		t.Fatalf("Top level = %+v, want svc:, cache:, (no prefix) by memory", report.Prefixes)
+	}
+
+	svc := report.Prefixes[0]
+	if svc.Keys != 4 || svc.Memory != 1210 || svc.WithTTL != 1 {
+		t.Errorf("svc: = %+v", svc)
+	}
+	// svc:cfg has only two segments, so it counts toward svc: alone.
+	if len(svc.Children) != 2 || svc.Children[0].Prefix != "svc:order:" || svc.Children[1].Prefix != "svc:user:" {
+		t.Fatalf("svc: children = %+v", svc.Children)
+	}
+	if user := svc.Children[1]; user.Keys != 2 || user.TTLCoverage() != 0.5 || len(user.Children) != 0 {
+		t.Errorf("svc:user: = %+v", user)
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteText(&buf); err != nil {
+		t.Fatalf("WriteText failed: %v", err)
+	}
+	for _, want := range []string{"6 keys matching *", "33% with TTL", "  svc:user:", "(no prefix)"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Text report missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestPrefixesDepthAndDelimiter(t *testing.T) {
+	keys := map[string][2]int64{
+		"a/b/c/d": {10, -1},
+		"a/b/x":   {10, -1},
+	}
+	c := newPrefixServer(t, []string{"a/b/c/d", "a/b/x"}, keys)
+
+	report, err := Prefixes(c, PrefixOptions{Depth: 1, Delimiter: "/"})
+	if err != nil {
+		t.Fatalf("Prefixes failed: %v", err)
+	}
+	if len(report.Prefixes) != 1 || report.Prefixes[0].Prefix != "a/" || len(report.Prefixes[0].Children) != 0 {
+		t.Errorf("Depth 1 = %+v", report.Prefixes)
+	}
+
+	report, err = Prefixes(c, PrefixOptions{Depth: 5, Delimiter: "/"})
+	if err != nil {
+		t.Fatalf("Prefixes failed: %v", err)
+	}
+	b := report.Prefixes[0].Children[0]
+	if b.Prefix != "a/b/" || b.Keys != 2 || len(b.Children) != 1 || b.Children[0].Prefix != "a/b/c/" {
+		t.Errorf("Depth 5 a/b/ = %+v", b)
+	}
+}
+
+func TestParsePrefixArgs(t *testing.T) {
+	opts, asJSON, err := ParsePrefixArgs([]string{"svc:*", "depth", "3", "DELIM", "/", "json"})
+	if err != nil {
+		t.Fatalf("ParsePrefixArgs failed: %v", err)
+	}
+	if opts.Pattern != "svc:*" || opts.Depth != 3 || opts.Delimiter != "/" || !asJSON {
+		t.Errorf("ParsePrefixArgs() = %+v, %v", opts, asJSON)
+	}
+
+	for _, args := range [][]string{
+		{"*", "DEPTH"},
+		{"*", "DEPTH", "0"},
+		{"*", "DELIM"},
+		{"*", "extra"},
+	} {
+		if _, _, err := ParsePrefixArgs(args); err == nil {
+			t.Errorf("ParsePrefixArgs(%q) expected error", args)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package output
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+
+	"github.com/cosmez/redisman-go/internal/resp"
+	"github.com/cosmez/redisman-go/internal/serializer"
+)
+
+// Output formats for redirected results.
+const (
+	FormatText = "text" // plain values, one per line, as EXPORT writes them
+	FormatJSON = "json" // a JSON document; collections become a JSON array
+	FormatCSV  = "csv"  // one CSV record per value or collection item
+)
+
+// ValidFormat reports whether format is one of the output formats.
+func ValidFormat(format string) bool {
+	return format == FormatText || format == FormatJSON || format == FormatCSV
+}
+
+// OpenRedirect opens the target of a `> file` (truncate) or `>> file`
+// (append) redirect.
+func OpenRedirect(path string, appendMode bool) (*os.File, error) {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if appendMode {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+	return os.OpenFile(path, flags, 0o644)
+}
+
+// WriteFormatted writes a RedisValue or an iterator of RedisValues to w in
+// the given format, decoding string values with ser when it is non-nil.
+// Iterators are streamed item by item. An error reply stops the output and
+// is returned.
+//
+// C#: No direct equivalent — the C# version only wrote plain text via ExportAsync.
+func WriteFormatted(w io.Writer, v resp.RedisValue, values iter.Seq[resp.RedisValue], format, typeHint string, ser serializer.Serializer) error {
+	switch format {
+	case FormatJSON:
+		return writeJSONFormat(w, v, values, ser)
+	case FormatCSV:
+		return writeCSVFormat(w, v, values, typeHint, ser)
+	case "", FormatText:
+		return writeTextFormat(w, v, values, typeHint, ser)
+	}
+	return fmt.Errorf("unknown output format %q", format)
+}
+
+// decodeValue returns v with string values decoded by ser. Values that fail
+// to decode are kept as stored, as PrintRedisValue does.
+func decodeValue(v resp.RedisValue, ser serializer.Serializer) resp.RedisValue {
+	if ser == nil {
+		return v
+	}
+	switch val := v.(type) {
+	case resp.RedisArray:
+		decoded := make([]resp.RedisValue, len(val.Values))
+		for i, elem := range val.Values {
+			decoded[i] = decodeValue(elem, ser)
+		}
+		return resp.RedisArray{Values: decoded}
+	case resp.RedisBulkString:
+		if val.Length == -1 {
+			return val
+		}
+		if out, err := ser.Deserialize([]byte(val.Value)); err == nil {
+			return resp.RedisBulkString{Value: string(out), Length: len(out)}
+		}
+	case resp.RedisString:
+		if out, err := ser.Deserialize([]byte(val.Value)); err == nil {
+			return resp.RedisString{Value: string(out)}
+		}
+	}
+	return v
+}
+
+// valueError returns the error carried by an error reply, if v is one.
+func valueError(v resp.RedisValue) error {
+	if e, ok := v.(resp.RedisError); ok {
+		return fmt.Errorf("%s", e.Value)
+	}
+	return nil
+}
+
+func writeTextFormat(w io.Writer, v resp.RedisValue, values iter.Seq[resp.RedisValue], typeHint string, ser serializer.Serializer) error {
+	if v != nil {
+		if err := valueError(v); err != nil {
+			return err
+		}
+		writeValueAsync(w, decodeValue(v, ser), typeHint)
+		if _, ok := v.(resp.RedisArray); !ok {
+			fmt.Fprintln(w) // arrays already end each element with a newline
+		}
+	}
+	if values != nil {
+		for value := range values {
+			if err := valueError(value); err != nil {
+				return err
+			}
+			// Collection items are written one per line, hash pairs as field=value.
+			if typeHint == "" {
+				writeValueAsync(w, decodeValue(value, ser), "")
+				fmt.Fprintln(w)
+				continue
+			}
+			fmt.Fprintln(w, joinFields(decodeValue(value, ser), typeHint))
+		}
+	}
+	return nil
+}
+
+func writeJSONFormat(w io.Writer, v resp.RedisValue, values iter.Seq[resp.RedisValue], ser serializer.Serializer) error {
+	if v != nil {
+		doc, err := jsonDocument(v, ser)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, doc)
+	}
+	if values != nil {
+		fmt.Fprint(w, "[")
+		first := true
+		for value := range values {
+			doc, err := jsonDocument(value, ser)
+			if err != nil {
+				fmt.Fprintln(w, "]")
+				return err
+			}
+			if !first {
+				fmt.Fprint(w, ",")
+			}
+			fmt.Fprintf(w, "\n  %s", doc)
+			first = false
+		}
+		fmt.Fprintln(w, "\n]")
+	}
+	return nil
+}
+
+func writeCSVFormat(w io.Writer, v resp.RedisValue, values iter.Seq[resp.RedisValue], typeHint string, ser serializer.Serializer) error {
+	cw := csv.NewWriter(w)
+
+	if v != nil {
+		if err := valueError(v); err != nil {
+			return err
+		}
+		// A reply array is one record per element; a scalar is a single record.
+		if array, ok := decodeValue(v, ser).(resp.RedisArray); ok {
+			for _, elem := range array.Values {
+				cw.Write(flattenFields(elem))
+			}
+		} else {
+			cw.Write(flattenFields(decodeValue(v, ser)))
+		}
+	}
+	if values != nil {
+		for value := range values {
+			if err := valueError(value); err != nil {
+				cw.Flush()
+				return err
+			}
+			cw.Write(flattenFields(decodeValue(value, ser)))
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// flattenFields returns the scalar strings in v, depth first. Nil values
+// become empty strings.
+func flattenFields(v resp.RedisValue) []string {
+	var fields []string
+	var walk func(resp.RedisValue)
+	walk = func(v resp.RedisValue) {
+		switch val := v.(type) {
+		case resp.RedisArray:
+			for _, elem := range val.Values {
+				walk(elem)
+			}
+		case resp.RedisNull:
+			fields = append(fields, "")
+		case resp.RedisBulkString:
+			if val.Length == -1 {
+				fields = append(fields, "")
+			} else {
+				fields = append(fields, val.Value)
+			}
+		default:
+			fields = append(fields, v.StringValue())
+		}
+	}
+	walk(v)
+	return fields
+}
@@ -0,0 +1,110 @@
+package output
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cosmez/redisman-go/internal/resp"
+	"github.com/cosmez/redisman-go/internal/serializer"
+)
+
+func hashPairs(yield func(resp.RedisValue) bool) {
+	for _, p := range [][2]string{{"name", "Ada"}, {"note", `say "hi", bye`}} {
+		pair := resp.RedisArray{Values: []resp.RedisValue{
+			resp.RedisBulkString{Value: p[0], Length: len(p[0])},
+			resp.RedisBulkString{Value: p[1], Length: len(p[1])},
+		}}
+		if !yield(pair) {
+			return
+		}
+	}
+}
+
+func TestWriteFormatted(t *testing.T) {
+	list := resp.RedisArray{Values: []resp.RedisValue{
+		resp.RedisBulkString{Value: "one", Length: 3},
+		resp.RedisBulkString{Value: `{"a":1}`, Length: 7},
+		resp.RedisBulkString{Length: -1},
+	}}
+
+	tests := []struct {
+		name     string
+		v        resp.RedisValue
+		values   bool
+		format   string
+		typeHint string
+		want     string
+	}{
+		{name: "Text Scalar", v: resp.RedisInteger{IntValue: 5}, format: FormatText, want: "5\n"},
+		{name: "Text Array", v: list, format: FormatText, want: "one\n{\"a\":1}\n(null)\n"},
+		{name: "Text Hash Items", values: true, format: FormatText, typeHint: "hash", want: "name=Ada\nnote=say \"hi\", bye\n"},
+		{name: "JSON Array", v: list, format: FormatJSON, want: "[\"one\",{\"a\":1},null]\n"},
+		{name: "JSON Hash Items", values: true, format: FormatJSON, typeHint: "hash", want: "[\n  [\"name\",\"Ada\"],\n  [\"note\",\"say \\\"hi\\\", bye\"]\n]\n"},
+		{name: "CSV Array", v: list, format: FormatCSV, want: "one\n\"{\"\"a\"\":1}\"\n\n"},
+		{name: "CSV Hash Items", values: true, format: FormatCSV, typeHint: "hash", want: "name,Ada\nnote,\"say \"\"hi\"\", bye\"\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			var err error
+			if tt.values {
+				err = WriteFormatted(&buf, nil, hashPairs, tt.format, tt.typeHint, nil)
+			} else {
+				err = WriteFormatted(&buf, tt.v, nil, tt.format, tt.typeHint, nil)
+			}
+			if err != nil {
+				t.Fatalf("WriteFormatted failed: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("WriteFormatted() = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteFormatted_Decodes(t *testing.T) {
+	ser, err := serializer.Get("base64")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	v := resp.RedisBulkString{Value: "aGVsbG8=", Length: 8}
+	if err := WriteFormatted(&buf, v, nil, FormatText, "", ser); err != nil {
+		t.Fatalf("WriteFormatted failed: %v", err)
+	}
+	if buf.String() != "hello\n" {
+		t.Errorf("WriteFormatted() = %q, want %q", buf.String(), "hello\n")
+	}
+}
+
+func TestWriteFormatted_Error(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteFormatted(&buf, resp.RedisError{Value: "ERR nope"}, nil, FormatCSV, "", nil); err == nil {
+		t.Error("Expected error reply to be returned")
+	}
+}
+
+func TestOpenRedirect(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.txt")
+
+	for _, step := range []struct {
+		text       string
+		appendMode bool
+	}{{"a\n", false}, {"b\n", true}, {"c\n", false}, {"d\n", true}} {
+		f, err := OpenRedirect(path, step.appendMode)
+		if err != nil {
+			t.Fatalf("OpenRedirect failed: %v", err)
+		}
+		f.WriteString(step.text)
+		f.Close()
+	}
+
+	content, _ := os.ReadFile(path)
+	if string(content) != "c\nd\n" {
+		t.Errorf("File content = %q, want %q", content, "c\nd\n")
+	}
+}
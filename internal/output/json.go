@@ -0,0 +1,233 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+
+	"github.com/cosmez/redisman-go/internal/jsonfilter"
+	"github.com/cosmez/redisman-go/internal/resp"
+	"github.com/cosmez/redisman-go/internal/serializer"
+	"github.com/fatih/color"
+)
+
+var (
+	colorJSONKey    = color.New(color.FgHiCyan)
+	colorJSONString = color.New(color.FgHiBlue)
+	colorJSONNumber = color.New(color.FgHiGreen)
+	colorJSONLit    = color.New(color.FgHiMagenta) // true, false, null
+)
+
+// LooksLikeJSON reports whether s is a JSON object or array. Scalars such as
+// "42" or "true" are deliberately excluded so plain values print as before.
+func LooksLikeJSON(s string) bool {
+	t := strings.TrimSpace(s)
+	if t == "" || (t[0] != '{' && t[0] != '[') {
+		return false
+	}
+	return json.Valid([]byte(t))
+}
+
+// WriteJSON pretty-prints a JSON document with two-space indentation and
+// optional key/string/number coloring. Every line after the first is
+// prefixed with padding so the document lines up under a nested array index.
+// Nothing is written if doc is not valid JSON.
+//
+// C#: No direct equivalent — the C# version printed JSON as a plain string.
+func WriteJSON(w io.Writer, doc string, padding string, useColor bool) error {
+	dec := json.NewDecoder(strings.NewReader(doc))
+	dec.UseNumber()
+
+	p := &jsonPrinter{dec: dec, padding: padding, color: useColor}
+	if err := p.value(0); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return fmt.Errorf("invalid JSON: trailing data")
+	}
+
+	_, err := w.Write(p.buf.Bytes())
+	return err
+}
+
+// jsonPrinter walks a token stream so key order is preserved exactly as
+// stored (decoding into a map would sort keys).
+type jsonPrinter struct {
+	dec     *json.Decoder
+	buf     bytes.Buffer
+	padding string
+	color   bool
+}
+
+func (p *jsonPrinter) write(text string, c *color.Color) {
+	if p.color && c != nil {
+		c.Fprint(&p.buf, text)
+	} else {
+		p.buf.WriteString(text)
+	}
+}
+
+func (p *jsonPrinter) newline(depth int) {
+	p.buf.WriteString("\n" + p.padding + strings.Repeat("  ", depth))
+}
+
+func (p *jsonPrinter) value(depth int) error {
+	tok, err := p.dec.Token()
+	if err != nil {
+		return err
+	}
+
+	switch v := tok.(type) {
+	case json.Delim:
+		isObject := v == '{'
+		closer := "]"
+		if isObject {
+			closer = "}"
+		}
+		p.write(v.String(), nil)
+
+		n := 0
+		for p.dec.More() {
+			if n > 0 {
+				p.write(",", nil)
+			}
+			n++
+			p.newline(depth + 1)
+
+			if isObject {
+				keyTok, err := p.dec.Token()
+				if err != nil {
+					return err
+				}
+				key, _ := keyTok.(string)
+				p.write(quoteJSON(key), colorJSONKey)
+				p.write(": ", nil)
+			}
+			if err := p.value(depth + 1); err != nil {
+				return err
+			}
+		}
+
+		// Consume the closing delimiter.
+		if _, err := p.dec.Token(); err != nil {
+			return err
+		}
+		if n > 0 {
+			p.newline(depth)
+		}
+		p.write(closer, nil)
+	case string:
+		p.write(quoteJSON(v), colorJSONString)
+	case json.Number:
+		p.write(v.String(), colorJSONNumber)
+	case bool:
+		p.write(fmt.Sprint(v), colorJSONLit)
+	case nil:
+		p.write("null", colorJSONLit)
+	}
+	return nil
+}
+
+// quoteJSON encodes s as a JSON string literal without HTML escaping.
+func quoteJSON(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// FilterRedisValue applies a jq-style filter (see package jsonfilter) to a
+// reply and pretty-prints each result. String values are decoded with
+// opts.Serializer first; those that are not JSON are treated as JSON strings,
+// and arrays become JSON arrays, so `LRANGE q 0 -1 |> .[].id` works.
+//
+// C#: No direct equivalent — the C# version relied on piping to jq.
+func FilterRedisValue(w io.Writer, v resp.RedisValue, filter string, opts PrintOpts) error {
+	f, err := jsonfilter.Compile(filter)
+	if err != nil {
+		return err
+	}
+
+	doc, err := jsonDocument(v, opts.Serializer)
+	if err != nil {
+		return err
+	}
+	return runFilter(w, f, doc, opts)
+}
+
+// FilterRedisValues is FilterRedisValue for a collection read by VIEW: the
+// items are gathered into one JSON array, as FORMAT json writes them (hash
+// fields as [field, value] pairs), so `VIEW q |> .[].id` works. The whole
+// collection is held in memory while filtering.
+func FilterRedisValues(w io.Writer, values iter.Seq[resp.RedisValue], filter string, opts PrintOpts) error {
+	f, err := jsonfilter.Compile(filter)
+	if err != nil {
+		return err
+	}
+
+	var parts []string
+	for value := range values {
+		doc, err := jsonDocument(value, opts.Serializer)
+		if err != nil {
+			return err
+		}
+		parts = append(parts, doc)
+	}
+	return runFilter(w, f, "["+strings.Join(parts, ",")+"]", opts)
+}
+
+// runFilter runs f on doc and pretty-prints each result.
+func runFilter(w io.Writer, f *jsonfilter.Filter, doc string, opts PrintOpts) error {
+	results, err := f.Run(doc)
+	if err != nil {
+		return err
+	}
+	for _, r := range results {
+		if err := WriteJSON(w, r, "", opts.Color); err != nil {
+			return err
+		}
+		fmt.Fprintln(w)
+	}
+	return nil
+}
+
+// jsonDocument converts a reply into a JSON document for filtering.
+func jsonDocument(v resp.RedisValue, ser serializer.Serializer) (string, error) {
+	switch val := v.(type) {
+	case resp.RedisError:
+		return "", fmt.Errorf("%s", val.Value)
+	case resp.RedisNull:
+		return "null", nil
+	case resp.RedisInteger:
+		return val.StringValue(), nil
+	case resp.RedisArray:
+		parts := make([]string, len(val.Values))
+		for i, elem := range val.Values {
+			doc, err := jsonDocument(elem, ser)
+			if err != nil {
+				return "", err
+			}
+			parts[i] = doc
+		}
+		return "[" + strings.Join(parts, ",") + "]", nil
+	case resp.RedisBulkString:
+		if val.Length == -1 {
+			return "null", nil
+		}
+	}
+
+	text := v.StringValue()
+	if ser != nil {
+		if decoded, err := ser.Deserialize([]byte(text)); err == nil {
+			text = string(decoded)
+		}
+	}
+	if json.Valid([]byte(text)) {
+		return text, nil
+	}
+	return quoteJSON(text), nil
+}
@@ -5,7 +5,6 @@ import (
 	"io"
 	"iter"
 	"os"
-	"os/exec"
 	"strings"
 
 	"github.com/cosmez/redisman-go/internal/resp"
@@ -20,6 +19,7 @@ type PrintOpts struct {
 	Padding    string
 	TypeHint   string // e.g., "hash", "stream"
 	Newline    bool
+	PrettyJSON bool // pretty-print string values that are JSON objects or arrays
 }
 
 var (
@@ -116,52 +116,6 @@ func PrintRedisValues(w io.Writer, r io.Reader, values iter.Seq[resp.RedisValue]
 	}
 }
 
-// PipeRedisValue pipes a RedisValue to a shell command.
-//
-// C# equivalent:
-// public static void PipeRedisValue(ParsedCommand command, RedisValue value)
-func PipeRedisValue(w io.Writer, v resp.RedisValue, shellCmd string) error {
-	if shellCmd == "" {
-		return nil
-	}
-
-	args := strings.Fields(shellCmd)
-	if len(args) == 0 {
-		return nil
-	}
-
-	cmd := exec.Command(args[0], args[1:]...)
-	cmd.Stdout = w
-	cmd.Stderr = w
-
-	stdin, err := cmd.StdinPipe()
-	if err != nil {
-		return err
-	}
-
-	if err := cmd.Start(); err != nil {
-		return err
-	}
-
-	writeRawValue(stdin, v)
-	stdin.Close()
-
-	return cmd.Wait()
-}
-
-func writeRawValue(w io.Writer, v resp.RedisValue) {
-	if v == nil {
-		return
-	}
-	if array, ok := v.(resp.RedisArray); ok {
-		for _, element := range array.Values {
-			writeRawValue(w, element)
-		}
-	} else {
-		fmt.Fprintln(w, v.StringValue())
-	}
-}
-
 // ExportAsync writes a RedisValue or an iterator of RedisValues to a file.
 //
 // C# equivalent:
@@ -338,7 +292,14 @@ func PrintRedisValue(w io.Writer, v resp.RedisValue, opts PrintOpts) {
 				outputText = "(nil)"
 				c = colorNull
 			} else {
-				outputText = fmt.Sprintf("\"%s\"", getDeserialized(val.StringValue()))
+				text := getDeserialized(val.StringValue())
+				if opts.PrettyJSON && LooksLikeJSON(text) && WriteJSON(w, text, opts.Padding, opts.Color) == nil {
+					if opts.Newline {
+						fmt.Fprintln(w)
+					}
+					return
+				}
+				outputText = fmt.Sprintf("\"%s\"", text)
 				c = colorString
 			}
 		case resp.TypeInteger:
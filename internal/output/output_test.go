@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
@@ -99,6 +100,32 @@ func TestPrintRedisValue(t *testing.T) {
 			opts:     PrintOpts{Newline: true},
 			expected: "(empty array)\n",
 		},
+		{
+			name:     "Pretty JSON",
+			value:    resp.RedisBulkString{Value: `{"b":1,"a":[true,null,"x"],"e":{}}`, Length: 34},
+			opts:     PrintOpts{Newline: true, PrettyJSON: true},
+			expected: "{\n  \"b\": 1,\n  \"a\": [\n    true,\n    null,\n    \"x\"\n  ],\n  \"e\": {}\n}\n",
+		},
+		{
+			name:     "Pretty JSON Disabled",
+			value:    resp.RedisBulkString{Value: `{"a":1}`, Length: 7},
+			opts:     PrintOpts{Newline: true},
+			expected: "\"{\"a\":1}\"\n",
+		},
+		{
+			name:     "Pretty JSON Scalar Untouched",
+			value:    resp.RedisBulkString{Value: `42`, Length: 2},
+			opts:     PrintOpts{Newline: true, PrettyJSON: true},
+			expected: "\"42\"\n",
+		},
+		{
+			name: "Pretty JSON Nested In Array",
+			value: resp.RedisArray{Values: []resp.RedisValue{
+				resp.RedisBulkString{Value: `{"a":1}`, Length: 7},
+			}},
+			opts:     PrintOpts{Newline: true, PrettyJSON: true},
+			expected: "1) {\n     \"a\": 1\n   }\n",
+		},
 		{
 			name: "Array with typed values",
 			value: resp.RedisArray{Values: []resp.RedisValue{
@@ -246,3 +273,79 @@ func TestExportAsync_Hash(t *testing.T) {
 		t.Errorf("ExportAsync() = %q, want %q", string(content), expected)
 	}
 }
+
+func TestFilterRedisValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    resp.RedisValue
+		filter   string
+		expected string
+	}{
+		{
+			name:     "JSON String",
+			value:    resp.RedisBulkString{Value: `{"items":[{"id":1},{"id":2}]}`, Length: 29},
+			filter:   ".items[].id",
+			expected: "1\n2\n",
+		},
+		{
+			name: "Array Of Documents",
+			value: resp.RedisArray{Values: []resp.RedisValue{
+				resp.RedisBulkString{Value: `{"id":"a"}`, Length: 10},
+				resp.RedisBulkString{Value: `plain`, Length: 5},
+			}},
+			filter:   ".[]",
+			expected: "{\n  \"id\": \"a\"\n}\n\"plain\"\n",
+		},
+		{
+			name:     "Null",
+			value:    resp.RedisNull{},
+			filter:   ".a",
+			expected: "null\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := FilterRedisValue(&buf, tt.value, tt.filter, PrintOpts{}); err != nil {
+				t.Fatalf("FilterRedisValue failed: %v", err)
+			}
+			if got := buf.String(); got != tt.expected {
+				t.Errorf("FilterRedisValue() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := FilterRedisValue(&buf, resp.RedisError{Value: "ERR wrong type"}, ".", PrintOpts{}); err == nil {
+		t.Error("Expected error reply to surface as an error")
+	}
+}
+
+func TestFilterRedisValues(t *testing.T) {
+	hash := slices.Values([]resp.RedisValue{
+		resp.RedisArray{Values: []resp.RedisValue{resp.RedisBulkString{Value: "a", Length: 1}, resp.RedisBulkString{Value: `{"n":1}`, Length: 7}}},
+		resp.RedisArray{Values: []resp.RedisValue{resp.RedisBulkString{Value: "b", Length: 1}, resp.RedisBulkString{Value: `{"n":2}`, Length: 7}}},
+	})
+
+	var buf bytes.Buffer
+	if err := FilterRedisValues(&buf, hash, ".[1][1].n, .[0][0]", PrintOpts{}); err != nil {
+		t.Fatalf("FilterRedisValues failed: %v", err)
+	}
+	if got := buf.String(); got != "2\n\"a\"\n" {
+		t.Errorf("FilterRedisValues() = %q, want %q", got, "2\n\"a\"\n")
+	}
+
+	buf.Reset()
+	if err := FilterRedisValues(&buf, slices.Values([]resp.RedisValue(nil)), "length", PrintOpts{}); err != nil {
+		t.Fatalf("FilterRedisValues failed: %v", err)
+	}
+	if got := buf.String(); got != "0\n" {
+		t.Errorf("FilterRedisValues() on an empty collection = %q, want %q", got, "0\n")
+	}
+
+	failing := slices.Values([]resp.RedisValue{resp.RedisError{Value: "ERR scan failed"}})
+	if err := FilterRedisValues(&buf, failing, ".", PrintOpts{}); err == nil {
+		t.Error("Expected an error item to surface as an error")
+	}
+}
@@ -0,0 +1,216 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"os/exec"
+	"os/signal"
+
+	"github.com/cosmez/redisman-go/internal/resp"
+)
+
+// Paging modes for long results.
+const (
+	PagingPager  = "pager"  // stream output taller than the terminal into $PAGER
+	PagingPrompt = "prompt" // pause with "Continue Listing? (Y/N)" every PageSize items
+	PagingNone   = "none"   // print everything
+)
+
+// DefaultPageSize is the number of items between prompts in prompt mode.
+const DefaultPageSize = 100
+
+// PageOpts configures how long results are paged.
+type PageOpts struct {
+	Mode     string    // PagingPager, PagingPrompt or PagingNone; empty means PagingPager
+	PageSize int       // items between prompts in prompt mode
+	Height   int       // terminal height in lines; 0 when output is not a terminal
+	Input    io.Reader // where prompt answers are read from
+}
+
+// ValidPagingMode reports whether mode is one of the paging modes.
+func ValidPagingMode(mode string) bool {
+	return mode == PagingPager || mode == PagingPrompt || mode == PagingNone
+}
+
+// PagerCommand returns the pager argv: $PAGER if set, otherwise less -R.
+// It returns nil if no pager is available.
+func PagerCommand() []string {
+	args := splitArgs(os.Getenv("PAGER"))
+	if len(args) == 0 {
+		args = []string{"less", "-R"}
+	}
+	if _, err := exec.LookPath(args[0]); err != nil {
+		return nil
+	}
+	return args
+}
+
+// PageRedisValues prints an iterator of RedisValues using the paging mode in
+// page. Pager mode falls back to the prompt when there is no terminal or no
+// pager installed.
+//
+// C#: No direct equivalent — the C# version always prompted every 100 items.
+func PageRedisValues(w io.Writer, values iter.Seq[resp.RedisValue], opts PrintOpts, page PageOpts) {
+	pageSize := page.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
+	switch page.Mode {
+	case PagingNone:
+		PrintRedisValues(w, nil, values, opts, 0)
+		return
+	case PagingPrompt:
+		PrintRedisValues(w, page.Input, values, opts, pageSize)
+		return
+	}
+
+	pager := NewPager(w, page.Height)
+	if pager == nil {
+		PrintRedisValues(w, page.Input, values, opts, pageSize)
+		return
+	}
+
+	// Stop fetching once the user quits the pager.
+	untilQuit := func(yield func(resp.RedisValue) bool) {
+		for value := range values {
+			if pager.Quit() || !yield(value) {
+				return
+			}
+		}
+	}
+	PrintRedisValues(pager, nil, untilQuit, opts, 0)
+	if err := pager.Close(); err != nil {
+		fmt.Fprintf(w, "Pager error: %v\n", err)
+	}
+}
+
+// Pager is a writer that buffers output until it grows taller than the
+// terminal, then starts the pager and streams everything into it. Output
+// that fits on one screen is written to the underlying writer on Close.
+type Pager struct {
+	out    io.Writer
+	height int
+	args   []string
+
+	buf   bytes.Buffer
+	lines int
+
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+	quit  bool
+	sigs  chan os.Signal
+}
+
+// NewPager returns a Pager writing to out, or nil when paging is not
+// possible (height unknown or no pager available).
+func NewPager(out io.Writer, height int) *Pager {
+	if height <= 0 {
+		return nil
+	}
+	args := PagerCommand()
+	if args == nil {
+		return nil
+	}
+	return &Pager{out: out, height: height, args: args}
+}
+
+// Quit reports whether the pager has exited, e.g. the user pressed q.
+func (p *Pager) Quit() bool {
+	return p.quit
+}
+
+// Write implements io.Writer.
+func (p *Pager) Write(b []byte) (int, error) {
+	if p.quit {
+		return 0, io.ErrClosedPipe
+	}
+	if p.cmd != nil {
+		if _, err := p.stdin.Write(b); err != nil {
+			p.quit = true
+			return 0, err
+		}
+		return len(b), nil
+	}
+	if p.args == nil {
+		return p.out.Write(b)
+	}
+
+	p.buf.Write(b)
+	p.lines += bytes.Count(b, []byte("\n"))
+	if p.lines >= p.height {
+		if err := p.start(); err != nil {
+			// Could not start the pager: print what we have and continue unpaged.
+			p.args = nil
+			p.out.Write(p.buf.Bytes())
+			p.buf.Reset()
+		}
+	}
+	return len(b), nil
+}
+
+// start launches the pager and flushes the buffered output into it.
+func (p *Pager) start() error {
+	cmd := exec.Command(p.args[0], p.args[1:]...)
+	cmd.Stdout = p.out
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	// Ctrl+C belongs to the pager while it runs, not to redisman.
+	p.sigs = make(chan os.Signal, 1)
+	signal.Notify(p.sigs, os.Interrupt)
+
+	p.cmd = cmd
+	p.stdin = stdin
+	if _, err := stdin.Write(p.buf.Bytes()); err != nil {
+		p.quit = true
+	}
+	p.buf.Reset()
+	return nil
+}
+
+// Close flushes short output to the underlying writer, or waits for the
+// pager to exit.
+func (p *Pager) Close() error {
+	if p.cmd == nil {
+		_, err := p.out.Write(p.buf.Bytes())
+		p.buf.Reset()
+		return err
+	}
+
+	p.stdin.Close()
+	err := p.cmd.Wait()
+	signal.Stop(p.sigs)
+	if _, ok := ExitCode(err); ok {
+		return nil // pagers exit non-zero when interrupted; that is not an error
+	}
+	return err
+}
+
+// PageRedisValue prints a single RedisValue, through the pager in pager mode
+// when it is taller than the terminal. Other modes print it directly.
+func PageRedisValue(w io.Writer, v resp.RedisValue, opts PrintOpts, page PageOpts) {
+	if page.Mode != "" && page.Mode != PagingPager {
+		PrintRedisValue(w, v, opts)
+		return
+	}
+
+	pager := NewPager(w, page.Height)
+	if pager == nil {
+		PrintRedisValue(w, v, opts)
+		return
+	}
+	PrintRedisValue(pager, v, opts)
+	if err := pager.Close(); err != nil {
+		fmt.Fprintf(w, "Pager error: %v\n", err)
+	}
+}
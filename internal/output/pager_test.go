@@ -0,0 +1,89 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/cosmez/redisman-go/internal/resp"
+)
+
+func keySeq(n int) func(yield func(resp.RedisValue) bool) {
+	return func(yield func(resp.RedisValue) bool) {
+		for i := 0; i < n; i++ {
+			if !yield(resp.RedisString{Value: "key"}) {
+				return
+			}
+		}
+	}
+}
+
+func TestPageRedisValues_Modes(t *testing.T) {
+	opts := PrintOpts{Newline: true}
+
+	t.Run("None", func(t *testing.T) {
+		var buf bytes.Buffer
+		PageRedisValues(&buf, keySeq(3), opts, PageOpts{Mode: PagingNone, PageSize: 1})
+		if strings.Contains(buf.String(), "Continue Listing?") || strings.Count(buf.String(), "key") != 3 {
+			t.Errorf("Unexpected output: %q", buf.String())
+		}
+	})
+
+	t.Run("Prompt", func(t *testing.T) {
+		var buf bytes.Buffer
+		page := PageOpts{Mode: PagingPrompt, PageSize: 2, Input: strings.NewReader("n\n")}
+		PageRedisValues(&buf, keySeq(5), opts, page)
+		if strings.Count(buf.String(), "key") != 2 || !strings.Contains(buf.String(), "Continue Listing?") {
+			t.Errorf("Unexpected output: %q", buf.String())
+		}
+	})
+
+	t.Run("Pager Without Terminal Falls Back To Prompt", func(t *testing.T) {
+		var buf bytes.Buffer
+		page := PageOpts{Mode: PagingPager, PageSize: 2, Input: strings.NewReader("n\n")}
+		PageRedisValues(&buf, keySeq(5), opts, page)
+		if strings.Count(buf.String(), "key") != 2 {
+			t.Errorf("Unexpected output: %q", buf.String())
+		}
+	})
+}
+
+func TestPager(t *testing.T) {
+	withShell(t, nil) // skip on Windows; cat is the pager
+	t.Setenv("PAGER", "cat")
+
+	t.Run("Short Output Is Written Directly", func(t *testing.T) {
+		var buf bytes.Buffer
+		p := NewPager(&buf, 10)
+		if p == nil {
+			t.Fatal("NewPager returned nil")
+		}
+		p.Write([]byte("one\ntwo\n"))
+		if buf.Len() != 0 {
+			t.Errorf("Expected output to be buffered, got %q", buf.String())
+		}
+		if err := p.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+		if p.cmd != nil {
+			t.Error("Pager should not start for short output")
+		}
+		if buf.String() != "one\ntwo\n" {
+			t.Errorf("Pager output = %q", buf.String())
+		}
+	})
+
+	t.Run("Long Output Goes Through Pager", func(t *testing.T) {
+		var buf bytes.Buffer
+		PageRedisValues(&buf, keySeq(5), PrintOpts{Newline: true}, PageOpts{Mode: PagingPager, Height: 3})
+		if got := strings.Count(buf.String(), "key"); got != 5 {
+			t.Errorf("Expected 5 keys through the pager, got %d in %q", got, buf.String())
+		}
+	})
+
+	t.Run("No Terminal", func(t *testing.T) {
+		if NewPager(&bytes.Buffer{}, 0) != nil {
+			t.Error("NewPager should return nil without a terminal height")
+		}
+	})
+}
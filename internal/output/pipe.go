@@ -0,0 +1,184 @@
+package output
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/cosmez/redisman-go/internal/resp"
+)
+
+// PipeShell is the argv prefix used to run pipe commands; the pipe text is
+// appended as the last argument, so quoting, chained pipes, redirection and
+// environment variables behave as they do in the user's shell. Set it to nil
+// to run pipe commands directly, without a shell.
+var PipeShell = DefaultPipeShell()
+
+// DefaultPipeShell returns the shell used for pipes: $SHELL -c (falling back
+// to /bin/sh), or cmd /C on Windows.
+func DefaultPipeShell() []string {
+	if runtime.GOOS == "windows" {
+		return []string{"cmd", "/C"}
+	}
+	shell := os.Getenv("SHELL")
+	if shell == "" {
+		shell = "/bin/sh"
+	}
+	return []string{shell, "-c"}
+}
+
+// PipeRedisValue pipes a RedisValue to a shell command.
+//
+// C# equivalent:
+// public static void PipeRedisValue(ParsedCommand command, RedisValue value)
+func PipeRedisValue(w io.Writer, v resp.RedisValue, shellCmd string) error {
+	return PipeRedisValues(w, func(yield func(resp.RedisValue) bool) { yield(v) }, shellCmd, "")
+}
+
+// PipeRedisValues streams an iterator of RedisValues (SAFEKEYS, VIEW) to a
+// shell command, one line per item, without buffering the collection.
+// Iteration stops early if the command stops reading (e.g. `| head -5`).
+//
+// A non-zero exit status is returned as an *exec.ExitError; use ExitCode to
+// read it.
+//
+// C#: No direct equivalent — the C# version only piped single values.
+func PipeRedisValues(w io.Writer, values iter.Seq[resp.RedisValue], shellCmd, typeHint string) error {
+	cmd := pipeCommand(shellCmd)
+	if cmd == nil {
+		return nil
+	}
+	cmd.Stdout = w
+	cmd.Stderr = w
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(stdin)
+	for value := range values {
+		if writePipeValue(bw, value, typeHint) != nil {
+			break // the command closed its stdin; let it finish
+		}
+	}
+	bw.Flush()
+	stdin.Close()
+
+	return cmd.Wait()
+}
+
+// ExitCode reports the exit status carried by a pipe error.
+func ExitCode(err error) (int, bool) {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), true
+	}
+	return 0, false
+}
+
+// pipeCommand builds the command for shellCmd using PipeShell, or by
+// splitting shellCmd into arguments when PipeShell is nil.
+func pipeCommand(shellCmd string) *exec.Cmd {
+	if strings.TrimSpace(shellCmd) == "" {
+		return nil
+	}
+
+	if len(PipeShell) > 0 {
+		args := append(PipeShell[1:len(PipeShell):len(PipeShell)], shellCmd)
+		return exec.Command(PipeShell[0], args...)
+	}
+
+	args := splitArgs(shellCmd)
+	if len(args) == 0 {
+		return nil
+	}
+	return exec.Command(args[0], args[1:]...)
+}
+
+// writePipeValue writes one item of a piped value. Single values are written
+// like writeRawValue; collection items are written on one line each, with
+// hash pairs as field=value to match EXPORT.
+func writePipeValue(w *bufio.Writer, v resp.RedisValue, typeHint string) error {
+	array, ok := v.(resp.RedisArray)
+	if typeHint == "" || !ok {
+		writeRawValue(w, v)
+		return w.Flush()
+	}
+
+	fmt.Fprintln(w, joinFields(array, typeHint))
+	return w.Flush()
+}
+
+// joinFields renders a collection item on one line: hash pairs as
+// field=value, anything else (zset member/score, stream entries) space
+// separated.
+func joinFields(v resp.RedisValue, typeHint string) string {
+	sep := " "
+	if typeHint == "hash" {
+		sep = "="
+	}
+	return strings.Join(flattenFields(v), sep)
+}
+
+func writeRawValue(w io.Writer, v resp.RedisValue) {
+	if v == nil {
+		return
+	}
+	if array, ok := v.(resp.RedisArray); ok {
+		for _, element := range array.Values {
+			writeRawValue(w, element)
+		}
+	} else {
+		fmt.Fprintln(w, v.StringValue())
+	}
+}
+
+// splitArgs splits a command line into arguments, honoring single quotes,
+// double quotes and backslash escapes. It is used when PipeShell is nil.
+func splitArgs(s string) []string {
+	var args []string
+	var current strings.Builder
+	inArg := false
+	var quote byte
+
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case quote != 0 && ch == quote:
+			quote = 0
+		case quote != '\'' && ch == '\\' && i+1 < len(s):
+			i++
+			current.WriteByte(s[i])
+			inArg = true
+		case quote != 0:
+			current.WriteByte(ch)
+		case ch == '\'' || ch == '"':
+			quote = ch
+			inArg = true
+		case ch == ' ' || ch == '\t':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteByte(ch)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args
+}
@@ -0,0 +1,124 @@
+package output
+
+import (
+	"bytes"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/cosmez/redisman-go/internal/resp"
+)
+
+func withShell(t *testing.T, shell []string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("pipe tests use POSIX shell commands")
+	}
+	prev := PipeShell
+	PipeShell = shell
+	t.Cleanup(func() { PipeShell = prev })
+}
+
+func TestPipeRedisValue_Shell(t *testing.T) {
+	withShell(t, []string{"/bin/sh", "-c"})
+
+	val := resp.RedisArray{Values: []resp.RedisValue{
+		resp.RedisString{Value: "b x"},
+		resp.RedisString{Value: "a"},
+		resp.RedisString{Value: "b y"},
+	}}
+
+	var buf bytes.Buffer
+	if err := PipeRedisValue(&buf, val, `grep 'b ' | wc -l | tr -d ' '`); err != nil {
+		t.Fatalf("PipeRedisValue failed: %v", err)
+	}
+	if got := buf.String(); got != "2\n" {
+		t.Errorf("PipeRedisValue() = %q, want %q", got, "2\n")
+	}
+}
+
+func TestPipeRedisValue_ExitCode(t *testing.T) {
+	withShell(t, []string{"/bin/sh", "-c"})
+
+	var buf bytes.Buffer
+	err := PipeRedisValue(&buf, resp.RedisString{Value: "x"}, "cat >/dev/null; exit 3")
+	code, ok := ExitCode(err)
+	if !ok || code != 3 {
+		t.Errorf("ExitCode() = %d, %v, want 3, true (err: %v)", code, ok, err)
+	}
+}
+
+func TestPipeRedisValues_Stream(t *testing.T) {
+	withShell(t, []string{"/bin/sh", "-c"})
+
+	pairs := func(yield func(resp.RedisValue) bool) {
+		for _, p := range [][2]string{{"f1", "v1"}, {"f2", "v2"}} {
+			pair := resp.RedisArray{Values: []resp.RedisValue{
+				resp.RedisString{Value: p[0]},
+				resp.RedisString{Value: p[1]},
+			}}
+			if !yield(pair) {
+				return
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := PipeRedisValues(&buf, pairs, "cat", "hash"); err != nil {
+		t.Fatalf("PipeRedisValues failed: %v", err)
+	}
+	if got, want := buf.String(), "f1=v1\nf2=v2\n"; got != want {
+		t.Errorf("PipeRedisValues() = %q, want %q", got, want)
+	}
+}
+
+func TestPipeRedisValues_EarlyExit(t *testing.T) {
+	withShell(t, []string{"/bin/sh", "-c"})
+
+	endless := func(yield func(resp.RedisValue) bool) {
+		for {
+			if !yield(resp.RedisString{Value: "key"}) {
+				return
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := PipeRedisValues(&buf, endless, "head -n 2", "keys"); err != nil {
+		t.Fatalf("PipeRedisValues failed: %v", err)
+	}
+	if got := buf.String(); got != "key\nkey\n" {
+		t.Errorf("PipeRedisValues() = %q, want %q", got, "key\nkey\n")
+	}
+}
+
+func TestPipeRedisValue_NoShell(t *testing.T) {
+	withShell(t, nil)
+
+	var buf bytes.Buffer
+	if err := PipeRedisValue(&buf, resp.RedisString{Value: "x"}, `echo "a | b" '$HOME'`); err != nil {
+		t.Fatalf("PipeRedisValue failed: %v", err)
+	}
+	if got := buf.String(); got != "a | b $HOME\n" {
+		t.Errorf("PipeRedisValue() = %q, want %q", got, "a | b $HOME\n")
+	}
+}
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"jq .", []string{"jq", "."}},
+		{`grep "a b"  c`, []string{"grep", "a b", "c"}},
+		{`awk '{print $1}'`, []string{"awk", "{print $1}"}},
+		{`echo a\ b ""`, []string{"echo", "a b", ""}},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		if got := splitArgs(tt.input); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitArgs(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ProgressBar renders done out of total as a fixed-width bar followed by
+// the counts, e.g. "[#####     ] 50/100". A zero total renders as full.
+func ProgressBar(done, total, width int) string {
+	filled := width
+	if total > 0 {
+		filled = min(width*done/total, width)
+	}
+	return fmt.Sprintf("[%s%s] %d/%d", strings.Repeat("#", filled), strings.Repeat(" ", width-filled), done, total)
+}
@@ -0,0 +1,20 @@
+package output
+
+import "testing"
+
+func TestProgressBar(t *testing.T) {
+	tests := []struct {
+		done, total int
+		want        string
+	}{
+		{0, 100, "[          ] 0/100"},
+		{45, 100, "[####      ] 45/100"},
+		{100, 100, "[##########] 100/100"},
+		{0, 0, "[##########] 0/0"},
+	}
+	for _, tt := range tests {
+		if got := ProgressBar(tt.done, tt.total, 10); got != tt.want {
+			t.Errorf("ProgressBar(%d, %d) = %q, want %q", tt.done, tt.total, got, tt.want)
+		}
+	}
+}
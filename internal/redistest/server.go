@@ -0,0 +1,147 @@
+package redistest
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cosmez/redisman-go/internal/resp"
+)
+
+// Handler answers one command. args[0] is the upper-cased command name and
+// the return value is a raw RESP reply, built with the helpers below.
+type Handler func(args []string) string
+
+// Server is a fake Redis server for tests. It speaks RESP over a real TCP
+// listener so conn.Connect can be used unchanged; each command is answered
+// by the handler registered for its name.
+//
+// C#: No direct equivalent — the C# version had no server-backed tests.
+type Server struct {
+	Host string
+	Port string
+
+	mu       sync.Mutex
+	handlers map[string]Handler
+	commands [][]string
+	listener net.Listener
+}
+
+// NewServer starts a fake server on a random local port. It answers INFO
+// with a minimal reply; every other command gets an "unknown command" error
+// until a handler is registered. The server stops when the test ends.
+func NewServer(t *testing.T) *Server {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	host, port, _ := net.SplitHostPort(l.Addr().String())
+
+	s := &Server{
+		Host:     host,
+		Port:     port,
+		handlers: make(map[string]Handler),
+		listener: l,
+	}
+	s.Handle("INFO", func(args []string) string {
+		return Bulk("# Server\r\nredis_version:7.2.0\r\nredis_mode:standalone\r\n")
+	})
+
+	go s.serve()
+	t.Cleanup(func() { l.Close() })
+	return s
+}
+
+// Handle registers h for the command name (case-insensitive).
+func (s *Server) Handle(name string, h Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[strings.ToUpper(name)] = h
+}
+
+// Commands returns every command received so far, in order.
+func (s *Server) Commands() [][]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([][]string(nil), s.commands...)
+}
+
+func (s *Server) serve() {
+	for {
+		nc, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.serveConn(nc)
+	}
+}
+
+func (s *Server) serveConn(nc net.Conn) {
+	defer nc.Close()
+	r := bufio.NewReader(nc)
+
+	for {
+		v, err := resp.ParseValue(r)
+		if err != nil {
+			return
+		}
+		array, ok := v.(resp.RedisArray)
+		if !ok || len(array.Values) == 0 {
+			fmt.Fprint(nc, Err("ERR protocol error"))
+			continue
+		}
+
+		args := make([]string, len(array.Values))
+		for i, arg := range array.Values {
+			args[i] = arg.StringValue()
+		}
+		args[0] = strings.ToUpper(args[0])
+
+		s.mu.Lock()
+		s.commands = append(s.commands, args)
+		h := s.handlers[args[0]]
+		s.mu.Unlock()
+
+		reply := Err(fmt.Sprintf("ERR unknown command '%s'", args[0]))
+		if h != nil {
+			reply = h(args)
+		}
+		if _, err := fmt.Fprint(nc, reply); err != nil {
+			return
+		}
+	}
+}
+
+// Simple returns a simple string reply.
+func Simple(s string) string { return "+" + s + "\r\n" }
+
+// Err returns an error reply.
+func Err(s string) string { return "-" + s + "\r\n" }
+
+// Int returns an integer reply.
+func Int(n int64) string { return fmt.Sprintf(":%d\r\n", n) }
+
+// Bulk returns a bulk string reply.
+func Bulk(s string) string { return fmt.Sprintf("$%d\r\n%s\r\n", len(s), s) }
+
+// NullBulk is the nil bulk string reply.
+const NullBulk = "$-1\r\n"
+
+// Array returns an array reply of already-encoded elements.
+func Array(elems ...string) string {
+	return fmt.Sprintf("*%d\r\n", len(elems)) + strings.Join(elems, "")
+}
+
+// BulkArray returns an array reply of bulk strings.
+func BulkArray(values ...string) string {
+	elems := make([]string, len(values))
+	for i, v := range values {
+		elems[i] = Bulk(v)
+	}
+	return Array(elems...)
+}
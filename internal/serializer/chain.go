@@ -0,0 +1,50 @@
+package serializer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// chainSerializer applies several codecs in sequence.
+//
+// A chain spec lists codecs in the order they are peeled off on read:
+// "base64+gzip" means the stored value is base64 text wrapping gzip bytes,
+// so Deserialize runs base64 then gzip, and Serialize runs gzip then base64.
+type chainSerializer []Serializer
+
+// newChain builds a chainSerializer from a "+"-separated spec.
+func newChain(spec string) (Serializer, error) {
+	var chain chainSerializer
+	for _, part := range strings.Split(spec, "+") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			return nil, fmt.Errorf("empty codec in chain %q", spec)
+		}
+		s, err := Get(part)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, s)
+	}
+	return chain, nil
+}
+
+func (c chainSerializer) Serialize(data []byte) ([]byte, error) {
+	var err error
+	for i := len(c) - 1; i >= 0; i-- {
+		if data, err = c[i].Serialize(data); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+func (c chainSerializer) Deserialize(data []byte) ([]byte, error) {
+	var err error
+	for _, s := range c {
+		if data, err = s.Deserialize(data); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
@@ -0,0 +1,108 @@
+package serializer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// DefaultExternalTimeout bounds how long an external codec subprocess may run
+// when the config does not specify a timeout.
+const DefaultExternalTimeout = 5 * time.Second
+
+// externalSerializer implements the Serializer interface by piping bytes
+// through a user-configured subprocess: data goes to stdin, the result is
+// read from stdout.
+//
+// C#: No direct equivalent — the C# version only shipped compiled-in codecs.
+type externalSerializer struct {
+	name    string
+	encode  []string // argv used by Serialize, empty if encoding is unsupported
+	decode  []string // argv used by Deserialize, empty if decoding is unsupported
+	timeout time.Duration
+}
+
+// NewExternal returns a Serializer that runs encode/decode as subprocesses.
+// Each argv is executed directly (no shell), so arguments never need quoting.
+// A zero timeout falls back to DefaultExternalTimeout.
+func NewExternal(name string, encode, decode []string, timeout time.Duration) Serializer {
+	if timeout <= 0 {
+		timeout = DefaultExternalTimeout
+	}
+	return externalSerializer{name: name, encode: encode, decode: decode, timeout: timeout}
+}
+
+// WithArg returns a copy of the codec with every "{arg}" placeholder in its
+// commands replaced by arg. This lets one configured codec serve specs such
+// as "proto:cache.Entry".
+func (s externalSerializer) WithArg(arg string) (Serializer, error) {
+	if !hasPlaceholder(s.encode) && !hasPlaceholder(s.decode) {
+		return nil, fmt.Errorf("codec %q does not take an argument (no {arg} in its commands)", s.name)
+	}
+	s.encode = substituteArg(s.encode, arg)
+	s.decode = substituteArg(s.decode, arg)
+	return s, nil
+}
+
+func hasPlaceholder(argv []string) bool {
+	for _, a := range argv {
+		if strings.Contains(a, "{arg}") {
+			return true
+		}
+	}
+	return false
+}
+
+func substituteArg(argv []string, arg string) []string {
+	if argv == nil {
+		return nil
+	}
+	out := make([]string, len(argv))
+	for i, a := range argv {
+		out[i] = strings.ReplaceAll(a, "{arg}", arg)
+	}
+	return out
+}
+
+func (s externalSerializer) Serialize(data []byte) ([]byte, error) {
+	if len(s.encode) == 0 {
+		return nil, fmt.Errorf("codec %q does not support encoding", s.name)
+	}
+	return s.run(s.encode, data)
+}
+
+func (s externalSerializer) Deserialize(data []byte) ([]byte, error) {
+	if len(s.decode) == 0 {
+		return nil, fmt.Errorf("codec %q does not support decoding", s.name)
+	}
+	return s.run(s.decode, data)
+}
+
+// run executes argv with data on stdin and returns its stdout. Stderr is
+// folded into the returned error so users can see why their codec failed.
+func (s externalSerializer) run(argv []string, data []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Stdin = bytes.NewReader(data)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("codec %q timed out after %s", s.name, s.timeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("codec %q failed: %w: %s", s.name, err, msg)
+		}
+		return nil, fmt.Errorf("codec %q failed: %w", s.name, err)
+	}
+
+	return stdout.Bytes(), nil
+}
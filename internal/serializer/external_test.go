@@ -0,0 +1,81 @@
+package serializer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExternalSerializerRoundTrip(t *testing.T) {
+	codec := NewExternal("cat", []string{"cat"}, []string{"cat"}, time.Second)
+
+	input := []byte{0x00, 'h', 'i', 0xFF}
+	encoded, err := codec.Serialize(input)
+	if err != nil {
+		t.Fatalf("Serialize failed: %v", err)
+	}
+	decoded, err := codec.Deserialize(encoded)
+	if err != nil {
+		t.Fatalf("Deserialize failed: %v", err)
+	}
+	if !bytes.Equal(input, decoded) {
+		t.Errorf("Round-trip failed.\nExpected: %v\nGot:      %v", input, decoded)
+	}
+}
+
+func TestExternalSerializerTransforms(t *testing.T) {
+	codec := NewExternal("upper", []string{"tr", "a-z", "A-Z"}, []string{"tr", "A-Z", "a-z"}, time.Second)
+
+	got, err := codec.Serialize([]byte("hello"))
+	if err != nil {
+		t.Fatalf("Serialize failed: %v", err)
+	}
+	if string(got) != "HELLO" {
+		t.Errorf("Serialize() = %q, want %q", got, "HELLO")
+	}
+}
+
+func TestExternalSerializerErrors(t *testing.T) {
+	t.Run("Decode Only", func(t *testing.T) {
+		codec := NewExternal("ro", nil, []string{"cat"}, time.Second)
+		if _, err := codec.Serialize([]byte("x")); err == nil {
+			t.Error("Expected error when encode command is missing")
+		}
+	})
+
+	t.Run("Non-zero Exit", func(t *testing.T) {
+		codec := NewExternal("fail", []string{"sh", "-c", "echo bad envelope >&2; exit 3"}, nil, time.Second)
+		_, err := codec.Serialize([]byte("x"))
+		if err == nil || !strings.Contains(err.Error(), "bad envelope") {
+			t.Errorf("Expected error containing stderr, got %v", err)
+		}
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		codec := NewExternal("slow", []string{"sleep", "5"}, nil, 50*time.Millisecond)
+		_, err := codec.Serialize([]byte("x"))
+		if err == nil || !strings.Contains(err.Error(), "timed out") {
+			t.Errorf("Expected timeout error, got %v", err)
+		}
+	})
+}
+
+func TestRegister(t *testing.T) {
+	codec := NewExternal("mycorp", []string{"cat"}, []string{"cat"}, time.Second)
+	if err := Register("MyCorp", codec); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	got, err := Get("mycorp")
+	if err != nil {
+		t.Fatalf("Get(%q) failed: %v", "mycorp", err)
+	}
+	if got == nil {
+		t.Fatal("Get returned nil codec")
+	}
+
+	if err := Register("gzip", codec); err == nil {
+		t.Error("Expected error when replacing a built-in codec")
+	}
+}
@@ -0,0 +1,41 @@
+package serializer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// jsonSerializer implements the Serializer interface as a validating
+// pass-through: values must be well-formed JSON. On write it also compacts
+// the document so stored values carry no insignificant whitespace.
+//
+// C#: No direct equivalent — the C# version had no JSON awareness.
+type jsonSerializer struct{}
+
+func (s jsonSerializer) Serialize(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (s jsonSerializer) Deserialize(data []byte) ([]byte, error) {
+	if !json.Valid(data) {
+		return nil, fmt.Errorf("invalid JSON")
+	}
+	return data, nil
+}
+
+// IsJSON reports whether s produces JSON on read, i.e. it is the json codec
+// or a chain ending in it. Output code uses this to force pretty-printing.
+func IsJSON(s Serializer) bool {
+	switch v := s.(type) {
+	case jsonSerializer:
+		return true
+	case chainSerializer:
+		return len(v) > 0 && IsJSON(v[len(v)-1])
+	}
+	return false
+}
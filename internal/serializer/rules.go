@@ -0,0 +1,214 @@
+package serializer
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// RawModifier is the `#:raw` modifier. It bypasses codec rules so the value
+// is shown exactly as stored.
+const RawModifier = "raw"
+
+// Rule maps a key glob pattern to a codec spec (see Get).
+type Rule struct {
+	Pattern string // Redis-style glob, e.g. "session:*"
+	Codec   string // codec spec, e.g. "base64+gzip"
+}
+
+// Rules is an ordered list of key pattern → codec rules. The first matching
+// rule wins. A nil *Rules is valid and matches nothing.
+//
+// C#: No direct equivalent — the C# version required a modifier on every command.
+type Rules struct {
+	rules []Rule
+}
+
+// NewRules returns a rule set after checking that every codec spec resolves.
+func NewRules(rules []Rule) (*Rules, error) {
+	for _, r := range rules {
+		if _, err := Get(r.Codec); err != nil {
+			return nil, fmt.Errorf("rule %q: %w", r.Pattern, err)
+		}
+	}
+	return &Rules{rules: rules}, nil
+}
+
+// ParseRules reads rules in the form `pattern => codec`, one per line.
+// Blank lines and lines starting with '#' are ignored.
+//
+//	# pattern        => codec chain
+//	session:*        => base64+gzip
+//	cache:proto:*    => proto:cache.Entry
+func ParseRules(r io.Reader) (*Rules, error) {
+	var rules []Rule
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		pattern, codec, ok := strings.Cut(line, "=>")
+		pattern = strings.TrimSpace(pattern)
+		codec = strings.TrimSpace(codec)
+		if !ok || pattern == "" || codec == "" {
+			return nil, fmt.Errorf("line %d: expected `pattern => codec`, got %q", lineNo, line)
+		}
+		rules = append(rules, Rule{Pattern: pattern, Codec: codec})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return NewRules(rules)
+}
+
+// LoadRules reads a rules file. A missing file yields an empty rule set.
+func LoadRules(path string) (*Rules, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &Rules{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	rules, err := ParseRules(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return rules, nil
+}
+
+// Match returns the codec spec of the first rule whose pattern matches key,
+// or "" if none does.
+func (r *Rules) Match(key string) string {
+	if r == nil || key == "" {
+		return ""
+	}
+	for _, rule := range r.rules {
+		if MatchGlob(rule.Pattern, key) {
+			return rule.Codec
+		}
+	}
+	return ""
+}
+
+// Resolve picks the codec for a value read from or written to key.
+// An explicit modifier wins; `#:raw` disables decoding; otherwise the first
+// matching rule applies. It returns nil, nil when no codec should be used.
+func (r *Rules) Resolve(modifier, key string) (Serializer, error) {
+	if strings.EqualFold(modifier, RawModifier) {
+		return nil, nil
+	}
+	if modifier != "" {
+		return Get(modifier)
+	}
+	if spec := r.Match(key); spec != "" {
+		return Get(spec)
+	}
+	return nil, nil
+}
+
+// MatchGlob reports whether s matches a Redis-style glob pattern
+// (the same syntax as SCAN MATCH): '*' matches any run of characters
+// including ':', '?' matches one character, [abc], [^a] and [a-z] match
+// classes, and '\' escapes the next character.
+func MatchGlob(pattern, s string) bool {
+	p := []rune(pattern)
+	str := []rune(s)
+	return matchRunes(p, str)
+}
+
+func matchRunes(p, s []rune) bool {
+	for len(p) > 0 {
+		switch p[0] {
+		case '*':
+			// Collapse consecutive stars, then try every split point.
+			for len(p) > 0 && p[0] == '*' {
+				p = p[1:]
+			}
+			if len(p) == 0 {
+				return true
+			}
+			for i := 0; i <= len(s); i++ {
+				if matchRunes(p, s[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if len(s) == 0 {
+				return false
+			}
+			p, s = p[1:], s[1:]
+		case '[':
+			if len(s) == 0 {
+				return false
+			}
+			matched, rest := matchClass(p[1:], s[0])
+			if !matched {
+				return false
+			}
+			p, s = rest, s[1:]
+		case '\\':
+			if len(p) > 1 {
+				p = p[1:]
+			}
+			fallthrough
+		default:
+			if len(s) == 0 || p[0] != s[0] {
+				return false
+			}
+			p, s = p[1:], s[1:]
+		}
+	}
+	return len(s) == 0
+}
+
+// matchClass matches c against a bracket class whose opening '[' has already
+// been consumed. It returns whether c matched and the pattern after the ']'.
+func matchClass(p []rune, c rune) (bool, []rune) {
+	negate := false
+	if len(p) > 0 && p[0] == '^' {
+		negate = true
+		p = p[1:]
+	}
+
+	matched := false
+	for len(p) > 0 && p[0] != ']' {
+		switch {
+		case p[0] == '\\' && len(p) > 1:
+			if p[1] == c {
+				matched = true
+			}
+			p = p[2:]
+		case len(p) > 2 && p[1] == '-' && p[2] != ']':
+			lo, hi := p[0], p[2]
+			if lo > hi {
+				lo, hi = hi, lo
+			}
+			if c >= lo && c <= hi {
+				matched = true
+			}
+			p = p[3:]
+		default:
+			if p[0] == c {
+				matched = true
+			}
+			p = p[1:]
+		}
+	}
+	if len(p) > 0 {
+		p = p[1:] // skip ']'
+	}
+
+	return matched != negate, p
+}
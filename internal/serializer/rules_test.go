@@ -0,0 +1,132 @@
+package serializer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		key     string
+		want    bool
+	}{
+		{"session:*", "session:abc", true},
+		{"session:*", "session:a:b:c", true},
+		{"session:*", "sessions", false},
+		{"*", "", true},
+		{"user:?", "user:1", true},
+		{"user:?", "user:12", false},
+		{"user:[0-9]", "user:7", true},
+		{"user:[^0-9]", "user:7", false},
+		{"user:[ab]", "user:b", true},
+		{"a\\*b", "a*b", true},
+		{"a\\*b", "axb", false},
+		{"cache:*:v?", "cache:proto:v2", true},
+	}
+
+	for _, tt := range tests {
+		if got := MatchGlob(tt.pattern, tt.key); got != tt.want {
+			t.Errorf("MatchGlob(%q, %q) = %v, want %v", tt.pattern, tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	input := `
+# comment
+session:*   => base64+gzip
+blob:*      => snappy
+`
+	rules, err := ParseRules(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseRules failed: %v", err)
+	}
+
+	if got := rules.Match("session:1"); got != "base64+gzip" {
+		t.Errorf("Match(session:1) = %q, want %q", got, "base64+gzip")
+	}
+	if got := rules.Match("other"); got != "" {
+		t.Errorf("Match(other) = %q, want empty", got)
+	}
+
+	if _, err := ParseRules(strings.NewReader("no arrow here")); err == nil {
+		t.Error("Expected error for malformed line")
+	}
+	if _, err := ParseRules(strings.NewReader("x:* => nosuchcodec")); err == nil {
+		t.Error("Expected error for unknown codec")
+	}
+}
+
+func TestRulesResolve(t *testing.T) {
+	rules, err := NewRules([]Rule{{Pattern: "session:*", Codec: "gzip"}})
+	if err != nil {
+		t.Fatalf("NewRules failed: %v", err)
+	}
+
+	if s, err := rules.Resolve("", "session:1"); err != nil || s == nil {
+		t.Errorf("Expected rule codec, got %v, %v", s, err)
+	}
+	if s, err := rules.Resolve("raw", "session:1"); err != nil || s != nil {
+		t.Errorf("Expected #:raw to bypass rules, got %v, %v", s, err)
+	}
+	if s, err := rules.Resolve("base64", "other"); err != nil || s == nil {
+		t.Errorf("Expected explicit modifier codec, got %v, %v", s, err)
+	}
+
+	var none *Rules
+	if s, err := none.Resolve("", "session:1"); err != nil || s != nil {
+		t.Errorf("Expected nil rules to resolve nothing, got %v, %v", s, err)
+	}
+}
+
+func TestChainRoundTrip(t *testing.T) {
+	chain, err := Get("base64+gzip")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+
+	input := []byte("hello chain")
+	encoded, err := chain.Serialize(input)
+	if err != nil {
+		t.Fatalf("Serialize failed: %v", err)
+	}
+
+	// The outermost layer is base64, so the stored bytes must decode as base64
+	// into a gzip payload.
+	b64, _ := Get("base64")
+	gz, err := b64.Deserialize(encoded)
+	if err != nil {
+		t.Fatalf("Outer layer is not base64: %v", err)
+	}
+	gzc, _ := Get("gzip")
+	if plain, err := gzc.Deserialize(gz); err != nil || !bytes.Equal(plain, input) {
+		t.Errorf("Inner layer mismatch: %q, %v", plain, err)
+	}
+
+	decoded, err := chain.Deserialize(encoded)
+	if err != nil || !bytes.Equal(decoded, input) {
+		t.Errorf("Round-trip failed: %q, %v", decoded, err)
+	}
+}
+
+func TestCodecArgument(t *testing.T) {
+	if err := Register("echoarg", NewExternal("echoarg", nil, []string{"echo", "-n", "{arg}"}, time.Second)); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	codec, err := Get("echoarg:cache.Entry")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	got, err := codec.Deserialize(nil)
+	if err != nil || string(got) != "cache.Entry" {
+		t.Errorf("Deserialize() = %q, %v; want %q", got, err, "cache.Entry")
+	}
+
+	if _, err := Get("gzip:level9"); err == nil {
+		t.Error("Expected error for argument on a codec that takes none")
+	}
+}
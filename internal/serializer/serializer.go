@@ -3,6 +3,7 @@ package serializer
 import (
 	"fmt"
 	"strings"
+	"sync"
 )
 
 // Serializer defines the interface for codec plugins.
@@ -18,6 +19,41 @@ type Serializer interface {
 	Deserialize([]byte) ([]byte, error)
 }
 
+// registry holds user-registered codecs (e.g. external codecs from the config
+// file), keyed by lowercase name.
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Serializer{}
+)
+
+// builtins lists the codec names handled directly by Get. They cannot be
+// overridden by Register.
+var builtins = map[string]bool{"base64": true, "gzip": true, "snappy": true, "json": true}
+
+// Register makes a codec available to Get under the given name, so that
+// `#:name` modifiers resolve to it. Names are case-insensitive.
+// Registering a built-in name or an empty name is an error.
+func Register(name string, s Serializer) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return fmt.Errorf("codec name must not be empty")
+	}
+	if builtins[name] {
+		return fmt.Errorf("codec %q is built in and cannot be replaced", name)
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[name] = s
+	return nil
+}
+
+// parameterized is implemented by codecs that accept an argument in their
+// spec, e.g. "proto:cache.Entry" passes "cache.Entry" to the "proto" codec.
+type parameterized interface {
+	WithArg(arg string) (Serializer, error)
+}
+
 // Get returns a Serializer instance by name.
 //
 // C#:
@@ -26,15 +62,49 @@ type Serializer interface {
 // Go:
 // We return an error if the name is unknown, rather than returning nil.
 // This forces the caller to handle the missing codec explicitly.
+//
+// The name may also be a chain such as "base64+gzip" (see newChain) and each
+// element may carry an argument after a colon, e.g. "proto:cache.Entry".
 func Get(name string) (Serializer, error) {
-	switch strings.ToLower(name) {
+	name = strings.TrimSpace(name)
+	if strings.Contains(name, "+") {
+		return newChain(name)
+	}
+
+	base, arg, hasArg := strings.Cut(name, ":")
+	s, err := getOne(base)
+	if err != nil {
+		return nil, err
+	}
+	if !hasArg {
+		return s, nil
+	}
+	p, ok := s.(parameterized)
+	if !ok {
+		return nil, fmt.Errorf("codec %q does not take an argument", base)
+	}
+	return p.WithArg(arg)
+}
+
+// getOne resolves a single codec name without chain or argument syntax.
+func getOne(name string) (Serializer, error) {
+	name = strings.ToLower(name)
+	switch name {
 	case "base64":
 		return base64Serializer{}, nil
 	case "gzip":
 		return gzipSerializer{}, nil
 	case "snappy":
 		return snappySerializer{}, nil
-	default:
-		return nil, fmt.Errorf("unknown serializer: %q", name)
+	case "json":
+		return jsonSerializer{}, nil
+	}
+
+	registryMu.RLock()
+	s, ok := registry[name]
+	registryMu.RUnlock()
+	if ok {
+		return s, nil
 	}
+	return nil, fmt.Errorf("unknown serializer: %q", name)
 }
@@ -66,3 +66,37 @@ func TestGetUnknownSerializer(t *testing.T) {
 		t.Errorf("Expected nil codec for unknown serializer, got %T", codec)
 	}
 }
+
+func TestJSONSerializer(t *testing.T) {
+	codec, err := Get("json")
+	if err != nil {
+		t.Fatalf("Get(json) failed: %v", err)
+	}
+
+	got, err := codec.Serialize([]byte(`{ "a" : [1, 2] }`))
+	if err != nil {
+		t.Fatalf("Serialize failed: %v", err)
+	}
+	if string(got) != `{"a":[1,2]}` {
+		t.Errorf("Serialize() = %q, want compacted JSON", got)
+	}
+
+	if _, err := codec.Serialize([]byte(`{a:1}`)); err == nil {
+		t.Error("Expected error for invalid JSON on write")
+	}
+	if _, err := codec.Deserialize([]byte(`not json`)); err == nil {
+		t.Error("Expected error for invalid JSON on read")
+	}
+
+	if !IsJSON(codec) {
+		t.Error("IsJSON(json) = false, want true")
+	}
+	chain, _ := Get("base64+json")
+	if !IsJSON(chain) {
+		t.Error("IsJSON(base64+json) = false, want true")
+	}
+	gz, _ := Get("gzip")
+	if IsJSON(gz) {
+		t.Error("IsJSON(gzip) = true, want false")
+	}
+}
@@ -0,0 +1,125 @@
+package server
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/cosmez/redisman-go/internal/conn"
+	"github.com/cosmez/redisman-go/internal/keyspace"
+)
+
+// ClientSorts are the columns clients can be sorted by, in the order the TUI
+// cycles through them. "id" keeps the server's order, oldest connection
+// first; durations and memory sort largest first, text alphabetically.
+var ClientSorts = []string{"id", "addr", "name", "age", "idle", "db", "cmd", "memory"}
+
+// ClientOptions selects and orders CLIENT LIST entries.
+type ClientOptions struct {
+	Sort   string   // one of ClientSorts, "" for "id"
+	Filter string   // keep clients whose address, name, user or last command contain this, case-insensitive
+	Args   []string // passed on to CLIENT LIST, e.g. TYPE pubsub
+}
+
+// Clients fetches CLIENT LIST and applies opts.
+func Clients(c *conn.Connection, opts ClientOptions) ([]conn.ClientInfo, error) {
+	clients, err := c.ClientList(opts.Args...)
+	if err != nil {
+		return nil, err
+	}
+	return opts.Apply(clients), nil
+}
+
+// Apply filters and sorts clients, in place.
+func (o ClientOptions) Apply(clients []conn.ClientInfo) []conn.ClientInfo {
+	if o.Filter != "" {
+		filter := strings.ToLower(o.Filter)
+		clients = slices.DeleteFunc(clients, func(ci conn.ClientInfo) bool {
+			for _, s := range []string{ci.Addr, ci.Name, ci.User, ci.Cmd} {
+				if strings.Contains(strings.ToLower(s), filter) {
+					return false
+				}
+			}
+			return true
+		})
+	}
+	slices.SortStableFunc(clients, func(a, b conn.ClientInfo) int {
+		switch o.Sort {
+		case "addr":
+			return strings.Compare(a.Addr, b.Addr)
+		case "name":
+			return strings.Compare(a.Name, b.Name)
+		case "age":
+			return cmp.Compare(b.Age, a.Age)
+		case "idle":
+			return cmp.Compare(b.Idle, a.Idle)
+		case "db":
+			return cmp.Compare(a.DB, b.DB)
+		case "cmd":
+			return strings.Compare(a.Cmd, b.Cmd)
+		case "memory":
+			return cmp.Compare(b.Memory, a.Memory)
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return clients
+}
+
+// ClientRow returns the table cells of a client: id, address, name, age,
+// idle time, db, last command, memory and flags.
+func ClientRow(ci conn.ClientInfo) []string {
+	memory := ""
+	if _, ok := ci.Fields["tot-mem"]; ok {
+		memory = keyspace.FormatBytes(ci.Memory)
+	}
+	return []string{
+		strconv.FormatInt(ci.ID, 10), ci.Addr, ci.Name, ci.Age.String(), ci.Idle.String(),
+		strconv.Itoa(ci.DB), ci.Cmd, memory, ci.Flags,
+	}
+}
+
+// ClientHeaders are the column names matching ClientRow.
+var ClientHeaders = []string{"ID", "Addr", "Name", "Age", "Idle", "DB", "Cmd", "Memory", "Flags"}
+
+// WriteClients writes clients as an aligned table.
+func WriteClients(w io.Writer, clients []conn.ClientInfo) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.ToUpper(strings.Join(ClientHeaders, "\t")))
+	for _, ci := range clients {
+		fmt.Fprintln(tw, strings.Join(ClientRow(ci), "\t"))
+	}
+	return tw.Flush()
+}
+
+// ParseClientArgs parses the arguments after CLIENT LIST. SORT column and
+// FILTER text are handled here; anything else, such as TYPE normal or
+// ID 1 2, is passed on to Redis.
+func ParseClientArgs(args []string) (ClientOptions, error) {
+	var opts ClientOptions
+	for i := 0; i < len(args); i++ {
+		switch strings.ToUpper(args[i]) {
+		case "SORT":
+			if i+1 >= len(args) {
+				return opts, fmt.Errorf("SORT needs a column")
+			}
+			opts.Sort = strings.ToLower(args[i+1])
+			if !slices.Contains(ClientSorts, opts.Sort) {
+				return opts, fmt.Errorf("SORT must be one of %s", strings.Join(ClientSorts, ", "))
+			}
+			i++
+		case "FILTER":
+			if i+1 >= len(args) {
+				return opts, fmt.Errorf("FILTER needs a text")
+			}
+			opts.Filter = args[i+1]
+			i++
+		default:
+			opts.Args = append(opts.Args, args[i])
+		}
+	}
+	return opts, nil
+}
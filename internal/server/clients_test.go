@@ -0,0 +1,68 @@
+package server
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cosmez/redisman-go/internal/conn"
+)
+
+func TestClientOptions(t *testing.T) {
+	clients := func() []conn.ClientInfo {
+		return []conn.ClientInfo{
+			{ID: 1, Addr: "10.0.0.1:1", Name: "api", Idle: time.Second, Memory: 100, Cmd: "get"},
+			{ID: 2, Addr: "10.0.0.2:1", Name: "worker", Idle: time.Hour, Memory: 50, Cmd: "blpop"},
+			{ID: 3, Addr: "10.0.0.3:1", Idle: time.Minute, Memory: 900, Cmd: "client|list", Fields: map[string]string{"tot-mem": "900"}},
+		}
+	}
+	ids := func(cs []conn.ClientInfo) []int64 {
+		var out []int64
+		for _, c := range cs {
+			out = append(out, c.ID)
+		}
+		return out
+	}
+
+	tests := []struct {
+		opts ClientOptions
+		want []int64
+	}{
+		{ClientOptions{}, []int64{1, 2, 3}},
+		{ClientOptions{Sort: "idle"}, []int64{2, 3, 1}},
+		{ClientOptions{Sort: "memory"}, []int64{3, 1, 2}},
+		{ClientOptions{Sort: "name"}, []int64{3, 1, 2}},
+		{ClientOptions{Filter: "WORK"}, []int64{2}},
+		{ClientOptions{Filter: "10.0.0.3"}, []int64{3}},
+	}
+	for _, tt := range tests {
+		if got := ids(tt.opts.Apply(clients())); !slices.Equal(got, tt.want) {
+			t.Errorf("%+v: ids %v, want %v", tt.opts, got, tt.want)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := WriteClients(&buf, clients()); err != nil {
+		t.Fatalf("WriteClients failed: %v", err)
+	}
+	if out := buf.String(); !strings.HasPrefix(out, "ID  ADDR") || !strings.Contains(out, "900 B") {
+		t.Errorf("WriteClients output:\n%s", out)
+	}
+}
+
+func TestParseClientArgs(t *testing.T) {
+	opts, err := ParseClientArgs([]string{"TYPE", "normal", "sort", "Idle", "FILTER", "api"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.Sort != "idle" || opts.Filter != "api" || !slices.Equal(opts.Args, []string{"TYPE", "normal"}) {
+		t.Errorf("opts = %+v", opts)
+	}
+	for _, args := range [][]string{{"SORT"}, {"SORT", "flags"}, {"FILTER"}} {
+		if _, err := ParseClientArgs(args); err == nil {
+			t.Errorf("ParseClientArgs(%q) should fail", args)
+		}
+	}
+}
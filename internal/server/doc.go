@@ -0,0 +1,7 @@
+// Package server reads and formats server-side diagnostics that Redis
+// returns as nested arrays or packed text, such as SLOWLOG GET, CLIENT LIST
+// and PUBSUB CHANNELS, so the REPL and the TUI can show them as tables. It
+// also filters and formats the MONITOR and Pub/Sub streams.
+//
+// C#: No direct equivalent — the C# version printed these replies raw.
+package server
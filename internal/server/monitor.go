@@ -0,0 +1,90 @@
+package server
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/cosmez/redisman-go/internal/conn"
+	"github.com/cosmez/redisman-go/internal/serializer"
+)
+
+// MonitorTimeFormat is how MONITOR times are shown.
+const MonitorTimeFormat = "15:04:05.000"
+
+// MonitorFilter selects MONITOR events. Empty fields match everything.
+type MonitorFilter struct {
+	Commands   []string // upper-cased command names
+	KeyPattern string   // glob matched against the first argument after the command
+	Client     string   // text the client address must contain
+}
+
+// Match reports whether e passes the filter.
+func (f MonitorFilter) Match(e conn.MonitorEvent) bool {
+	if len(f.Commands) > 0 && !slices.Contains(f.Commands, e.Command()) {
+		return false
+	}
+	if f.KeyPattern != "" && (len(e.Args) < 2 || !serializer.MatchGlob(f.KeyPattern, e.Args[1])) {
+		return false
+	}
+	return f.Client == "" || strings.Contains(e.Client, f.Client)
+}
+
+// String describes the filter, e.g. "GET,SET on user:* from 10.0.0.1", or
+// returns "" if it matches everything.
+func (f MonitorFilter) String() string {
+	var parts []string
+	if len(f.Commands) > 0 {
+		parts = append(parts, strings.Join(f.Commands, ","))
+	}
+	if f.KeyPattern != "" {
+		parts = append(parts, "on "+f.KeyPattern)
+	}
+	if f.Client != "" {
+		parts = append(parts, "from "+f.Client)
+	}
+	return strings.Join(parts, " ")
+}
+
+// FormatMonitorEvent renders e as "time db client command line".
+func FormatMonitorEvent(e conn.MonitorEvent) string {
+	return fmt.Sprintf("%s [%d %s] %s", e.Time.Format(MonitorTimeFormat), e.DB, e.Client, commandLine(e.Args))
+}
+
+// ParseMonitorCommands splits a comma- or space-separated list of command
+// names and upper-cases them.
+func ParseMonitorCommands(list string) []string {
+	var names []string
+	for name := range strings.FieldsFuncSeq(list, func(r rune) bool { return r == ',' || r == ' ' }) {
+		names = append(names, strings.ToUpper(name))
+	}
+	return names
+}
+
+// ParseMonitorArgs parses the MONITOR built-in's options:
+// [CMD name[,name...]] [KEY pattern] [CLIENT text].
+func ParseMonitorArgs(args []string) (MonitorFilter, error) {
+	var f MonitorFilter
+	for i := 0; i < len(args); i++ {
+		option := strings.ToUpper(args[i])
+		if i+1 >= len(args) {
+			switch option {
+			case "CMD", "KEY", "CLIENT":
+				return f, fmt.Errorf("%s needs a value", option)
+			}
+			return f, fmt.Errorf("unknown option %q", args[i])
+		}
+		switch option {
+		case "CMD":
+			f.Commands = append(f.Commands, ParseMonitorCommands(args[i+1])...)
+		case "KEY":
+			f.KeyPattern = args[i+1]
+		case "CLIENT":
+			f.Client = args[i+1]
+		default:
+			return f, fmt.Errorf("unknown option %q", args[i])
+		}
+		i++
+	}
+	return f, nil
+}
@@ -0,0 +1,65 @@
+package server
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cosmez/redisman-go/internal/conn"
+)
+
+func TestMonitorFilter(t *testing.T) {
+	events := []conn.MonitorEvent{
+		{Client: "10.0.0.1:5000", Args: []string{"get", "user:1"}},
+		{Client: "10.0.0.2:5000", Args: []string{"set", "user:2", "x"}},
+		{Client: "10.0.0.1:5000", Args: []string{"get", "session:1"}},
+		{Client: "lua", Args: []string{"ping"}},
+	}
+	tests := []struct {
+		filter MonitorFilter
+		want   []int
+	}{
+		{MonitorFilter{}, []int{0, 1, 2, 3}},
+		{MonitorFilter{Commands: []string{"GET"}}, []int{0, 2}},
+		{MonitorFilter{KeyPattern: "user:*"}, []int{0, 1}},
+		{MonitorFilter{Client: "10.0.0.1"}, []int{0, 2}},
+		{MonitorFilter{Commands: []string{"GET", "SET"}, KeyPattern: "user:*", Client: "10.0.0.2"}, []int{1}},
+	}
+	for _, tt := range tests {
+		var got []int
+		for i, e := range events {
+			if tt.filter.Match(e) {
+				got = append(got, i)
+			}
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%q matched %v, want %v", tt.filter, got, tt.want)
+		}
+	}
+}
+
+func TestParseMonitorArgs(t *testing.T) {
+	f, err := ParseMonitorArgs([]string{"cmd", "get,set", "CMD", "del", "KEY", "user:*", "CLIENT", "10.0.0.1"})
+	if err != nil {
+		t.Fatalf("ParseMonitorArgs failed: %v", err)
+	}
+	if !slices.Equal(f.Commands, []string{"GET", "SET", "DEL"}) || f.KeyPattern != "user:*" || f.Client != "10.0.0.1" {
+		t.Errorf("Got %+v", f)
+	}
+	if got := f.String(); got != "GET,SET,DEL on user:* from 10.0.0.1" {
+		t.Errorf("String() = %q", got)
+	}
+	for _, args := range [][]string{{"KEY"}, {"FOO", "x"}, {"CMD", "get", "extra"}} {
+		if _, err := ParseMonitorArgs(args); err == nil {
+			t.Errorf("ParseMonitorArgs(%q) should fail", args)
+		}
+	}
+}
+
+func TestFormatMonitorEvent(t *testing.T) {
+	e := conn.MonitorEvent{Time: time.Date(2024, 1, 2, 3, 4, 5, 678e6, time.Local), DB: 1, Client: "lua", Args: []string{"set", "k", "a b"}}
+	if got := FormatMonitorEvent(e); !strings.HasSuffix(got, `[1 lua] set k "a b"`) || !strings.HasPrefix(got, "03:04:05.678") {
+		t.Errorf("FormatMonitorEvent = %q", got)
+	}
+}
@@ -0,0 +1,150 @@
+package server
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/cosmez/redisman-go/internal/conn"
+	"github.com/cosmez/redisman-go/internal/resp"
+	"github.com/cosmez/redisman-go/internal/serializer"
+)
+
+// NotifyConfig is the server setting that turns keyspace notifications on.
+const NotifyConfig = "notify-keyspace-events"
+
+// KeyEvent is a keyspace notification: Event (e.g. "set", "expired")
+// happened to Key in database DB.
+type KeyEvent struct {
+	DB    int
+	Key   string
+	Event string
+}
+
+// ParseKeyEvent reads a keyspace notification from a Pub/Sub message sent on
+// __keyspace@<db>__:<key>, whose payload is the event, or on
+// __keyevent@<db>__:<event>, whose payload is the key.
+func ParseKeyEvent(m conn.PubSubMessage) (KeyEvent, bool) {
+	kind, rest, ok := strings.Cut(strings.TrimPrefix(m.Channel, "__key"), "@")
+	if !ok || (kind != "space" && kind != "event") {
+		return KeyEvent{}, false
+	}
+	db, name, ok := strings.Cut(rest, "__:")
+	n, err := strconv.Atoi(db)
+	if !ok || err != nil {
+		return KeyEvent{}, false
+	}
+	if kind == "space" {
+		return KeyEvent{DB: n, Key: name, Event: m.Payload}, true
+	}
+	return KeyEvent{DB: n, Key: m.Payload, Event: name}, true
+}
+
+// WatchOptions selects the notifications WATCHKEYS subscribes to.
+type WatchOptions struct {
+	Pattern string   // glob of the keys to watch
+	Events  []string // lower-case event names such as "set" or "expired", empty for all
+}
+
+// ParseWatchArgs parses WATCHKEYS [pattern] [EVENT name[,name...]]. The
+// pattern defaults to "*".
+func ParseWatchArgs(args []string) (WatchOptions, error) {
+	opts := WatchOptions{Pattern: "*"}
+	for i := 0; i < len(args); i++ {
+		switch {
+		case strings.EqualFold(args[i], "EVENT"):
+			if i+1 >= len(args) {
+				return opts, fmt.Errorf("EVENT needs an event name")
+			}
+			for name := range strings.FieldsFuncSeq(args[i+1], func(r rune) bool { return r == ',' || r == ' ' }) {
+				opts.Events = append(opts.Events, strings.ToLower(name))
+			}
+			i++
+		case i == 0:
+			opts.Pattern = args[i]
+		default:
+			return opts, fmt.Errorf("unknown option %q", args[i])
+		}
+	}
+	return opts, nil
+}
+
+// Channels returns the patterns to PSUBSCRIBE to for db: the keyspace
+// channels of the matching keys or, with Events, the keyevent channels of
+// those events.
+func (o WatchOptions) Channels(db int) []string {
+	if len(o.Events) == 0 {
+		return []string{fmt.Sprintf("__keyspace@%d__:%s", db, o.Pattern)}
+	}
+	channels := make([]string, len(o.Events))
+	for i, event := range o.Events {
+		channels[i] = fmt.Sprintf("__keyevent@%d__:%s", db, event)
+	}
+	return channels
+}
+
+// Flags returns the notify-keyspace-events flags the subscription needs:
+// K for keyspace or E for keyevent channels, and A for every event class.
+func (o WatchOptions) Flags() string {
+	if len(o.Events) == 0 {
+		return "KA"
+	}
+	return "EA"
+}
+
+// Match reports whether e is a notification to show. Keyevent channels carry
+// every key, so the pattern is checked here too.
+func (o WatchOptions) Match(e KeyEvent) bool {
+	if len(o.Events) > 0 && !slices.Contains(o.Events, e.Event) {
+		return false
+	}
+	return serializer.MatchGlob(o.Pattern, e.Key)
+}
+
+// String describes the options, e.g. "user:*" or "user:* on set,del".
+func (o WatchOptions) String() string {
+	if len(o.Events) == 0 {
+		return o.Pattern
+	}
+	return o.Pattern + " on " + strings.Join(o.Events, ",")
+}
+
+// EnableNotifications makes sure notify-keyspace-events includes flags,
+// adding the missing ones with CONFIG SET. It returns the setting before and
+// after, which are equal if nothing had to change. CONFIG is often renamed
+// or denied on managed services; the error then says so and the caller can
+// only warn that events may not arrive.
+func EnableNotifications(c *conn.Connection, flags string) (before, after string, err error) {
+	reply, err := c.Do("CONFIG", "GET", NotifyConfig)
+	if err != nil {
+		return "", "", fmt.Errorf("cannot read %s: %w", NotifyConfig, err)
+	}
+	arr, ok := reply.(resp.RedisArray)
+	if !ok || len(arr.Values) != 2 {
+		return "", "", fmt.Errorf("cannot read %s: unexpected CONFIG GET reply", NotifyConfig)
+	}
+	before = arr.Values[1].StringValue()
+	after = before
+	for _, flag := range flags {
+		if !strings.ContainsRune(after, flag) {
+			after += string(flag)
+		}
+	}
+	if after == before {
+		return before, after, nil
+	}
+	if _, err := c.Do("CONFIG", "SET", NotifyConfig, after); err != nil {
+		return before, before, fmt.Errorf("cannot set %s to %q: %w", NotifyConfig, after, err)
+	}
+	return before, after, nil
+}
+
+// RestoreNotifications sets notify-keyspace-events back to before, as
+// returned by EnableNotifications, once the watch that needed it has ended.
+func RestoreNotifications(c *conn.Connection, before string) error {
+	if _, err := c.Do("CONFIG", "SET", NotifyConfig, before); err != nil {
+		return fmt.Errorf("cannot restore %s to %q: %w", NotifyConfig, before, err)
+	}
+	return nil
+}
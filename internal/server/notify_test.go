@@ -0,0 +1,94 @@
+package server
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/cosmez/redisman-go/internal/conn"
+	"github.com/cosmez/redisman-go/internal/redistest"
+)
+
+func TestParseKeyEvent(t *testing.T) {
+	tests := []struct {
+		msg  conn.PubSubMessage
+		want KeyEvent
+		ok   bool
+	}{
+		{conn.PubSubMessage{Channel: "__keyspace@0__:user:1", Payload: "set"}, KeyEvent{0, "user:1", "set"}, true},
+		{conn.PubSubMessage{Channel: "__keyevent@3__:expired", Payload: "a@b"}, KeyEvent{3, "a@b", "expired"}, true},
+		{conn.PubSubMessage{Channel: "news", Payload: "set"}, KeyEvent{}, false},
+		{conn.PubSubMessage{Channel: "__keyspace@x__:k", Payload: "set"}, KeyEvent{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseKeyEvent(tt.msg)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseKeyEvent(%q) = %+v, %v; want %+v, %v", tt.msg.Channel, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestWatchOptions(t *testing.T) {
+	opts, err := ParseWatchArgs(nil)
+	if err != nil || opts.Pattern != "*" || opts.Flags() != "KA" {
+		t.Errorf("Defaults = %+v, %v", opts, err)
+	}
+	if got := opts.Channels(2); !slices.Equal(got, []string{"__keyspace@2__:*"}) {
+		t.Errorf("Channels() = %q", got)
+	}
+
+	opts, err = ParseWatchArgs([]string{"user:*", "event", "SET,del"})
+	if err != nil {
+		t.Fatalf("ParseWatchArgs failed: %v", err)
+	}
+	if got := opts.Channels(0); !slices.Equal(got, []string{"__keyevent@0__:set", "__keyevent@0__:del"}) || opts.Flags() != "EA" {
+		t.Errorf("Channels() = %q, Flags() = %q", got, opts.Flags())
+	}
+	if !opts.Match(KeyEvent{Key: "user:1", Event: "del"}) || opts.Match(KeyEvent{Key: "order:1", Event: "del"}) || opts.Match(KeyEvent{Key: "user:1", Event: "expire"}) {
+		t.Error("Match should check both the key pattern and the events")
+	}
+	if opts.String() != "user:* on set,del" {
+		t.Errorf("String() = %q", opts.String())
+	}
+
+	for _, args := range [][]string{{"a", "b"}, {"a", "EVENT"}} {
+		if _, err := ParseWatchArgs(args); err == nil {
+			t.Errorf("ParseWatchArgs(%q) should fail", args)
+		}
+	}
+}
+
+func TestEnableNotifications(t *testing.T) {
+	setting, denied := "Ex", false
+	s := redistest.NewServer(t)
+	s.Handle("CONFIG", func(args []string) string {
+		if args[1] == "SET" {
+			if denied {
+				return redistest.Err("NOPERM this user has no permissions to run the 'config|set' command")
+			}
+			setting = args[3]
+			return redistest.Simple("OK")
+		}
+		return redistest.BulkArray(NotifyConfig, setting)
+	})
+	c, err := conn.Connect(s.Host, s.Port, "", "")
+	if err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer c.Close()
+
+	before, after, err := EnableNotifications(c, "KA")
+	if err != nil || before != "Ex" || after != "ExKA" || setting != "ExKA" {
+		t.Errorf("EnableNotifications() = %q, %q, %v; setting %q", before, after, err, setting)
+	}
+	if before, after, err = EnableNotifications(c, "KA"); err != nil || before != after {
+		t.Errorf("Nothing should change when the flags are set: %q, %q, %v", before, after, err)
+	}
+	if err := RestoreNotifications(c, "Ex"); err != nil || setting != "Ex" {
+		t.Errorf("RestoreNotifications() = %v; setting %q, want %q", err, setting, "Ex")
+	}
+
+	setting, denied = "", true
+	if _, after, err = EnableNotifications(c, "KA"); err == nil || after != "" {
+		t.Errorf("A denied CONFIG SET should fail and leave the setting: %q, %v", after, err)
+	}
+}
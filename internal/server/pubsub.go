@@ -0,0 +1,84 @@
+package server
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/cosmez/redisman-go/internal/conn"
+	"github.com/cosmez/redisman-go/internal/serializer"
+)
+
+// ChannelInfo is a Pub/Sub channel and its number of subscribers.
+type ChannelInfo struct {
+	Channel     string
+	Subscribers int64
+}
+
+// Channels lists the active channels matching pattern (all if empty) with
+// their subscriber counts, busiest first. With shard, shard channels are
+// listed instead.
+func Channels(c *conn.Connection, shard bool, pattern string) ([]ChannelInfo, error) {
+	names, err := c.PubSubChannels(shard, pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+	channels, err := NumSub(c, shard, names)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(channels, func(a, b ChannelInfo) int {
+		return cmp.Or(cmp.Compare(b.Subscribers, a.Subscribers), strings.Compare(a.Channel, b.Channel))
+	})
+	return channels, nil
+}
+
+// NumSub returns the subscriber counts of names, in their order.
+func NumSub(c *conn.Connection, shard bool, names []string) ([]ChannelInfo, error) {
+	counts, err := c.PubSubNumSub(shard, names...)
+	if err != nil {
+		return nil, err
+	}
+	channels := make([]ChannelInfo, len(names))
+	for i, name := range names {
+		channels[i] = ChannelInfo{Channel: name, Subscribers: counts[name]}
+	}
+	return channels, nil
+}
+
+// WriteChannels prints channels as an aligned table.
+func WriteChannels(w io.Writer, channels []ChannelInfo) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CHANNEL\tSUBSCRIBERS")
+	for _, ch := range channels {
+		fmt.Fprintf(tw, "%s\t%d\n", ch.Channel, ch.Subscribers)
+	}
+	return tw.Flush()
+}
+
+// DecodePayload returns payload decoded with ser, or as received if ser is
+// nil or cannot decode it.
+func DecodePayload(payload string, ser serializer.Serializer) string {
+	if ser == nil {
+		return payload
+	}
+	if out, err := ser.Deserialize([]byte(payload)); err == nil {
+		return string(out)
+	}
+	return payload
+}
+
+// MessageSource returns where a message came from: its channel, followed by
+// the pattern it matched for a pmessage, e.g. "news.sport (news.*)".
+func MessageSource(m conn.PubSubMessage) string {
+	if m.Pattern != "" {
+		return m.Channel + " (" + m.Pattern + ")"
+	}
+	return m.Channel
+}
@@ -0,0 +1,61 @@
+package server
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/cosmez/redisman-go/internal/conn"
+	"github.com/cosmez/redisman-go/internal/redistest"
+	"github.com/cosmez/redisman-go/internal/serializer"
+)
+
+func TestChannels(t *testing.T) {
+	s := redistest.NewServer(t)
+	s.Handle("PUBSUB", func(args []string) string {
+		switch strings.ToUpper(args[1]) {
+		case "CHANNELS":
+			return redistest.BulkArray("a", "b", "c")
+		case "NUMSUB":
+			counts := map[string]string{"a": "1", "b": "5", "c": "1"}
+			var reply []string
+			for _, ch := range args[2:] {
+				reply = append(reply, redistest.Bulk(ch), ":"+counts[ch]+"\r\n")
+			}
+			return redistest.Array(reply...)
+		}
+		return redistest.Err("ERR unknown subcommand")
+	})
+	c, err := conn.Connect(s.Host, s.Port, "", "")
+	if err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer c.Close()
+
+	channels, err := Channels(c, false, "")
+	if err != nil {
+		t.Fatalf("Channels failed: %v", err)
+	}
+	want := []ChannelInfo{{"b", 5}, {"a", 1}, {"c", 1}}
+	if len(channels) != 3 || channels[0] != want[0] || channels[1] != want[1] || channels[2] != want[2] {
+		t.Errorf("Channels() = %v, want %v", channels, want)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteChannels(&buf, channels); err != nil || !strings.HasPrefix(buf.String(), "CHANNEL  SUBSCRIBERS\nb") {
+		t.Errorf("WriteChannels output:\n%s", buf.String())
+	}
+}
+
+func TestDecodePayload(t *testing.T) {
+	b64, _ := serializer.Get("base64")
+	if got := DecodePayload("aGk=", b64); got != "hi" {
+		t.Errorf("DecodePayload(base64) = %q", got)
+	}
+	if got := DecodePayload("not base64!", b64); got != "not base64!" {
+		t.Errorf("Undecodable payload = %q, want it unchanged", got)
+	}
+	if got := MessageSource(conn.PubSubMessage{Channel: "news.eu", Pattern: "news.*"}); got != "news.eu (news.*)" {
+		t.Errorf("MessageSource() = %q", got)
+	}
+}
@@ -0,0 +1,202 @@
+package server
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cosmez/redisman-go/internal/conn"
+	"github.com/cosmez/redisman-go/internal/resp"
+)
+
+// Slowlog sort orders.
+const (
+	SortByTime     = "time"     // newest first, as SLOWLOG GET returns them
+	SortByDuration = "duration" // slowest first
+)
+
+// DefaultSlowlogCount is how many entries SLOWLOG GET asks for by default,
+// the default slowlog-max-len.
+const DefaultSlowlogCount = 128
+
+// SlowlogEntry is one entry of SLOWLOG GET.
+type SlowlogEntry struct {
+	ID       int64
+	Time     time.Time
+	Duration time.Duration
+	Args     []string
+	Client   string // ip:port, Redis 4.0+
+	Name     string // CLIENT SETNAME, Redis 4.0+
+}
+
+// Command returns the upper-cased command name, e.g. "HGETALL".
+func (e SlowlogEntry) Command() string {
+	if len(e.Args) == 0 {
+		return ""
+	}
+	return strings.ToUpper(e.Args[0])
+}
+
+// CommandLine returns the arguments as one line, see commandLine.
+func (e SlowlogEntry) CommandLine() string {
+	return commandLine(e.Args)
+}
+
+// commandLine joins args into one line; arguments that are empty or contain
+// spaces or control characters are quoted.
+func commandLine(args []string) string {
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		if arg == "" || strings.ContainsFunc(arg, func(r rune) bool { return r <= ' ' || r == '"' || r == 0x7f }) {
+			arg = strconv.Quote(arg)
+		}
+		parts[i] = arg
+	}
+	return strings.Join(parts, " ")
+}
+
+// SlowlogOptions selects and orders slowlog entries.
+type SlowlogOptions struct {
+	Count   int    // entries to fetch, -1 for all (Redis 7.0+)
+	Sort    string // SortByTime or SortByDuration
+	Command string // keep only this command, case-insensitive; empty for all
+}
+
+// Slowlog fetches the slowlog with SLOWLOG GET and applies opts.
+func Slowlog(c *conn.Connection, opts SlowlogOptions) ([]SlowlogEntry, error) {
+	reply, err := c.Do("SLOWLOG", "GET", strconv.Itoa(cmp.Or(opts.Count, DefaultSlowlogCount)))
+	if err != nil {
+		return nil, err
+	}
+	entries, err := ParseSlowlog(reply)
+	if err != nil {
+		return nil, err
+	}
+	return opts.Apply(entries), nil
+}
+
+// Apply filters entries by command and sorts them, in place.
+func (o SlowlogOptions) Apply(entries []SlowlogEntry) []SlowlogEntry {
+	if o.Command != "" {
+		entries = slices.DeleteFunc(entries, func(e SlowlogEntry) bool {
+			return !strings.EqualFold(e.Command(), o.Command)
+		})
+	}
+	if o.Sort == SortByDuration {
+		slices.SortStableFunc(entries, func(a, b SlowlogEntry) int { return cmp.Compare(b.Duration, a.Duration) })
+	} else {
+		slices.SortStableFunc(entries, func(a, b SlowlogEntry) int { return cmp.Compare(b.ID, a.ID) })
+	}
+	return entries
+}
+
+// ParseSlowlog turns a SLOWLOG GET reply into entries. Each entry is an
+// array of id, unix time, microseconds, arguments and, since Redis 4.0,
+// client address and name.
+func ParseSlowlog(v resp.RedisValue) ([]SlowlogEntry, error) {
+	arr, ok := v.(resp.RedisArray)
+	if !ok {
+		return nil, fmt.Errorf("expected an array for SLOWLOG GET, got %T", v)
+	}
+	entries := make([]SlowlogEntry, 0, len(arr.Values))
+	for i, item := range arr.Values {
+		fields, ok := item.(resp.RedisArray)
+		if !ok || len(fields.Values) < 4 {
+			return nil, fmt.Errorf("slowlog entry %d: expected at least 4 fields", i)
+		}
+		integer := func(j int) int64 {
+			if n, ok := fields.Values[j].(resp.RedisInteger); ok {
+				return n.IntValue
+			}
+			return 0
+		}
+		e := SlowlogEntry{
+			ID:       integer(0),
+			Time:     time.Unix(integer(1), 0),
+			Duration: time.Duration(integer(2)) * time.Microsecond,
+		}
+		if args, ok := fields.Values[3].(resp.RedisArray); ok {
+			for _, arg := range args.Values {
+				e.Args = append(e.Args, arg.StringValue())
+			}
+		}
+		if len(fields.Values) > 5 {
+			e.Client = fields.Values[4].StringValue()
+			e.Name = fields.Values[5].StringValue()
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// SlowlogLen returns the number of entries in the slowlog.
+func SlowlogLen(c *conn.Connection) (int64, error) {
+	reply, err := c.Do("SLOWLOG", "LEN")
+	if err != nil {
+		return 0, err
+	}
+	n, ok := reply.(resp.RedisInteger)
+	if !ok {
+		return 0, fmt.Errorf("expected an integer for SLOWLOG LEN, got %T", reply)
+	}
+	return n.IntValue, nil
+}
+
+// ResetSlowlog empties the slowlog.
+func ResetSlowlog(c *conn.Connection) error {
+	_, err := c.Do("SLOWLOG", "RESET")
+	return err
+}
+
+// TimeFormat is how slowlog and client times are shown.
+const TimeFormat = "2006-01-02 15:04:05"
+
+// WriteSlowlog writes entries as an aligned table.
+func WriteSlowlog(w io.Writer, entries []SlowlogEntry) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tTIME\tDURATION\tCLIENT\tNAME\tCOMMAND")
+	for _, e := range entries {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\n", e.ID, e.Time.Format(TimeFormat), e.Duration, e.Client, e.Name, e.CommandLine())
+	}
+	return tw.Flush()
+}
+
+// ParseSlowlogArgs parses the arguments after SLOWLOG GET:
+// [count] [SORT time|duration] [CMD name].
+func ParseSlowlogArgs(args []string) (SlowlogOptions, error) {
+	opts := SlowlogOptions{Count: DefaultSlowlogCount, Sort: SortByTime}
+	for i := 0; i < len(args); i++ {
+		switch strings.ToUpper(args[i]) {
+		case "SORT":
+			if i+1 >= len(args) {
+				return opts, fmt.Errorf("SORT needs time or duration")
+			}
+			opts.Sort = strings.ToLower(args[i+1])
+			if opts.Sort != SortByTime && opts.Sort != SortByDuration {
+				return opts, fmt.Errorf("SORT must be time or duration")
+			}
+			i++
+		case "CMD":
+			if i+1 >= len(args) {
+				return opts, fmt.Errorf("CMD needs a command name")
+			}
+			opts.Command = args[i+1]
+			i++
+		default:
+			n, err := strconv.Atoi(args[i])
+			if i > 0 || err != nil {
+				return opts, fmt.Errorf("unknown option %q", args[i])
+			}
+			if n == 0 || n < -1 {
+				return opts, fmt.Errorf("count must be positive, or -1 for all")
+			}
+			opts.Count = n
+		}
+	}
+	return opts, nil
+}
@@ -0,0 +1,107 @@
+package server
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cosmez/redisman-go/internal/conn"
+	"github.com/cosmez/redisman-go/internal/redistest"
+)
+
+// slowlogReply is a SLOWLOG GET reply with a Redis 4.0+ entry, a pre-4.0
+// entry without client fields and a slow HGETALL.
+var slowlogReply = redistest.Array(
+	redistest.Array(redistest.Int(12), redistest.Int(1700000000), redistest.Int(1500),
+		redistest.BulkArray("SET", "greeting", "hello world"), redistest.Bulk("10.0.0.5:51234"), redistest.Bulk("worker")),
+	redistest.Array(redistest.Int(11), redistest.Int(1699999990), redistest.Int(90),
+		redistest.BulkArray("get", "a")),
+	redistest.Array(redistest.Int(10), redistest.Int(1699999980), redistest.Int(250000),
+		redistest.BulkArray("HGETALL", "big"), redistest.Bulk("10.0.0.6:40000"), redistest.Bulk("")),
+)
+
+func newSlowlogConn(t *testing.T) *conn.Connection {
+	t.Helper()
+	s := redistest.NewServer(t)
+	s.Handle("SLOWLOG", func(args []string) string {
+		switch strings.ToUpper(args[1]) {
+		case "GET":
+			return slowlogReply
+		case "LEN":
+			return redistest.Int(3)
+		}
+		return redistest.Simple("OK")
+	})
+	c, err := conn.Connect(s.Host, s.Port, "", "")
+	if err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestSlowlog(t *testing.T) {
+	c := newSlowlogConn(t)
+
+	entries, err := Slowlog(c, SlowlogOptions{Sort: SortByTime})
+	if err != nil {
+		t.Fatalf("Slowlog failed: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Got %d entries, want 3", len(entries))
+	}
+	first := entries[0]
+	if first.ID != 12 || first.Duration != 1500*time.Microsecond || first.Client != "10.0.0.5:51234" || first.Name != "worker" {
+		t.Errorf("First entry = %+v", first)
+	}
+	if got := first.CommandLine(); got != `SET greeting "hello world"` {
+		t.Errorf("CommandLine() = %q", got)
+	}
+	if entries[1].Client != "" || entries[1].Command() != "GET" {
+		t.Errorf("Pre-4.0 entry = %+v", entries[1])
+	}
+
+	entries, err = Slowlog(c, SlowlogOptions{Sort: SortByDuration})
+	if err != nil {
+		t.Fatalf("Slowlog failed: %v", err)
+	}
+	if entries[0].ID != 10 || entries[2].ID != 11 {
+		t.Errorf("By duration: ids %d, %d, %d; want 10, 12, 11", entries[0].ID, entries[1].ID, entries[2].ID)
+	}
+
+	entries, _ = Slowlog(c, SlowlogOptions{Command: "get"})
+	if len(entries) != 1 || entries[0].ID != 11 {
+		t.Errorf("CMD get kept %+v", entries)
+	}
+
+	if n, err := SlowlogLen(c); err != nil || n != 3 {
+		t.Errorf("SlowlogLen() = %d, %v", n, err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteSlowlog(&buf, entries); err != nil {
+		t.Fatalf("WriteSlowlog failed: %v", err)
+	}
+	if out := buf.String(); !strings.HasPrefix(out, "ID  TIME") || !strings.Contains(out, "90µs") {
+		t.Errorf("WriteSlowlog output:\n%s", out)
+	}
+}
+
+func TestParseSlowlogArgs(t *testing.T) {
+	opts, err := ParseSlowlogArgs([]string{"20", "sort", "DURATION", "CMD", "hgetall"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.Count != 20 || opts.Sort != SortByDuration || opts.Command != "hgetall" {
+		t.Errorf("opts = %+v", opts)
+	}
+	if opts, _ := ParseSlowlogArgs(nil); opts.Count != DefaultSlowlogCount || opts.Sort != SortByTime {
+		t.Errorf("Defaults = %+v", opts)
+	}
+	for _, args := range [][]string{{"0"}, {"SORT", "id"}, {"CMD"}, {"SORT", "time", "5"}} {
+		if _, err := ParseSlowlogArgs(args); err == nil {
+			t.Errorf("ParseSlowlogArgs(%q) should fail", args)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/cosmez/redisman-go/internal/command"
+	"github.com/cosmez/redisman-go/internal/keyspace"
+	"github.com/rivo/tview"
+)
+
+// handleAnalyze runs ANALYZE in the background, writes the text (or JSON)
+// report to the output view and opens the biggest keys in the report view.
+func (a *App) handleAnalyze(parsed *command.ParsedCommand) {
+	opts, asJSON, err := keyspace.ParseAnalyzeArgs(parsed.Args)
+	if err != nil {
+		fmt.Fprintf(a.ansiWriter, "[red]Usage: ANALYZE [pattern] [TOP n] [SAMPLE fraction] [LIMIT n] [JSON] (%v)[white]\n", err)
+		return
+	}
+
+	progress, stop := a.trackProgress("Analyzing")
+	opts.Progress = func(keys int) { progress(keys, 0) }
+
+	go func() {
+		a.connMu.Lock()
+		report, err := keyspace.Analyze(a.conn, opts)
+		a.connMu.Unlock()
+		stop()
+
+		a.app.QueueUpdateDraw(func() {
+			if err != nil {
+				fmt.Fprintf(a.ansiWriter, "[red]Analyze failed: %v[white]\n", err)
+				a.showStatus("[red]Error")
+				return
+			}
+			var buf bytes.Buffer
+			if asJSON {
+				out, _ := json.MarshalIndent(report, "", "  ")
+				buf.Write(append(out, '\n'))
+			} else {
+				report.WriteText(&buf)
+			}
+			fmt.Fprint(a.ansiWriter, tview.Escape(buf.String()))
+			a.showStatus("[green]Done")
+			a.showAnalyzeReport(report)
+		})
+	}()
+}
+
+// showAnalyzeReport lists the biggest keys of each type in the report
+// view; Enter opens a key.
+func (a *App) showAnalyzeReport(report keyspace.Report) {
+	var rows []reportRow
+	for _, ts := range report.Types {
+		rows = append(rows, reportRow{
+			cells:   []string{ts.Type, keyspace.FormatBytes(ts.Memory), strconv.Itoa(ts.Keys) + " keys"},
+			section: true,
+		})
+		for _, st := range ts.Top {
+			key := st.Key
+			rows = append(rows, reportRow{
+				cells:    []string{"  " + st.Key, keyspace.FormatBytes(st.Memory), strconv.FormatInt(st.Length, 10)},
+				selected: func() { a.showKey(key) },
+			})
+		}
+	}
+	title := fmt.Sprintf("Analyze %s | %d keys, %s", report.Pattern, report.Analyzed, keyspace.FormatBytes(report.Memory))
+	a.showReport(title, []string{"Key", "Memory", "Length"}, rows)
+}
+
+// handlePrefixes runs PREFIXES in the background, writes the text (or JSON)
+// report to the output view and opens the prefix tree in the report view.
+func (a *App) handlePrefixes(parsed *command.ParsedCommand) {
+	opts, asJSON, err := keyspace.ParsePrefixArgs(parsed.Args)
+	if err != nil {
+		fmt.Fprintf(a.ansiWriter, "[red]Usage: PREFIXES [pattern] [DEPTH n] [DELIM delimiter] [JSON] (%v)[white]\n", err)
+		return
+	}
+
+	progress, stop := a.trackProgress("Scanning")
+	opts.Progress = func(keys int) { progress(keys, 0) }
+
+	go func() {
+		a.connMu.Lock()
+		report, err := keyspace.Prefixes(a.conn, opts)
+		a.connMu.Unlock()
+		stop()
+
+		a.app.QueueUpdateDraw(func() {
+			if err != nil {
+				fmt.Fprintf(a.ansiWriter, "[red]Prefixes failed: %v[white]\n", err)
+				a.showStatus("[red]Error")
+				return
+			}
+			var buf bytes.Buffer
+			if asJSON {
+				out, _ := json.MarshalIndent(report, "", "  ")
+				buf.Write(append(out, '\n'))
+			} else {
+				report.WriteText(&buf)
+			}
+			fmt.Fprint(a.ansiWriter, tview.Escape(buf.String()))
+			a.showStatus("[green]Done")
+			a.showPrefixLevel(report, nil)
+		})
+	}()
+}
+
+// showPrefixLevel lists the prefixes below path (the top level if path is
+// empty) in the report view. Enter on a prefix with children drills down
+// into it, Enter on a leaf prefix filters the key list by it, and ".." goes
+// back up a level.
+func (a *App) showPrefixLevel(report keyspace.PrefixReport, path []*keyspace.PrefixStats) {
+	nodes, total := report.Prefixes, &report.Total
+	var rows []reportRow
+	if len(path) > 0 {
+		total = path[len(path)-1]
+		nodes = total.Children
+		up := path[:len(path)-1]
+		rows = append(rows, reportRow{
+			cells:    []string{"..", "", "", ""},
+			selected: func() { a.showPrefixLevel(report, up) },
+		})
+	}
+
+	for _, node := range nodes {
+		var selected func()
+		if len(node.Children) > 0 {
+			down := append(path[:len(path):len(path)], node)
+			selected = func() { a.showPrefixLevel(report, down) }
+		} else if node.Prefix != keyspace.NoPrefix {
+			selected = func() { a.filterByPrefix(node.Prefix) }
+		}
+		rows = append(rows, reportRow{
+			cells: []string{node.Prefix, strconv.Itoa(node.Keys),
+				keyspace.FormatBytes(node.Memory), keyspace.FormatPercent(node.TTLCoverage())},
+			selected: selected,
+		})
+	}
+
+	title := fmt.Sprintf("Prefixes %s | %d keys, %s", total.Prefix, total.Keys, keyspace.FormatBytes(total.Memory))
+	a.showReport(title, []string{"Prefix", "Keys", "Memory", "TTL"}, rows)
+}
+
+// filterByPrefix sets the key filter to prefix and focuses the key list.
+func (a *App) filterByPrefix(prefix string) {
+	a.filterInput.SetText(prefix)
+	a.switchContent("output", "Output")
+	a.focusKeys()
+}
@@ -0,0 +1,146 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/cosmez/redisman-go/internal/backup"
+	"github.com/cosmez/redisman-go/internal/command"
+)
+
+// handleBackup writes the keys matching a pattern to a file in the
+// background, with progress in the status bar.
+func (a *App) handleBackup(parsed *command.ParsedCommand) {
+	if len(parsed.Args) != 2 {
+		fmt.Fprintf(a.ansiWriter, "[red]Usage: BACKUP <pattern> <file>[white]\n")
+		return
+	}
+	opts, path := backup.BackupOptions{Pattern: parsed.Args[0]}, parsed.Args[1]
+
+	progress, stop := a.trackProgress("Backing up")
+	opts.Progress = func(keys int) { progress(keys, 0) }
+
+	go func() {
+		a.connMu.Lock()
+		n, err := backup.BackupFile(a.conn, path, opts)
+		a.connMu.Unlock()
+		stop()
+
+		a.app.QueueUpdateDraw(func() {
+			if err != nil {
+				fmt.Fprintf(a.ansiWriter, "[red]Backup failed: %v[white]\n", err)
+				a.showStatus("[red]Backup failed")
+			} else {
+				fmt.Fprintf(a.ansiWriter, "[green]Backed up %d keys to %s[white]\n", n, path)
+				a.showStatus("[green]Backup done")
+			}
+			a.outputView.ScrollToEnd()
+		})
+	}()
+}
+
+// handleRestoreFile restores a backup file in the background, with progress
+// in the status bar, and reloads the key list when it is done.
+func (a *App) handleRestoreFile(parsed *command.ParsedCommand) {
+	path, opts, err := backup.ParseRestoreArgs(parsed.Args)
+	if err != nil {
+		fmt.Fprintf(a.ansiWriter, "[red]Usage: RESTORE-FILE <file> [REPLACE] [SKIPEXISTING] [TTL keep|absolute|none] (%v)[white]\n", err)
+		return
+	}
+
+	progress, stop := a.trackProgress("Restoring")
+	opts.Progress = func(keys int) { progress(keys, 0) }
+
+	go func() {
+		a.connMu.Lock()
+		hdr, result, err := backup.RestoreFile(a.conn, path, opts)
+		a.connMu.Unlock()
+		stop()
+
+		a.app.QueueUpdateDraw(func() {
+			if err != nil {
+				fmt.Fprintf(a.ansiWriter, "[red]Restore failed: %v[white]\n", err)
+				a.showStatus("[red]Restore failed")
+			} else {
+				fmt.Fprintf(a.ansiWriter, "[cyan]Backup of %q from Redis %s (db %d) taken %s[white]\n",
+					hdr.Pattern, hdr.ServerVersion, hdr.DB, hdr.Created.Local().Format("2006-01-02 15:04:05"))
+				a.printResultCounts("Restored", result)
+				a.showStatus("[green]Restore done")
+			}
+			a.outputView.ScrollToEnd()
+		})
+		if err == nil {
+			a.loadKeys("*")
+		}
+	}()
+}
+
+// handleExportKeys writes the keys matching a pattern to a JSON lines file
+// in the background, with progress in the status bar.
+func (a *App) handleExportKeys(parsed *command.ParsedCommand) {
+	if len(parsed.Args) != 2 {
+		fmt.Fprintf(a.ansiWriter, "[red]Usage: EXPORT-KEYS <pattern> <file>[white]\n")
+		return
+	}
+	opts, path := backup.ExportOptions{Pattern: parsed.Args[0]}, parsed.Args[1]
+
+	progress, stop := a.trackProgress("Exporting")
+	opts.Progress = func(keys int) { progress(keys, 0) }
+
+	go func() {
+		a.connMu.Lock()
+		n, err := backup.ExportKeysFile(a.conn, path, opts)
+		a.connMu.Unlock()
+		stop()
+
+		a.app.QueueUpdateDraw(func() {
+			if err != nil {
+				fmt.Fprintf(a.ansiWriter, "[red]Export failed: %v[white]\n", err)
+				a.showStatus("[red]Export failed")
+			} else {
+				fmt.Fprintf(a.ansiWriter, "[green]Exported %d keys to %s[white]\n", n, path)
+				a.showStatus("[green]Export done")
+			}
+			a.outputView.ScrollToEnd()
+		})
+	}()
+}
+
+// handleImportKeys imports a JSON lines file in the background, with
+// progress in the status bar, and reloads the key list when it is done.
+func (a *App) handleImportKeys(parsed *command.ParsedCommand) {
+	path, opts, err := backup.ParseImportArgs(parsed.Args)
+	if err != nil {
+		fmt.Fprintf(a.ansiWriter, "[red]Usage: IMPORT-KEYS <file> [REPLACE] [SKIPEXISTING] [NOTTL] (%v)[white]\n", err)
+		return
+	}
+
+	progress, stop := a.trackProgress("Importing")
+	opts.Progress = func(keys int) { progress(keys, 0) }
+
+	go func() {
+		a.connMu.Lock()
+		result, err := backup.ImportKeysFile(a.conn, path, opts)
+		a.connMu.Unlock()
+		stop()
+
+		a.app.QueueUpdateDraw(func() {
+			if err != nil {
+				fmt.Fprintf(a.ansiWriter, "[red]Import failed after %d keys: %v[white]\n", result.Restored, err)
+				a.showStatus("[red]Import failed")
+			} else {
+				a.printResultCounts("Imported", result)
+				a.showStatus("[green]Import done")
+			}
+			a.outputView.ScrollToEnd()
+		})
+		a.loadKeys("*")
+	}()
+}
+
+// printResultCounts writes restore or import counts and the first failures.
+func (a *App) printResultCounts(verb string, result backup.RestoreResult) {
+	fmt.Fprintf(a.ansiWriter, "[green]%s %d keys, skipped %d, failed %d[white]\n", verb, result.Restored, result.Skipped, result.Failed)
+	for _, e := range result.Errors {
+		fmt.Fprintf(a.ansiWriter, "[red]  %v[white]\n", e)
+	}
+}
@@ -0,0 +1,192 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cosmez/redisman-go/internal/command"
+	"github.com/cosmez/redisman-go/internal/keyspace"
+	"github.com/rivo/tview"
+)
+
+// bulkOp runs a bulk operation on keys; it is called while holding connMu.
+type bulkOp func(keys []string, opts keyspace.Options) (keyspace.Result, error)
+
+// toggleMark marks or unmarks the key at index for a bulk action and moves
+// to the next key, so several keys can be marked by holding Space.
+func (a *App) toggleMark(index int) {
+	if index < 0 || index >= len(a.keys) {
+		return
+	}
+	name := a.keys[index]
+	if a.marked == nil {
+		a.marked = make(map[string]bool)
+	}
+	if a.marked[name] {
+		delete(a.marked, name)
+		a.keyList.SetItemText(index, name, "")
+	} else {
+		a.marked[name] = true
+		a.keyList.SetItemText(index, "* "+name, "")
+	}
+	if index+1 < a.keyList.GetItemCount() {
+		a.keyList.SetCurrentItem(index + 1)
+	}
+	a.leftPane.SetTitle(a.keysTitle(a.keyList.GetCurrentItem()))
+}
+
+// keysTitle returns the key list title for the item at index.
+func (a *App) keysTitle(index int) string {
+	title := fmt.Sprintf(" Keys [%d/%d%s", index+1, a.keyList.GetItemCount(), a.scanProgress())
+	if len(a.marked) > 0 {
+		title += fmt.Sprintf(", %d marked", len(a.marked))
+	}
+	return title + "] "
+}
+
+// markedOrCurrent returns the marked keys in list order, or the highlighted
+// key if none are marked. In tree mode the highlighted node stands for all
+// the keys under it.
+func (a *App) markedOrCurrent() []string {
+	var keys []string
+	for _, key := range a.keys {
+		if a.marked[key] {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 && a.treeMode {
+		return a.treeKeys()
+	}
+	if len(keys) == 0 {
+		if i := a.keyList.GetCurrentItem(); i >= 0 && i < len(a.keys) {
+			keys = append(keys, a.keys[i])
+		}
+	}
+	return keys
+}
+
+// deleteMarked unlinks the marked keys after confirmation.
+func (a *App) deleteMarked() {
+	keys := a.markedOrCurrent()
+	if len(keys) == 0 {
+		return
+	}
+	a.confirmBulk(fmt.Sprintf("Delete %d keys?", len(keys)), "Deleting", keys, keyspace.Options{},
+		func(keys []string, opts keyspace.Options) (keyspace.Result, error) {
+			return keyspace.Delete(a.conn, keys, opts)
+		})
+}
+
+// expireMarked asks for a TTL and sets it on the marked keys.
+func (a *App) expireMarked() {
+	keys := a.markedOrCurrent()
+	if len(keys) == 0 {
+		return
+	}
+	a.showEditModal(fmt.Sprintf("Set TTL on %d keys", len(keys)), func(form *tview.Form) {
+		form.AddInputField("Seconds", "3600", 20, tview.InputFieldInteger, nil)
+	}, func(form *tview.Form) {
+		seconds, err := strconv.ParseInt(form.GetFormItemByLabel("Seconds").(*tview.InputField).GetText(), 10, 64)
+		a.app.SetRoot(a.layout, true).SetFocus(a.activeContent)
+		if err != nil || seconds <= 0 {
+			a.showError("Seconds must be a positive integer")
+			return
+		}
+		a.runBulk("Expiring", keys, keyspace.Options{}, func(keys []string, opts keyspace.Options) (keyspace.Result, error) {
+			return keyspace.Expire(a.conn, keys, seconds, opts)
+		})
+	})
+}
+
+func (a *App) handleDelPattern(parsed *command.ParsedCommand) {
+	pattern, _, opts, err := keyspace.ParseArgs(parsed.Args, false)
+	if err != nil {
+		fmt.Fprintf(a.ansiWriter, "[red]Usage: DELPATTERN <pattern> [RATE n] (%v)[white]\n", err)
+		return
+	}
+	a.bulkPattern(pattern, "Delete", "Deleting", opts, func(keys []string, opts keyspace.Options) (keyspace.Result, error) {
+		return keyspace.Delete(a.conn, keys, opts)
+	})
+}
+
+func (a *App) handleExpirePattern(parsed *command.ParsedCommand) {
+	pattern, seconds, opts, err := keyspace.ParseArgs(parsed.Args, true)
+	if err != nil {
+		fmt.Fprintf(a.ansiWriter, "[red]Usage: EXPIREPATTERN <pattern> <seconds> [RATE n] (%v)[white]\n", err)
+		return
+	}
+	action := fmt.Sprintf("Set a %ds TTL on", seconds)
+	a.bulkPattern(pattern, action, "Expiring", opts, func(keys []string, opts keyspace.Options) (keyspace.Result, error) {
+		return keyspace.Expire(a.conn, keys, seconds, opts)
+	})
+}
+
+// bulkPattern scans the keys matching pattern in the background, with the
+// count in the status bar, and then confirms op on them.
+func (a *App) bulkPattern(pattern, action, label string, opts keyspace.Options, op bulkOp) {
+	progress, stop := a.trackProgress("Scanning")
+
+	go func() {
+		a.connMu.Lock()
+		keys, err := keyspace.Scan(a.conn, pattern, func(keys int) { progress(keys, 0) })
+		a.connMu.Unlock()
+		stop()
+
+		a.app.QueueUpdateDraw(func() {
+			switch {
+			case err != nil:
+				fmt.Fprintf(a.ansiWriter, "[red]%v[white]\n", err)
+				a.showStatus("[red]Error")
+			case len(keys) == 0:
+				fmt.Fprintf(a.ansiWriter, "[yellow]No keys match %s[white]\n", pattern)
+				a.showStatus("[yellow]No keys match")
+			default:
+				fmt.Fprintf(a.ansiWriter, "[cyan]%d keys match %s[white]\n", len(keys), pattern)
+				a.showStatus(fmt.Sprintf("[cyan]%d keys match", len(keys)))
+				a.confirmBulk(fmt.Sprintf("%s %d keys matching %s?", action, len(keys), pattern), label, keys, opts, op)
+			}
+		})
+	}()
+}
+
+// confirmBulk shows message with a sample of keys and runs op if accepted.
+func (a *App) confirmBulk(message, label string, keys []string, opts keyspace.Options, op bulkOp) {
+	sample := keyspace.Sample(keys)
+	message += "\n\n" + strings.Join(sample, "\n")
+	if len(keys) > len(sample) {
+		message += fmt.Sprintf("\n... and %d more", len(keys)-len(sample))
+	}
+	a.confirmAndExecute(message, func() { a.runBulk(label, keys, opts, op) })
+}
+
+// runBulk runs op in the background with a progress bar, then reports the
+// result and reloads the key list.
+func (a *App) runBulk(label string, keys []string, opts keyspace.Options, op bulkOp) {
+	var stop func()
+	opts.Progress, stop = a.trackProgress(label)
+
+	go func() {
+		a.connMu.Lock()
+		result, err := op(keys, opts)
+		a.connMu.Unlock()
+		stop()
+
+		a.app.QueueUpdateDraw(func() {
+			a.switchContent("output", "Output")
+			if err != nil {
+				fmt.Fprintf(a.ansiWriter, "[red]Failed after %d keys: %v[white]\n", result.Done, err)
+				a.showStatus("[red]Error")
+			} else {
+				fmt.Fprintf(a.ansiWriter, "[green]Done: %d keys, %d already gone, %d failed[white]\n", result.Done, result.Missing, result.Failed)
+				for _, e := range result.Errors {
+					fmt.Fprintf(a.ansiWriter, "[red]  %v[white]\n", e)
+				}
+				a.showStatus("[green]Done")
+			}
+			a.outputView.ScrollToEnd()
+
+			a.reloadKeys()
+		})
+	}()
+}
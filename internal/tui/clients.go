@@ -0,0 +1,112 @@
+package tui
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/cosmez/redisman-go/internal/command"
+	"github.com/cosmez/redisman-go/internal/conn"
+	"github.com/cosmez/redisman-go/internal/server"
+	"github.com/rivo/tview"
+)
+
+// handleClient opens CLIENT LIST in the report view. It returns false for
+// other subcommands and for replies sent to a pipe, filter or file, which
+// run as standard commands.
+func (a *App) handleClient(parsed *command.ParsedCommand) bool {
+	if parsed.Pipe != "" || parsed.Filter != "" || parsed.Redirect != "" ||
+		len(parsed.Args) == 0 || !strings.EqualFold(parsed.Args[0], "LIST") {
+		return false
+	}
+	opts, err := server.ParseClientArgs(parsed.Args[1:])
+	if err != nil {
+		fmt.Fprintf(a.ansiWriter, "[red]Usage: CLIENT LIST [TYPE type] [SORT column] [FILTER text] (%v)[white]\n", err)
+		return true
+	}
+	a.showClients(opts)
+	return true
+}
+
+// showClients fetches CLIENT LIST and shows it in the report view. s cycles
+// the sort column, f filters, r refreshes and K kills the selected client.
+// This connection is marked and cannot be killed from here.
+func (a *App) showClients(opts server.ClientOptions) {
+	a.connMu.Lock()
+	clients, err := server.Clients(a.conn, opts)
+	self, _ := a.conn.ClientID() // 0 before Redis 5.0
+	a.connMu.Unlock()
+	if err != nil {
+		a.showError("CLIENT LIST failed: " + err.Error())
+		return
+	}
+
+	rows := make([]reportRow, len(clients))
+	for i, ci := range clients {
+		cells := server.ClientRow(ci)
+		if ci.ID == self {
+			cells[2] += " (this connection)"
+		}
+		rows[i] = reportRow{cells: cells}
+	}
+	title := fmt.Sprintf("Clients [%d by %s", len(clients), cmp.Or(opts.Sort, "id"))
+	if opts.Filter != "" {
+		title += fmt.Sprintf(", matching %q", opts.Filter)
+	}
+	title += "] s sort, f filter, r refresh, K kill"
+	a.showReport(title, server.ClientHeaders, rows)
+
+	a.reportKeys = map[rune]func(){
+		's': func() {
+			i := slices.Index(server.ClientSorts, cmp.Or(opts.Sort, "id"))
+			opts.Sort = server.ClientSorts[(i+1)%len(server.ClientSorts)]
+			a.showClients(opts)
+		},
+		'f': func() { a.filterClients(opts) },
+		'r': func() { a.showClients(opts) },
+		'K': func() {
+			row, _ := a.reportTable.GetSelection()
+			if row < 1 || row > len(clients) {
+				return
+			}
+			if ci := clients[row-1]; ci.ID == self {
+				a.showStatus("[yellow]That is this connection")
+			} else {
+				a.killClient(ci, opts)
+			}
+		},
+	}
+}
+
+// filterClients asks for a text to match against client addresses, names,
+// users and last commands, empty for all.
+func (a *App) filterClients(opts server.ClientOptions) {
+	a.showEditModal("Filter clients", func(form *tview.Form) {
+		form.AddInputField("Contains", opts.Filter, 30, nil, nil)
+	}, func(form *tview.Form) {
+		a.app.SetRoot(a.layout, true).SetFocus(a.activeContent)
+		opts.Filter = strings.TrimSpace(form.GetFormItemByLabel("Contains").(*tview.InputField).GetText())
+		a.showClients(opts)
+	})
+}
+
+// killClient closes a client connection after a confirmation and reloads
+// the list.
+func (a *App) killClient(ci conn.ClientInfo, opts server.ClientOptions) {
+	who := ci.Addr
+	if ci.Name != "" {
+		who += " (" + ci.Name + ")"
+	}
+	a.confirmAndExecute(fmt.Sprintf("Kill client %d, %s?\n\nLast command: %s, idle %s", ci.ID, who, ci.Cmd, ci.Idle), func() {
+		a.connMu.Lock()
+		err := a.conn.ClientKill(ci.ID)
+		a.connMu.Unlock()
+		if err != nil {
+			a.showError("CLIENT KILL failed: " + err.Error())
+			return
+		}
+		a.showStatus("[green]Client killed")
+		a.showClients(opts)
+	})
+}
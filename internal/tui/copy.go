@@ -0,0 +1,126 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cosmez/redisman-go/internal/backup"
+	"github.com/cosmez/redisman-go/internal/command"
+	"github.com/cosmez/redisman-go/internal/conn"
+	"github.com/rivo/tview"
+)
+
+var (
+	copyConflicts = []string{backup.ConflictFail, backup.ConflictSkip, backup.ConflictReplace}
+	copyModes     = []string{backup.CopyAuto, backup.CopyDump, backup.CopyLogical}
+)
+
+// handleCopyKeys runs COPY-KEYS with arguments, or opens the copy dialog
+// without them.
+func (a *App) handleCopyKeys(parsed *command.ParsedCommand) {
+	if len(parsed.Args) == 0 {
+		a.showCopyDialog()
+		return
+	}
+
+	profile, opts, err := backup.ParseCopyArgs(parsed.Args)
+	if err != nil {
+		fmt.Fprintf(a.ansiWriter, "[red]Usage: COPY-KEYS <profile> <pattern> [REPLACE|SKIPEXISTING] [DRYRUN] [RATE n] [MODE auto|dump|logical] (%v)[white]\n", err)
+		return
+	}
+	a.startCopy(profile, opts)
+}
+
+// showCopyDialog asks for the destination and copy options, defaulting the
+// pattern to the key filter.
+func (a *App) showCopyDialog() {
+	pattern := a.filterInput.GetText()
+	if pattern == "" {
+		pattern = "*"
+	}
+	names := a.profiles.Names()
+	to := ""
+	if len(names) > 0 {
+		to = names[0]
+	}
+
+	a.showEditModal("Copy Keys", func(form *tview.Form) {
+		form.AddInputField("To", to, 40, nil, nil)
+		form.GetFormItemByLabel("To").(*tview.InputField).SetAutocompleteFunc(func(text string) []string {
+			var matches []string
+			for _, name := range names {
+				if strings.HasPrefix(name, text) {
+					matches = append(matches, name)
+				}
+			}
+			return matches
+		})
+		form.AddInputField("Pattern", pattern, 40, nil, nil)
+		form.AddDropDown("Existing keys", copyConflicts, 0, nil)
+		form.AddDropDown("Mode", copyModes, 0, nil)
+		form.AddInputField("Rate (keys/s)", "0", 10, tview.InputFieldInteger, nil)
+		form.AddCheckbox("Dry run", true, nil)
+	}, func(form *tview.Form) {
+		profile := form.GetFormItemByLabel("To").(*tview.InputField).GetText()
+		_, conflict := form.GetFormItemByLabel("Existing keys").(*tview.DropDown).GetCurrentOption()
+		_, mode := form.GetFormItemByLabel("Mode").(*tview.DropDown).GetCurrentOption()
+		rate, _ := strconv.Atoi(form.GetFormItemByLabel("Rate (keys/s)").(*tview.InputField).GetText())
+		a.app.SetRoot(a.layout, true).SetFocus(a.activeContent)
+
+		a.switchContent("output", "Output")
+		a.startCopy(profile, backup.CopyOptions{
+			Pattern:  form.GetFormItemByLabel("Pattern").(*tview.InputField).GetText(),
+			Conflict: conflict,
+			Mode:     mode,
+			Rate:     rate,
+			DryRun:   form.GetFormItemByLabel("Dry run").(*tview.Checkbox).IsChecked(),
+		})
+	})
+}
+
+// startCopy connects to the destination and copies in the background,
+// showing a progress bar in the status label. The current connection is
+// held for the whole copy.
+func (a *App) startCopy(profile string, opts backup.CopyOptions) {
+	p, err := a.profiles.Lookup(profile)
+	if err != nil {
+		fmt.Fprintf(a.ansiWriter, "[red]%v[white]\n", err)
+		return
+	}
+	host, port := p.Addr()
+	dst, err := conn.Connect(host, port, p.Username, p.Password)
+	if err != nil {
+		fmt.Fprintf(a.ansiWriter, "[red]Connection to %s failed: %v[white]\n", profile, err)
+		return
+	}
+	fmt.Fprintf(a.ansiWriter, "[yellow]Copying %s to %s...[white]\n", opts.Pattern, profile)
+
+	var stop func()
+	opts.Progress, stop = a.trackProgress("Copying")
+
+	go func() {
+		defer dst.Close()
+		a.connMu.Lock()
+		result, err := backup.Copy(a.conn, dst, opts)
+		a.connMu.Unlock()
+		stop()
+
+		a.app.QueueUpdateDraw(func() {
+			switch {
+			case err != nil:
+				fmt.Fprintf(a.ansiWriter, "[red]Copy failed after %d keys: %v[white]\n", result.Restored, err)
+				a.showStatus("[red]Copy failed")
+			case opts.DryRun:
+				fmt.Fprintf(a.ansiWriter, "[cyan]Dry run of a %s copy to %s: %d keys match[white]\n", result.Mode, profile, result.Total)
+				a.printResultCounts("Would copy", result.RestoreResult)
+				a.showStatus("[green]Dry run done")
+			default:
+				fmt.Fprintf(a.ansiWriter, "[cyan]Copied keys to %s using %s[white]\n", profile, result.Mode)
+				a.printResultCounts("Copied", result.RestoreResult)
+				a.showStatus("[green]Copy done")
+			}
+			a.outputView.ScrollToEnd()
+		})
+	}()
+}
@@ -0,0 +1,319 @@
+package tui
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cosmez/redisman-go/internal/command"
+	"github.com/cosmez/redisman-go/internal/keyspace"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Dashboard refresh: INFO is fetched every interval and the last samples are
+// drawn as sparklines.
+const (
+	defaultDashboardInterval = 2 * time.Second
+	dashboardSamples         = 30
+)
+
+// sparkBars are the sparkline levels, lowest first.
+var sparkBars = []rune("▁▂▃▄▅▆▇█")
+
+// dashSample is one INFO reading.
+type dashSample struct {
+	info         map[string]string
+	ops          float64 // instantaneous_ops_per_sec
+	memory       float64 // used_memory in bytes
+	clients      float64 // connected_clients
+	hits, misses float64 // keyspace_hits and keyspace_misses since startup
+	hitRatio     float64 // hits per lookup since the previous sample, -1 if there were none
+}
+
+// dashboard keeps the last dashboardSamples INFO readings, oldest first.
+type dashboard struct {
+	interval time.Duration
+	samples  []dashSample
+}
+
+// add records an INFO reading, dropping the oldest beyond dashboardSamples.
+func (d *dashboard) add(info map[string]string) {
+	number := func(name string) float64 {
+		f, _ := strconv.ParseFloat(info[name], 64)
+		return f
+	}
+	s := dashSample{
+		info:     info,
+		ops:      number("instantaneous_ops_per_sec"),
+		memory:   number("used_memory"),
+		clients:  number("connected_clients"),
+		hits:     number("keyspace_hits"),
+		misses:   number("keyspace_misses"),
+		hitRatio: -1,
+	}
+	if n := len(d.samples); n > 0 {
+		prev := d.samples[n-1]
+		hits, lookups := s.hits-prev.hits, s.hits+s.misses-prev.hits-prev.misses
+		if lookups > 0 && hits >= 0 {
+			s.hitRatio = hits / lookups
+		}
+	}
+	d.samples = append(d.samples, s)
+	if len(d.samples) > dashboardSamples {
+		d.samples = slices.Delete(d.samples, 0, len(d.samples)-dashboardSamples)
+	}
+}
+
+// series returns one field of every sample, oldest first.
+func (d *dashboard) series(field func(dashSample) float64) []float64 {
+	values := make([]float64, len(d.samples))
+	for i, s := range d.samples {
+		values[i] = field(s)
+	}
+	return values
+}
+
+// sparkline draws values as bars scaled between their minimum and maximum.
+// Negative values are gaps.
+func sparkline(values []float64) string {
+	lo, hi := -1.0, -1.0
+	for _, v := range values {
+		if v < 0 {
+			continue
+		}
+		if lo < 0 || v < lo {
+			lo = v
+		}
+		hi = max(hi, v)
+	}
+	var b strings.Builder
+	for _, v := range values {
+		switch {
+		case v < 0:
+			b.WriteRune(' ')
+		case hi == lo:
+			b.WriteRune(sparkBars[0])
+		default:
+			b.WriteRune(sparkBars[int((v-lo)/(hi-lo)*float64(len(sparkBars)-1)+0.5)])
+		}
+	}
+	return b.String()
+}
+
+// rows returns the dashboard as label, value and sparkline rows, built from
+// the latest sample. Keyspace rows follow, one per database.
+func (d *dashboard) rows() [][3]string {
+	if len(d.samples) == 0 {
+		return nil
+	}
+	last := d.samples[len(d.samples)-1]
+	info := last.info
+	integer := func(name string) int64 {
+		n, _ := strconv.ParseInt(info[name], 10, 64)
+		return n
+	}
+
+	server := fmt.Sprintf("redis %s (%s)", info["redis_version"], cmp.Or(info["redis_mode"], "standalone"))
+	if up := integer("uptime_in_seconds"); up > 0 {
+		server += ", up " + (time.Duration(up) * time.Second).String()
+	}
+
+	memory := keyspace.FormatBytes(integer("used_memory")) + " used"
+	if peak := integer("used_memory_peak"); peak > 0 {
+		memory += ", " + keyspace.FormatBytes(peak) + " peak"
+	}
+	if frag := info["mem_fragmentation_ratio"]; frag != "" {
+		memory += ", fragmentation " + frag
+	}
+
+	hitRatio := "no lookups"
+	if last.hitRatio >= 0 {
+		hitRatio = keyspace.FormatPercent(last.hitRatio) + " recently"
+	}
+	if lookups := last.hits + last.misses; lookups > 0 {
+		hitRatio += ", " + keyspace.FormatPercent(last.hits/lookups) + " since start"
+	}
+
+	rows := [][3]string{
+		{"Server", server, ""},
+		{"Ops/sec", info["instantaneous_ops_per_sec"], sparkline(d.series(func(s dashSample) float64 { return s.ops }))},
+		{"Memory", memory, sparkline(d.series(func(s dashSample) float64 { return s.memory }))},
+		{"Hit ratio", hitRatio, sparkline(d.series(func(s dashSample) float64 { return s.hitRatio }))},
+		{"Clients", fmt.Sprintf("%d connected, %d blocked", integer("connected_clients"), integer("blocked_clients")),
+			sparkline(d.series(func(s dashSample) float64 { return s.clients }))},
+		{"Replication", replicationStatus(info), ""},
+		{"Persistence", persistenceStatus(info), ""},
+	}
+	for _, db := range keyspaceDBs(info) {
+		rows = append(rows, [3]string{db, keyspaceStatus(info[db]), ""})
+	}
+	return rows
+}
+
+// replicationStatus describes the replication role, e.g. "master, 2
+// replicas" or "replica of 10.0.0.1:6379, link up".
+func replicationStatus(info map[string]string) string {
+	switch info["role"] {
+	case "master":
+		return fmt.Sprintf("master, %s replicas", cmp.Or(info["connected_slaves"], "0"))
+	case "slave":
+		return fmt.Sprintf("replica of %s:%s, link %s", info["master_host"], info["master_port"], info["master_link_status"])
+	}
+	return cmp.Or(info["role"], "unknown")
+}
+
+// persistenceStatus describes RDB and AOF state, e.g. "RDB ok, saved 5m0s
+// ago, 12 changes since; AOF off".
+func persistenceStatus(info map[string]string) string {
+	rdb := "RDB " + cmp.Or(info["rdb_last_bgsave_status"], "unknown")
+	if info["rdb_bgsave_in_progress"] == "1" {
+		rdb += ", saving"
+	}
+	if saved, err := strconv.ParseInt(info["rdb_last_save_time"], 10, 64); err == nil && saved > 0 {
+		rdb += ", saved " + time.Since(time.Unix(saved, 0)).Round(time.Second).String() + " ago"
+	}
+	if changes := info["rdb_changes_since_last_save"]; changes != "" {
+		rdb += ", " + changes + " changes since"
+	}
+
+	aof := "AOF off"
+	if info["aof_enabled"] == "1" {
+		aof = "AOF on, last write " + cmp.Or(info["aof_last_write_status"], "unknown")
+		if info["aof_rewrite_in_progress"] == "1" {
+			aof += ", rewriting"
+		}
+	}
+	return rdb + "; " + aof
+}
+
+// keyspaceDBs returns the "dbN" fields of info in database order.
+func keyspaceDBs(info map[string]string) []string {
+	var dbs []string
+	for name := range info {
+		if n, ok := strings.CutPrefix(name, "db"); ok {
+			if _, err := strconv.Atoi(n); err == nil {
+				dbs = append(dbs, name)
+			}
+		}
+	}
+	slices.SortFunc(dbs, func(a, b string) int {
+		x, _ := strconv.Atoi(a[2:])
+		y, _ := strconv.Atoi(b[2:])
+		return cmp.Compare(x, y)
+	})
+	return dbs
+}
+
+// keyspaceStatus renders a keyspace line such as
+// "keys=10,expires=2,avg_ttl=60000" as "10 keys, 2 with expiry, avg TTL 1m0s".
+func keyspaceStatus(value string) string {
+	fields := map[string]string{}
+	for part := range strings.SplitSeq(value, ",") {
+		if k, v, ok := strings.Cut(part, "="); ok {
+			fields[k] = v
+		}
+	}
+	text := fmt.Sprintf("%s keys, %s with expiry", fields["keys"], cmp.Or(fields["expires"], "0"))
+	if ms, err := strconv.ParseInt(fields["avg_ttl"], 10, 64); err == nil && ms > 0 {
+		text += ", avg TTL " + (time.Duration(ms) * time.Millisecond).Round(time.Second).String()
+	}
+	return text
+}
+
+// setupDashboard creates the dashboard page. Escape returns to the output
+// page, which also stops the refresh.
+func (a *App) setupDashboard() {
+	a.dashTable = tview.NewTable().SetBorders(false)
+	a.contentPages.AddPage("dashboard", a.dashTable, true, false)
+
+	a.dashTable.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			a.switchContent("output", "Output")
+			a.focusKeys()
+			return nil
+		}
+		return event
+	})
+}
+
+// handleDashboard opens the dashboard, refreshing every interval seconds
+// (default 2).
+func (a *App) handleDashboard(parsed *command.ParsedCommand) {
+	interval := defaultDashboardInterval
+	if len(parsed.Args) > 0 {
+		seconds, err := strconv.ParseFloat(parsed.Args[0], 64)
+		if err != nil || seconds < 0.5 || len(parsed.Args) > 1 {
+			fmt.Fprintf(a.ansiWriter, "[red]Usage: DASHBOARD [seconds] (interval must be at least 0.5)[white]\n")
+			return
+		}
+		interval = time.Duration(seconds * float64(time.Second))
+	}
+	a.showDashboard(interval)
+}
+
+// showDashboard switches to the dashboard and refreshes it from a background
+// goroutine until another page is shown (switchContent bumps dashGen).
+func (a *App) showDashboard(interval time.Duration) {
+	a.dash = &dashboard{interval: interval}
+	a.switchContent("dashboard", a.dashboardTitle())
+	a.dashTable.Clear()
+	gen := a.dashGen.Add(1) // stops the refresh of a dashboard already open
+	a.focusContent()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			a.sampleDashboard(gen)
+			<-ticker.C
+			if a.dashGen.Load() != gen {
+				return
+			}
+		}
+	}()
+}
+
+// sampleDashboard fetches INFO and queues a redraw of the dashboard with it.
+// It runs on the refresh goroutine, so a job holding the connection delays
+// the sample instead of freezing the TUI.
+func (a *App) sampleDashboard(gen uint64) {
+	a.connMu.Lock()
+	info, err := a.conn.Info()
+	a.connMu.Unlock()
+	a.app.QueueUpdateDraw(func() { a.addDashboardSample(gen, info, err) })
+}
+
+// addDashboardSample adds info to the dashboard and redraws it, unless the
+// dashboard was closed since gen was issued.
+func (a *App) addDashboardSample(gen uint64, info map[string]string, err error) {
+	if a.dashGen.Load() != gen {
+		return
+	}
+	if err != nil {
+		a.showStatus("[red]INFO failed")
+		return
+	}
+	a.dash.add(info)
+	a.renderDashboard()
+}
+
+// renderDashboard fills the dashboard table from the samples so far.
+func (a *App) renderDashboard() {
+	a.dashTable.Clear()
+	for r, row := range a.dash.rows() {
+		a.dashTable.SetCell(r, 0, tview.NewTableCell(row[0]).SetTextColor(tcell.ColorYellow))
+		a.dashTable.SetCell(r, 1, tview.NewTableCell(tview.Escape(row[1])))
+		a.dashTable.SetCell(r, 2, tview.NewTableCell(row[2]).SetTextColor(tcell.ColorAqua).SetExpansion(1))
+	}
+	a.contentPages.SetTitle(contentTitle(a.dashboardTitle()))
+}
+
+// dashboardTitle returns the dashboard's title with its refresh interval and
+// sample count.
+func (a *App) dashboardTitle() string {
+	return fmt.Sprintf("Dashboard [every %s, %d/%d samples]", a.dash.interval, len(a.dash.samples), dashboardSamples)
+}
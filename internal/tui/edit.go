@@ -2,8 +2,11 @@ package tui
 
 import (
 	"fmt"
+	"strconv"
+	"sync/atomic"
 	"time"
 
+	"github.com/cosmez/redisman-go/internal/output"
 	"github.com/cosmez/redisman-go/internal/resp"
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
@@ -21,6 +24,7 @@ func (a *App) updateActionBar() {
 		label.SetBackgroundColor(tcell.ColorDarkSlateGray)
 		label.SetTextColor(tcell.ColorGray)
 		a.actionBar.AddItem(label, 0, 1, false)
+		a.addActionButton("^N", " New key", func() { a.newKey() })
 		a.actionBar.AddItem(a.statusLabel, 0, 1, false)
 		return
 	}
@@ -37,6 +41,7 @@ func (a *App) updateActionBar() {
 		a.addActionButton("E", "dit", func() { a.dispatchEdit() })
 		a.addActionButton("A", "dd", func() { a.addListItem() })
 		a.addActionButton("D", "elete", func() { a.dispatchDelete() })
+		a.addActionButton("J", "ump", func() { a.jumpInCollection() })
 		a.addActionButton("R", "efresh", func() { a.refreshCurrentKey() })
 		a.addActionButton("X", " Del Key", func() { a.deleteKey() })
 	case "set":
@@ -54,15 +59,26 @@ func (a *App) updateActionBar() {
 		a.addActionButton("E", "dit", func() { a.dispatchEdit() })
 		a.addActionButton("A", "dd", func() { a.addZSetMember() })
 		a.addActionButton("D", "elete", func() { a.dispatchDelete() })
+		a.addActionButton("J", "ump", func() { a.jumpInCollection() })
 		a.addActionButton("R", "efresh", func() { a.refreshCurrentKey() })
 		a.addActionButton("X", " Del Key", func() { a.deleteKey() })
 	case "stream":
 		a.addActionButton("A", "dd", func() { a.addStreamEntry() })
 		a.addActionButton("D", "elete", func() { a.dispatchDelete() })
+		a.addActionButton("J", "ump", func() { a.jumpInCollection() })
 		a.addActionButton("R", "efresh", func() { a.refreshCurrentKey() })
 		a.addActionButton("X", " Del Key", func() { a.deleteKey() })
 	}
 
+	// Expiry actions apply to every type.
+	a.addActionButton("T", "TL", func() { a.setExpiry() })
+	a.addActionButton("P", "ersist", func() { a.clearExpiry() })
+
+	// Rename, copy and move apply to every type as well.
+	a.addActionButton("N", " Rename", func() { a.renameKey() })
+	a.addActionButton("C", "opy", func() { a.duplicateKey() })
+	a.addActionButton("M", "ove", func() { a.moveKey() })
+
 	// Status label fills remaining space on the right.
 	a.actionBar.AddItem(a.statusLabel, 0, 1, false)
 }
@@ -223,6 +239,34 @@ func (a *App) showStatus(msg string) {
 	}()
 }
 
+// trackProgress shows a progress bar labeled label in the status label until
+// stop is called; a total of 0 shows the count only. The returned progress func is safe to call while holding
+// connMu: it only records the counts, which are polled from another
+// goroutine, because queuing UI updates while the UI waits on connMu could
+// fill tview's update queue and deadlock.
+func (a *App) trackProgress(label string) (progress func(done, total int), stop func()) {
+	var done, total atomic.Int64
+	quit := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(200 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-quit:
+				return
+			case <-ticker.C:
+				// Without a known total, show the count alone.
+				bar := fmt.Sprintf("%d keys", done.Load())
+				if t := total.Load(); t > 0 {
+					bar = output.ProgressBar(int(done.Load()), int(t), 20)
+				}
+				a.app.QueueUpdateDraw(func() { a.statusLabel.SetText("[yellow]" + label + " " + bar + " ") })
+			}
+		}
+	}()
+	return func(d, t int) { done.Store(int64(d)); total.Store(int64(t)) }, func() { close(quit) }
+}
+
 // confirmAndExecute shows a confirmation modal and runs onConfirm if accepted.
 func (a *App) confirmAndExecute(message string, onConfirm func()) {
 	modal := tview.NewModal().
@@ -242,13 +286,7 @@ func (a *App) refreshCurrentKey() {
 	if a.currentKey == "" {
 		return
 	}
-	// Find the key index in the list and re-select it.
-	for i, k := range a.keys {
-		if k == a.currentKey {
-			a.selectKey(i, a.currentKey, "", 0)
-			return
-		}
-	}
+	a.showKey(a.currentKey)
 }
 
 // getSelectedRow returns the data-row index (0-based, excluding header),
@@ -268,11 +306,49 @@ func (a *App) getSelectedRow() (dataIndex int, cells []string, ok bool) {
 	return row - 1, cells, true
 }
 
+// getSelectedRawRow is like getSelectedRow but returns the stored values
+// (before codec decoding). Use it wherever a command must address an existing
+// element by value, e.g. LREM, SREM or ZREM.
+func (a *App) getSelectedRawRow() (dataIndex int, cells []string, ok bool) {
+	row, _ := a.tableView.GetSelection()
+	if row < 1 {
+		return 0, nil, false
+	}
+	colCount := a.tableView.GetColumnCount()
+	for c := 0; c < colCount; c++ {
+		cell := a.tableView.GetCell(row, c)
+		if cell == nil {
+			continue
+		}
+		if raw, isString := cell.GetReference().(string); isString {
+			cells = append(cells, raw)
+		} else {
+			cells = append(cells, cell.Text)
+		}
+	}
+	return row - 1, cells, true
+}
+
+// encodeValue applies the current key's codec to a value typed by the user.
+// On failure it reports the error and returns ok=false.
+func (a *App) encodeValue(value string) (encoded string, ok bool) {
+	if a.currentCodec == nil {
+		return value, true
+	}
+	b, err := a.currentCodec.Serialize([]byte(value))
+	if err != nil {
+		a.showError("Codec error: " + err.Error())
+		return "", false
+	}
+	return string(b), true
+}
+
 // --- Keyboard shortcut wiring ---
 
 // setupEditHandlers wraps InputCapture on tableView and stringView to add
-// edit shortcut keys (e/a/d/r/x). These don't conflict with normal Table
-// or read-only TextView navigation because those widgets don't handle rune keys.
+// edit shortcut keys (e/a/d/r/x, J to jump in a collection, T and P to set
+// and clear the expiry, N/C/M to rename, copy and move the key). These don't conflict with normal Table or read-only
+// TextView navigation because those widgets don't handle these rune keys.
 func (a *App) setupEditHandlers() {
 	// Wrap tableView's existing InputCapture (which handles Escape).
 	origTable := a.tableView.GetInputCapture()
@@ -294,6 +370,24 @@ func (a *App) setupEditHandlers() {
 			case 'x':
 				a.deleteKey()
 				return nil
+			case 'J':
+				a.jumpInCollection()
+				return nil
+			case 'T':
+				a.setExpiry()
+				return nil
+			case 'P':
+				a.clearExpiry()
+				return nil
+			case 'N':
+				a.renameKey()
+				return nil
+			case 'C':
+				a.duplicateKey()
+				return nil
+			case 'M':
+				a.moveKey()
+				return nil
 			}
 		}
 		if origTable != nil {
@@ -308,27 +402,55 @@ func (a *App) setupEditHandlers() {
 		a.dispatchEdit()
 	})
 
-	// Wrap stringView's existing InputCapture (which handles Escape).
-	origString := a.stringView.GetInputCapture()
-	a.stringView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		if event.Key() == tcell.KeyRune {
-			switch event.Rune() {
-			case 'e':
-				a.editString()
-				return nil
-			case 'r':
-				a.refreshCurrentKey()
-				return nil
-			case 'x':
-				a.deleteKey()
-				return nil
-			}
+	// Moving near the last loaded row fetches the next page (see paging.go).
+	a.tableView.SetSelectionChangedFunc(func(row, column int) {
+		if a.page != nil && row >= a.page.loaded-rowPrefetch {
+			a.loadRows()
 		}
-		if origString != nil {
-			return origString(event)
-		}
-		return event
 	})
+
+	// Wrap the string views' existing InputCapture (which handles Escape).
+	// The text and JSON tree views share shortcuts; 't' toggles between them.
+	for _, view := range []*tview.Box{a.stringView.Box, a.jsonTree.Box} {
+		orig := view.GetInputCapture()
+		view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			if event.Key() == tcell.KeyRune {
+				switch event.Rune() {
+				case 'e':
+					a.editString()
+					return nil
+				case 'r':
+					a.refreshCurrentKey()
+					return nil
+				case 'x':
+					a.deleteKey()
+					return nil
+				case 't':
+					a.toggleJSONView()
+					return nil
+				case 'T':
+					a.setExpiry()
+					return nil
+				case 'P':
+					a.clearExpiry()
+					return nil
+				case 'N':
+					a.renameKey()
+					return nil
+				case 'C':
+					a.duplicateKey()
+					return nil
+				case 'M':
+					a.moveKey()
+					return nil
+				}
+			}
+			if orig != nil {
+				return orig(event)
+			}
+			return event
+		})
+	}
 }
 
 // --- Dispatch ---
@@ -405,12 +527,7 @@ func (a *App) deleteKey() {
 			a.switchContent("output", "Output")
 			fmt.Fprintf(a.ansiWriter, "[green]Deleted key %q[white]\n", key)
 			a.showStatus("[green]Deleted")
-			// Refresh key list.
-			pattern := a.filterInput.GetText() + "*"
-			if a.filterInput.GetText() == "" {
-				pattern = "*"
-			}
-			go a.loadKeys(pattern)
+			a.reloadKeys()
 		},
 	)
 }
@@ -439,19 +556,39 @@ func (a *App) editString() {
 	current := val.StringValue()
 	key := a.currentKey
 
+	// Edit the decoded form; refuse rather than risk re-encoding garbage.
+	if a.currentCodec != nil {
+		decoded, decErr := a.currentCodec.Deserialize([]byte(current))
+		if decErr != nil {
+			a.showError(fmt.Sprintf("Cannot decode %q for editing: %v (use VIEW %s #:raw)", key, decErr, key))
+			return
+		}
+		current = string(decoded)
+	}
+
 	a.showTextAreaModal("Edit String: "+key, current, func(newValue string) {
 		a.app.SetRoot(a.layout, true).SetFocus(a.activeContent)
-		a.sendEditCommand("SET", key, newValue)
+		encoded, ok := a.encodeValue(newValue)
+		if !ok {
+			return
+		}
+		a.sendEditCommand("SET", key, encoded)
 	})
 }
 
 // --- List ---
 
 func (a *App) editListItem() {
-	idx, cells, ok := a.getSelectedRow()
+	_, cells, ok := a.getSelectedRow()
 	if !ok || len(cells) < 2 {
 		return
 	}
+	// The # column is 1-based and stays right after a jump (see paging.go).
+	n, err := strconv.Atoi(cells[0])
+	if err != nil {
+		return
+	}
+	idx := n - 1
 	key := a.currentKey
 	currentValue := cells[1]
 
@@ -460,7 +597,11 @@ func (a *App) editListItem() {
 	}, func(form *tview.Form) {
 		newValue := form.GetFormItemByLabel("Value").(*tview.InputField).GetText()
 		a.app.SetRoot(a.layout, true).SetFocus(a.activeContent)
-		a.sendEditCommand("LSET", key, fmt.Sprintf("%d", idx), newValue)
+		encoded, ok := a.encodeValue(newValue)
+		if !ok {
+			return
+		}
+		a.sendEditCommand("LSET", key, fmt.Sprintf("%d", idx), encoded)
 	})
 }
 
@@ -481,12 +622,16 @@ func (a *App) addListItem() {
 		if position == "Head (LPUSH)" {
 			cmd = "LPUSH"
 		}
-		a.sendEditCommand(cmd, key, value)
+		encoded, ok := a.encodeValue(value)
+		if !ok {
+			return
+		}
+		a.sendEditCommand(cmd, key, encoded)
 	})
 }
 
 func (a *App) deleteListItem() {
-	_, cells, ok := a.getSelectedRow()
+	_, cells, ok := a.getSelectedRawRow()
 	if !ok || len(cells) < 2 {
 		return
 	}
@@ -514,12 +659,16 @@ func (a *App) addSetMember() {
 	}, func(form *tview.Form) {
 		member := form.GetFormItemByLabel("Member").(*tview.InputField).GetText()
 		a.app.SetRoot(a.layout, true).SetFocus(a.activeContent)
-		a.sendEditCommand("SADD", key, member)
+		encoded, ok := a.encodeValue(member)
+		if !ok {
+			return
+		}
+		a.sendEditCommand("SADD", key, encoded)
 	})
 }
 
 func (a *App) deleteSetMember() {
-	_, cells, ok := a.getSelectedRow()
+	_, cells, ok := a.getSelectedRawRow()
 	if !ok || len(cells) < 1 {
 		return
 	}
@@ -550,7 +699,11 @@ func (a *App) editHashField() {
 	}, func(form *tview.Form) {
 		newValue := form.GetFormItemByLabel("Value").(*tview.InputField).GetText()
 		a.app.SetRoot(a.layout, true).SetFocus(a.activeContent)
-		a.sendEditCommand("HSET", key, field, newValue)
+		encoded, ok := a.encodeValue(newValue)
+		if !ok {
+			return
+		}
+		a.sendEditCommand("HSET", key, field, encoded)
 	})
 }
 
@@ -567,7 +720,11 @@ func (a *App) addHashField() {
 		field := form.GetFormItemByLabel("Field").(*tview.InputField).GetText()
 		value := form.GetFormItemByLabel("Value").(*tview.InputField).GetText()
 		a.app.SetRoot(a.layout, true).SetFocus(a.activeContent)
-		a.sendEditCommand("HSET", key, field, value)
+		encoded, ok := a.encodeValue(value)
+		if !ok {
+			return
+		}
+		a.sendEditCommand("HSET", key, field, encoded)
 	})
 }
 
@@ -590,15 +747,16 @@ func (a *App) deleteHashField() {
 // --- Sorted Set ---
 
 func (a *App) editZSetScore() {
-	_, cells, ok := a.getSelectedRow()
+	_, cells, ok := a.getSelectedRawRow()
 	if !ok || len(cells) < 2 {
 		return
 	}
 	key := a.currentKey
-	member := cells[0]
+	member := cells[0] // stored member, addressed as-is by ZADD
 	currentScore := cells[1]
+	_, shown, _ := a.getSelectedRow()
 
-	a.showEditModal("Edit Score: "+member, func(form *tview.Form) {
+	a.showEditModal("Edit Score: "+shown[0], func(form *tview.Form) {
 		form.AddInputField("Score", currentScore, 20, nil, nil)
 	}, func(form *tview.Form) {
 		newScore := form.GetFormItemByLabel("Score").(*tview.InputField).GetText()
@@ -620,12 +778,16 @@ func (a *App) addZSetMember() {
 		member := form.GetFormItemByLabel("Member").(*tview.InputField).GetText()
 		score := form.GetFormItemByLabel("Score").(*tview.InputField).GetText()
 		a.app.SetRoot(a.layout, true).SetFocus(a.activeContent)
-		a.sendEditCommand("ZADD", key, score, member)
+		encoded, ok := a.encodeValue(member)
+		if !ok {
+			return
+		}
+		a.sendEditCommand("ZADD", key, score, encoded)
 	})
 }
 
 func (a *App) deleteZSetMember() {
-	_, cells, ok := a.getSelectedRow()
+	_, cells, ok := a.getSelectedRawRow()
 	if !ok || len(cells) < 2 {
 		return
 	}
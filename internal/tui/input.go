@@ -3,12 +3,16 @@ package tui
 import (
 	"bytes"
 	"fmt"
+	"io"
+	"iter"
 	"strings"
 	"time"
 
 	"github.com/cosmez/redisman-go/internal/command"
 	"github.com/cosmez/redisman-go/internal/conn"
+	"github.com/cosmez/redisman-go/internal/keyspace"
 	"github.com/cosmez/redisman-go/internal/output"
+	"github.com/cosmez/redisman-go/internal/resp"
 	"github.com/cosmez/redisman-go/internal/serializer"
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
@@ -72,8 +76,54 @@ func (a *App) executeCommand(input string) {
 		a.handleView(parsed)
 	case "EXPORT":
 		a.handleExport(parsed)
-	case "SUBSCRIBE":
-		fmt.Fprintf(a.ansiWriter, "[yellow]SUBSCRIBE is not supported in TUI mode. Use REPL mode instead.[white]\n")
+	case "SUBSCRIBE", "PSUBSCRIBE", "SSUBSCRIBE":
+		a.handleSubscribe(parsed)
+	case "PUBSUB":
+		if !a.handlePubSub(parsed) {
+			a.handleStandardCommand(parsed)
+		}
+	case "BACKUP":
+		a.handleBackup(parsed)
+	case "RESTORE-FILE":
+		a.handleRestoreFile(parsed)
+	case "EXPORT-KEYS":
+		a.handleExportKeys(parsed)
+	case "IMPORT-KEYS":
+		a.handleImportKeys(parsed)
+	case "DELPATTERN":
+		a.handleDelPattern(parsed)
+	case "EXPIREPATTERN":
+		a.handleExpirePattern(parsed)
+	case "ANALYZE":
+		a.handleAnalyze(parsed)
+	case "PREFIXES":
+		a.handlePrefixes(parsed)
+	case "DASHBOARD":
+		a.handleDashboard(parsed)
+	case "MONITOR":
+		a.handleMonitor(parsed)
+	case "WATCHKEYS":
+		a.handleWatchKeys(parsed)
+	case "SLOWLOG":
+		if !a.handleSlowlog(parsed) {
+			a.handleStandardCommand(parsed)
+		}
+	case "CLIENT":
+		if !a.handleClient(parsed) {
+			a.handleStandardCommand(parsed)
+		}
+	case "COPY-KEYS":
+		a.handleCopyKeys(parsed)
+	case "RENAME-KEY":
+		a.handleKeyAction(parsed, keyspace.OpRename)
+	case "DUPLICATE-KEY":
+		a.handleKeyAction(parsed, keyspace.OpDuplicate)
+	case "MOVE-KEY":
+		a.handleKeyAction(parsed, keyspace.OpMove)
+	case "FORMAT":
+		a.handleFormat(parsed)
+	case "PAGING":
+		fmt.Fprintf(a.ansiWriter, "[yellow]PAGING only applies to REPL mode; the output view scrolls instead.[white]\n")
 	default:
 		a.handleStandardCommand(parsed)
 	}
@@ -148,6 +198,19 @@ func (a *App) handleSafeKeys(parsed *command.ParsedCommand) {
 	}
 
 	a.connMu.Lock()
+	defer a.connMu.Unlock()
+
+	if parsed.Redirect != "" {
+		a.redirectTo(parsed, nil, a.conn.SafeKeys(pattern), "keys", nil)
+		return
+	}
+	if parsed.Pipe != "" {
+		a.pipeToOutput(func(w io.Writer) error {
+			return output.PipeRedisValues(w, a.conn.SafeKeys(pattern), parsed.Pipe, "keys")
+		})
+		return
+	}
+
 	i := 0
 	opts := output.PrintOpts{Color: true, Newline: true}
 	for val := range a.conn.SafeKeys(pattern) {
@@ -155,7 +218,56 @@ func (a *App) handleSafeKeys(parsed *command.ParsedCommand) {
 		fmt.Fprintf(a.ansiWriter, "%d) ", i)
 		output.PrintRedisValue(a.ansiWriter, val, opts)
 	}
-	a.connMu.Unlock()
+}
+
+func (a *App) handleFormat(parsed *command.ParsedCommand) {
+	if len(parsed.Args) > 0 {
+		f := strings.ToLower(parsed.Args[0])
+		if !output.ValidFormat(f) {
+			fmt.Fprintf(a.ansiWriter, "[red]Usage: FORMAT [text|json|csv][white]\n")
+			return
+		}
+		a.format = f
+	}
+	fmt.Fprintf(a.ansiWriter, "[cyan]Format: %s[white]\n", a.format)
+}
+
+// redirectTo writes a result to the file of a `> file` or `>> file` redirect
+// in the current format. A filter, if present, writes its results instead.
+func (a *App) redirectTo(parsed *command.ParsedCommand, v resp.RedisValue, values iter.Seq[resp.RedisValue], typeHint string, ser serializer.Serializer) {
+	f, err := output.OpenRedirect(parsed.Redirect, parsed.Append)
+	if err != nil {
+		fmt.Fprintf(a.ansiWriter, "[red]Redirect failed: %v[white]\n", err)
+		return
+	}
+	defer f.Close()
+
+	switch {
+	case parsed.Filter != "" && v != nil:
+		err = output.FilterRedisValue(f, v, parsed.Filter, output.PrintOpts{Serializer: ser})
+	case parsed.Filter != "":
+		err = output.FilterRedisValues(f, values, parsed.Filter, output.PrintOpts{Serializer: ser})
+	default:
+		err = output.WriteFormatted(f, v, values, a.format, typeHint, ser)
+	}
+	if err != nil {
+		fmt.Fprintf(a.ansiWriter, "[red]Redirect failed: %v[white]\n", err)
+		return
+	}
+	fmt.Fprintf(a.ansiWriter, "[green]Written to %s[white]\n", parsed.Redirect)
+}
+
+// pipeToOutput runs a pipe, capturing its output into a buffer, then writes
+// it to the output view followed by any error or non-zero exit status.
+func (a *App) pipeToOutput(run func(w io.Writer) error) {
+	var buf bytes.Buffer
+	err := run(&buf)
+	fmt.Fprint(a.ansiWriter, buf.String())
+	if code, ok := output.ExitCode(err); ok {
+		fmt.Fprintf(a.ansiWriter, "[yellow]Exit status %d[white]\n", code)
+	} else if err != nil {
+		fmt.Fprintf(a.ansiWriter, "[red]Pipe error: %v[white]\n", err)
+	}
 }
 
 func (a *App) handleView(parsed *command.ParsedCommand) {
@@ -169,23 +281,47 @@ func (a *App) handleView(parsed *command.ParsedCommand) {
 	a.connMu.Lock()
 	typeName, single, collection, err := a.conn.GetKeyValue(key)
 
-	// Consume collection iterators while holding the lock.
-	var headers []string
-	var rows [][]string
-	if err == nil && collection != nil {
-		switch typeName {
-		case "list":
-			headers, rows = consumeList(collection)
-		case "set":
-			headers, rows = consumeSet(collection)
-		case "hash":
-			headers, rows = consumeHash(collection)
-		case "zset":
-			headers, rows = consumeSortedSet(collection)
-		case "stream":
-			headers, rows = consumeStream(collection)
+	// A redirect streams the value straight into the file.
+	if err == nil && parsed.Redirect != "" && typeName != "none" {
+		codec, resolveErr := a.rules.Resolve(parsed.Modifier, key)
+		if resolveErr != nil {
+			a.connMu.Unlock()
+			fmt.Fprintf(a.ansiWriter, "[red]Serializer error: %v[white]\n", resolveErr)
+			return
+		}
+		a.redirectTo(parsed, single, collection, typeName, codec)
+		a.connMu.Unlock()
+		return
+	}
+
+	// A filter on a collection reads it while the connection is held, then
+	// prints its results to the output view.
+	if err == nil && parsed.Filter != "" && collection != nil {
+		defer a.connMu.Unlock()
+		codec, resolveErr := a.rules.Resolve(parsed.Modifier, key)
+		if resolveErr != nil {
+			fmt.Fprintf(a.ansiWriter, "[red]Serializer error: %v[white]\n", resolveErr)
+			return
+		}
+		opts := output.PrintOpts{Color: true, Serializer: codec}
+		if filterErr := output.FilterRedisValues(a.ansiWriter, collection, parsed.Filter, opts); filterErr != nil {
+			fmt.Fprintf(a.ansiWriter, "[red]Filter error: %v[white]\n", filterErr)
 		}
+		return
+	}
+
+	// A pipe streams the value straight to the shell command.
+	if err == nil && parsed.Pipe != "" && typeName != "none" {
+		a.pipeToOutput(func(w io.Writer) error {
+			if collection != nil {
+				return output.PipeRedisValues(w, collection, parsed.Pipe, typeName)
+			}
+			return output.PipeRedisValue(w, single, parsed.Pipe)
+		})
+		a.connMu.Unlock()
+		return
 	}
+
 	a.connMu.Unlock()
 
 	if err != nil {
@@ -198,30 +334,33 @@ func (a *App) handleView(parsed *command.ParsedCommand) {
 		return
 	}
 
+	codec, err := a.rules.Resolve(parsed.Modifier, key)
+	if err != nil {
+		fmt.Fprintf(a.ansiWriter, "[red]Serializer error: %v[white]\n", err)
+		return
+	}
+
 	title := fmt.Sprintf("%s (%s)", key, typeName)
 
-	// String type: show in dedicated string view.
-	if single != nil {
-		a.stringView.Clear()
-		stringWriter := tview.ANSIWriter(a.stringView)
-		opts := output.PrintOpts{Color: true, Newline: true}
-		if parsed.Modifier != "" {
-			if ser, serErr := serializer.Get(parsed.Modifier); serErr == nil {
-				opts.Serializer = ser
-			}
+	// A filter prints its results to the output view instead of opening a view.
+	if single != nil && parsed.Filter != "" {
+		opts := output.PrintOpts{Color: true, Serializer: codec}
+		if filterErr := output.FilterRedisValue(a.ansiWriter, single, parsed.Filter, opts); filterErr != nil {
+			fmt.Fprintf(a.ansiWriter, "[red]Filter error: %v[white]\n", filterErr)
 		}
-		output.PrintRedisValue(stringWriter, single, opts)
-		a.stringView.ScrollToBeginning()
-		a.switchContent("string-view", title)
 		return
 	}
 
-	// Collection types: show in shared table view.
-	if rows != nil {
-		a.populateTable(headers, rows)
-		a.switchContent("table-view", title)
+	// String type: show in dedicated string view.
+	if single != nil {
+		a.showStringValue(title, single, codec)
 		return
 	}
+
+	// Collection types: show in shared table view, a page at a time.
+	if collection != nil {
+		a.showCollection(key, typeName, title, codec)
+	}
 }
 
 func (a *App) handleExport(parsed *command.ParsedCommand) {
@@ -327,21 +466,24 @@ func (a *App) sendAndDisplay(parsed *command.ParsedCommand) {
 		return
 	}
 
-	opts := output.PrintOpts{Color: true, Newline: true}
-	if parsed.Modifier != "" {
-		if ser, serErr := serializer.Get(parsed.Modifier); serErr == nil {
-			opts.Serializer = ser
-		}
+	opts := output.PrintOpts{Color: true, Newline: true, PrettyJSON: true}
+	ser, serErr := a.rules.Resolve(parsed.Modifier, command.ValueKey(parsed))
+	if serErr != nil {
+		fmt.Fprintf(a.ansiWriter, "[red]Serializer error: %v[white]\n", serErr)
+		return
 	}
+	opts.Serializer = ser
 
-	if parsed.Pipe != "" {
-		// Pipe output to a shell command, capture into buffer, then write to outputView.
-		var buf bytes.Buffer
-		if pipeErr := output.PipeRedisValue(&buf, val, parsed.Pipe); pipeErr != nil {
-			fmt.Fprintf(a.ansiWriter, "[red]Pipe error: %v[white]\n", pipeErr)
-		} else {
-			fmt.Fprint(a.ansiWriter, buf.String())
+	if parsed.Redirect != "" {
+		a.redirectTo(parsed, val, nil, "", ser)
+	} else if parsed.Filter != "" {
+		if filterErr := output.FilterRedisValue(a.ansiWriter, val, parsed.Filter, opts); filterErr != nil {
+			fmt.Fprintf(a.ansiWriter, "[red]Filter error: %v[white]\n", filterErr)
 		}
+	} else if parsed.Pipe != "" {
+		a.pipeToOutput(func(w io.Writer) error {
+			return output.PipeRedisValue(w, val, parsed.Pipe)
+		})
 	} else {
 		output.PrintRedisValue(a.ansiWriter, val, opts)
 	}
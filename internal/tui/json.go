@@ -0,0 +1,107 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// identRe matches object keys that can be written in dot notation in a path.
+var identRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// buildJSONTree parses doc into a collapsible tree. Key order is preserved
+// (the decoder is walked token by token rather than decoding into a map).
+// Each node's reference is its JSON path, e.g. `$.items[2].id`.
+//
+// C#: Like binding a TreeView to a JToken hierarchy.
+func buildJSONTree(doc string) (*tview.TreeNode, error) {
+	dec := json.NewDecoder(strings.NewReader(doc))
+	dec.UseNumber()
+
+	root, err := decodeJSONNode(dec, "", "$")
+	if err != nil {
+		return nil, err
+	}
+	root.SetExpanded(true)
+	for _, child := range root.GetChildren() {
+		child.SetExpanded(false)
+	}
+	return root, nil
+}
+
+// decodeJSONNode reads one JSON value from dec and returns it as a tree node.
+// label is the key or index the value sits under ("" for the root).
+func decodeJSONNode(dec *json.Decoder, label, path string) (*tview.TreeNode, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := ""
+	if label != "" {
+		prefix = label + ": "
+	}
+
+	switch v := tok.(type) {
+	case json.Delim:
+		node := tview.NewTreeNode("").SetReference(path).SetSelectable(true)
+		n := 0
+		for dec.More() {
+			var childLabel, childPath string
+			if v == '{' {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				key, _ := keyTok.(string)
+				childLabel = key
+				childPath = jsonPathJoin(path, key)
+			} else {
+				childLabel = strconv.Itoa(n)
+				childPath = fmt.Sprintf("%s[%d]", path, n)
+			}
+			child, err := decodeJSONNode(dec, childLabel, childPath)
+			if err != nil {
+				return nil, err
+			}
+			node.AddChild(child)
+			n++
+		}
+		if _, err := dec.Token(); err != nil { // closing delimiter
+			return nil, err
+		}
+
+		summary := fmt.Sprintf("[%d]", n)
+		if v == '{' {
+			summary = fmt.Sprintf("{%d}", n)
+		}
+		node.SetText(prefix + summary).SetColor(tcell.ColorYellow)
+		return node, nil
+	case string:
+		return jsonLeaf(prefix+strconv.Quote(v), path, tcell.ColorLightSkyBlue), nil
+	case json.Number:
+		return jsonLeaf(prefix+v.String(), path, tcell.ColorLightGreen), nil
+	case bool:
+		return jsonLeaf(prefix+strconv.FormatBool(v), path, tcell.ColorViolet), nil
+	default:
+		return jsonLeaf(prefix+"null", path, tcell.ColorViolet), nil
+	}
+}
+
+func jsonLeaf(text, path string, c tcell.Color) *tview.TreeNode {
+	return tview.NewTreeNode(text).SetReference(path).SetColor(c)
+}
+
+// jsonPathJoin appends an object key to a path, using dot notation for
+// identifier-like keys and bracket notation otherwise.
+func jsonPathJoin(path, key string) string {
+	if identRe.MatchString(key) {
+		return path + "." + key
+	}
+	return path + "[" + strconv.Quote(key) + "]"
+}
@@ -0,0 +1,144 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cosmez/redisman-go/internal/command"
+	"github.com/cosmez/redisman-go/internal/keyspace"
+	"github.com/rivo/tview"
+)
+
+// keyActionUsage is the usage line of each single-key built-in.
+var keyActionUsage = map[string]string{
+	keyspace.OpRename:    "RENAME-KEY <key> <newname> [REPLACE]",
+	keyspace.OpDuplicate: "DUPLICATE-KEY <key> <dest> [DB n] [REPLACE]",
+	keyspace.OpMove:      "MOVE-KEY <key> <db>",
+}
+
+// handleKeyAction runs RENAME-KEY, DUPLICATE-KEY or MOVE-KEY typed in the
+// command input, after the same preview and confirmation as the forms.
+func (a *App) handleKeyAction(parsed *command.ParsedCommand, op string) {
+	action, err := keyspace.ParseKeyActionArgs(op, parsed.Args)
+	if err != nil {
+		fmt.Fprintf(a.ansiWriter, "[red]Usage: %s (%v)[white]\n", keyActionUsage[op], err)
+		return
+	}
+	a.confirmKeyAction(action)
+}
+
+// renameKey asks for a new name for the current key.
+func (a *App) renameKey() {
+	if a.currentKey == "" {
+		return
+	}
+	key := a.currentKey
+	a.showEditModal("Rename: "+key, func(form *tview.Form) {
+		form.AddInputField("New name", key, 50, nil, nil)
+		form.AddCheckbox("Overwrite existing", false, nil)
+	}, func(form *tview.Form) {
+		a.app.SetRoot(a.layout, true).SetFocus(a.activeContent)
+		a.confirmKeyAction(keyspace.KeyAction{
+			Op:      keyspace.OpRename,
+			Key:     key,
+			Dest:    form.GetFormItemByLabel("New name").(*tview.InputField).GetText(),
+			DB:      -1,
+			Replace: form.GetFormItemByLabel("Overwrite existing").(*tview.Checkbox).IsChecked(),
+		})
+	})
+}
+
+// duplicateKey asks for a destination name and database for a copy of the
+// current key.
+func (a *App) duplicateKey() {
+	if a.currentKey == "" {
+		return
+	}
+	key := a.currentKey
+	a.showEditModal("Copy: "+key, func(form *tview.Form) {
+		form.AddInputField("Destination", key+":copy", 50, nil, nil)
+		form.AddInputField("Database", "", 10, tview.InputFieldInteger, nil)
+		form.AddCheckbox("Overwrite existing", false, nil)
+	}, func(form *tview.Form) {
+		a.app.SetRoot(a.layout, true).SetFocus(a.activeContent)
+		db := -1 // the current database
+		if text := form.GetFormItemByLabel("Database").(*tview.InputField).GetText(); text != "" {
+			n, err := strconv.Atoi(text)
+			if err != nil || n < 0 {
+				a.showError("Database must be a non-negative integer")
+				return
+			}
+			db = n
+		}
+		a.confirmKeyAction(keyspace.KeyAction{
+			Op:      keyspace.OpDuplicate,
+			Key:     key,
+			Dest:    form.GetFormItemByLabel("Destination").(*tview.InputField).GetText(),
+			DB:      db,
+			Replace: form.GetFormItemByLabel("Overwrite existing").(*tview.Checkbox).IsChecked(),
+		})
+	})
+}
+
+// moveKey asks for the database to move the current key to.
+func (a *App) moveKey() {
+	if a.currentKey == "" {
+		return
+	}
+	key := a.currentKey
+	a.showEditModal("Move: "+key, func(form *tview.Form) {
+		form.AddInputField("Database", "1", 10, tview.InputFieldInteger, nil)
+	}, func(form *tview.Form) {
+		a.app.SetRoot(a.layout, true).SetFocus(a.activeContent)
+		db, err := strconv.Atoi(form.GetFormItemByLabel("Database").(*tview.InputField).GetText())
+		if err != nil || db < 0 {
+			a.showError("Database must be a non-negative integer")
+			return
+		}
+		a.confirmKeyAction(keyspace.KeyAction{Op: keyspace.OpMove, Key: key, DB: db})
+	})
+}
+
+// confirmKeyAction previews action in a confirmation modal and runs it if
+// accepted.
+func (a *App) confirmKeyAction(action keyspace.KeyAction) {
+	if action.Op != keyspace.OpMove && (action.Dest == "" || (action.Dest == action.Key && action.DB < 0)) {
+		a.showError("Destination must be a different key name")
+		return
+	}
+	a.connMu.Lock()
+	lines, err := action.Preview(a.conn)
+	a.connMu.Unlock()
+	if err != nil {
+		a.showError(err.Error())
+		return
+	}
+	message := lines[0] + "?\n\n" + strings.Join(lines[1:], "\n")
+	a.confirmAndExecute(message, func() { a.runKeyAction(action) })
+}
+
+// runKeyAction performs action, reloads the key list and follows a renamed
+// key; a key moved away from the shown database is closed.
+func (a *App) runKeyAction(action keyspace.KeyAction) {
+	a.connMu.Lock()
+	err := action.Run(a.conn)
+	a.connMu.Unlock()
+	if err != nil {
+		a.showError(err.Error())
+		return
+	}
+
+	a.showStatus("[green]Done")
+	a.reloadKeys()
+
+	if a.currentKey != action.Key {
+		return
+	}
+	switch action.Op {
+	case keyspace.OpRename:
+		a.showKey(action.Dest)
+	case keyspace.OpMove:
+		a.switchContent("output", "Output")
+	}
+}
@@ -3,10 +3,6 @@ package tui
 import (
 	"fmt"
 	"time"
-
-	"github.com/cosmez/redisman-go/internal/output"
-	"github.com/cosmez/redisman-go/internal/resp"
-	"github.com/rivo/tview"
 )
 
 // filterDebounce is the delay before a filter keystroke triggers a key reload.
@@ -16,6 +12,12 @@ const filterDebounce = 300 * time.Millisecond
 func (a *App) setupKeyHandlers() {
 	// Key selection — fires when the user presses Enter on a key in the list.
 	a.keyList.SetSelectedFunc(a.selectKey)
+	a.keyList.SetChangedFunc(func(index int, mainText string, secondaryText string, shortcut rune) {
+		a.leftPane.SetTitle(a.keysTitle(index))
+		if index >= len(a.keys)-keyPrefetch {
+			a.fetchKeys()
+		}
+	})
 
 	// Filter input — debounced reload on each keystroke.
 	//
@@ -37,50 +39,181 @@ func (a *App) setupKeyHandlers() {
 	})
 }
 
-// loadKeysSync populates the key list synchronously (used before app.Run()).
-// No mutex needed — called from the main goroutine before the event loop starts.
+// Key pages: the key pane is filled one SCAN page at a time as it scrolls.
+const (
+	keyPageSize  = 100 // keys fetched per page, also the SCAN COUNT hint
+	keyPrefetch  = 20  // fetch the next page when the selection is this close to the end
+	keyTreeBatch = 1000
+)
+
+// keyScan is the state of the incremental SCAN behind the key pane. It is
+// only touched on the UI thread (or before the event loop starts).
+type keyScan struct {
+	gen      uint64 // the scanGen value this scan was started with
+	pattern  string
+	cursor   string // next SCAN cursor
+	done     bool   // the cursor came back to "0" or the scan failed
+	fetching bool   // a page fetch is in flight
+	dbSize   int64  // DBSIZE when the scan started, -1 if unknown
+}
+
+// loadKeysSync starts a key scan and fetches the first page synchronously
+// (used before app.Run()). No mutex needed — called from the main goroutine
+// before the event loop starts.
 func (a *App) loadKeysSync(pattern string) {
+	gen := a.scanGen.Add(1)
+	size, err := a.conn.DBSize()
+	if err != nil {
+		size = -1
+	}
+	a.resetKeys(gen, pattern, size)
+	a.scan.fetching = true
+	keys, next, err := a.scanKeys(gen, pattern, "0", keyPageSize)
+	a.addKeys(gen, keys, next, err, true)
+}
+
+// loadKeys restarts the key scan with a new pattern from a background
+// goroutine. Any scan still in progress is cancelled: its pages are dropped
+// when they arrive.
+func (a *App) loadKeys(pattern string) {
+	gen := a.scanGen.Add(1)
+	a.connMu.Lock()
+	size, err := a.conn.DBSize()
+	a.connMu.Unlock()
+	if err != nil {
+		size = -1
+	}
+	a.app.QueueUpdateDraw(func() {
+		if a.scanGen.Load() != gen {
+			return
+		}
+		a.resetKeys(gen, pattern, size)
+		a.fetchKeys()
+	})
+}
+
+// reloadKeys restarts the key scan with the current filter, e.g. after keys
+// were deleted or renamed.
+func (a *App) reloadKeys() {
+	go a.loadKeys(a.filterInput.GetText() + "*")
+}
+
+// resetKeys clears the key pane for a new scan.
+func (a *App) resetKeys(gen uint64, pattern string, dbSize int64) {
 	a.keyList.Clear()
 	a.keys = a.keys[:0]
+	a.marked = nil
+	a.scan = keyScan{gen: gen, pattern: pattern, cursor: "0", dbSize: dbSize}
+	a.leftPane.SetTitle(a.keysTitle(0))
+}
+
+// fetchKeys fetches the next page of keys in the background unless the scan
+// is complete or a fetch is already running. In tree mode it keeps going
+// until the scan is complete, since group counts need every key.
+func (a *App) fetchKeys() {
+	if a.scan.done || a.scan.fetching || a.conn == nil {
+		return
+	}
+	a.scan.fetching = true
+	gen, pattern, cursor, all := a.scan.gen, a.scan.pattern, a.scan.cursor, a.treeMode
+
+	go func() {
+		want := keyPageSize
+		if all {
+			want = keyTreeBatch
+		}
+		for {
+			keys, next, err := a.scanKeys(gen, pattern, cursor, want)
+			if a.scanGen.Load() != gen {
+				return
+			}
+			final := !all || err != nil || next == "0"
+			a.app.QueueUpdateDraw(func() { a.addKeys(gen, keys, next, err, final) })
+			if final {
+				return
+			}
+			cursor = next
+		}
+	}()
+}
 
-	for val := range a.conn.SafeKeys(pattern) {
-		if _, ok := val.(resp.RedisError); ok {
+// scanKeys runs SCAN from cursor until at least want keys have arrived or
+// the scan is complete. connMu is held for one page at a time so other
+// operations can run in between; it stops early if the scan is superseded.
+func (a *App) scanKeys(gen uint64, pattern, cursor string, want int) (keys []string, next string, err error) {
+	next = cursor
+	for a.scanGen.Load() == gen {
+		var page []string
+		a.connMu.Lock()
+		next, page, err = a.conn.ScanPage(next, pattern, keyPageSize)
+		a.connMu.Unlock()
+		if err != nil {
+			return keys, next, err
+		}
+		keys = append(keys, page...)
+		if next == "0" || len(keys) >= want {
 			break
 		}
-		name := val.StringValue()
-		a.keys = append(a.keys, name)
-		a.keyList.AddItem(name, "", 0, nil)
 	}
-	a.leftPane.SetTitle(fmt.Sprintf(" Keys [%d] ", len(a.keys)))
+	return keys, next, nil
 }
 
-// loadKeys populates the key list from a background goroutine.
-// Uses QueueUpdateDraw for thread-safe UI updates and connMu for connection safety.
-//
-// C# equivalent: Task.Run(() => { foreach (var key in SafeKeys(pattern)) Dispatcher.Invoke(() => list.Add(key)); })
-func (a *App) loadKeys(pattern string) {
-	a.connMu.Lock()
-	defer a.connMu.Unlock()
+// addKeys appends a fetched page to the key pane. final ends the fetch
+// started by fetchKeys; another one is started if the selection is already
+// near the end of the list (or the tree needs the rest of the keys).
+func (a *App) addKeys(gen uint64, keys []string, next string, err error, final bool) {
+	if gen != a.scan.gen {
+		return
+	}
+	a.scan.cursor = next
+	if err != nil {
+		a.scan.done = true
+		fmt.Fprintf(a.ansiWriter, "[red]%v[white]\n", err)
+	} else if next == "0" {
+		a.scan.done = true
+	}
+	if final {
+		a.scan.fetching = false
+	}
 
-	// Clear the list on the UI thread first.
-	a.app.QueueUpdateDraw(func() {
-		a.keyList.Clear()
-		a.keys = a.keys[:0]
-	})
+	a.keys = append(a.keys, keys...)
+	for _, name := range keys {
+		a.keyList.AddItem(name, "", 0, nil)
+		if name == a.keyToSelect {
+			a.keyList.SetCurrentItem(a.keyList.GetItemCount() - 1)
+			a.keyToSelect = ""
+		}
+	}
+	if a.scan.done {
+		a.keyToSelect = "" // not in this view of the keys
+	}
 
-	for val := range a.conn.SafeKeys(pattern) {
-		if _, ok := val.(resp.RedisError); ok {
-			break
+	if a.treeMode {
+		if a.scan.done {
+			a.refreshKeyTree()
+		} else {
+			a.leftPane.SetTitle(fmt.Sprintf(" Keys [%d%s] ", len(a.keys), a.scanProgress()))
 		}
-		name := val.StringValue()
-		a.app.QueueUpdateDraw(func() {
-			a.keys = append(a.keys, name)
-			a.keyList.AddItem(name, "", 0, nil)
-			a.leftPane.SetTitle(fmt.Sprintf(" Keys [%d] ", len(a.keys)))
-		})
+	} else {
+		a.leftPane.SetTitle(a.keysTitle(a.keyList.GetCurrentItem()))
+	}
+	if final && (a.treeMode || a.keyList.GetCurrentItem() >= len(a.keys)-keyPrefetch) {
+		a.fetchKeys()
 	}
 }
 
+// scanProgress returns " of ~DBSIZE" while the key scan is incomplete, or
+// "" once every key has been loaded.
+func (a *App) scanProgress() string {
+	if a.scan.done {
+		return ""
+	}
+	if a.scan.dbSize < 0 {
+		return " of ?"
+	}
+	return fmt.Sprintf(" of ~%d", a.scan.dbSize)
+}
+
 // selectKey is called when the user selects a key in the list.
 // It fetches the key's value and displays it in a type-specific view.
 //
@@ -90,29 +223,24 @@ func (a *App) selectKey(index int, name string, secondaryText string, shortcut r
 	if index < 0 || index >= len(a.keys) {
 		return
 	}
+	a.showKey(a.keys[index])
+}
 
+// showKey fetches a key's value and displays it in a type-specific view.
+// It is also used to open keys listed in reports.
+func (a *App) showKey(name string) {
+	// Collections are not read here: showCollection pages through them.
 	a.connMu.Lock()
-	typeName, single, collection, err := a.conn.GetKeyValue(name)
-
-	// Consume collection iterators while holding the lock (they lazily call Send/Receive).
-	var headers []string
-	var rows [][]string
-	if err == nil && collection != nil {
-		switch typeName {
-		case "list":
-			headers, rows = consumeList(collection)
-		case "set":
-			headers, rows = consumeSet(collection)
-		case "hash":
-			headers, rows = consumeHash(collection)
-		case "zset":
-			headers, rows = consumeSortedSet(collection)
-		case "stream":
-			headers, rows = consumeStream(collection)
-		}
-	}
+	typeName, single, _, err := a.conn.GetKeyValue(name)
 	a.connMu.Unlock()
 
+	// Codec rules apply to every value of the key; a bad rule is reported but
+	// the key is still shown as stored.
+	codec, codecErr := a.rules.Resolve("", name)
+	if codecErr != nil {
+		a.showStatus("[red]Codec error")
+	}
+
 	if err != nil {
 		a.switchContent("output", "Output")
 		a.outputView.Clear()
@@ -125,24 +253,19 @@ func (a *App) selectKey(index int, name string, secondaryText string, shortcut r
 	// Track which key/type is displayed (for CRUD operations).
 	a.currentKey = name
 	a.currentType = typeName
+	a.currentCodec = codec
+	a.showMeta()
 
 	// String type: show in dedicated string view.
 	if single != nil {
-		a.stringView.Clear()
-		stringWriter := tview.ANSIWriter(a.stringView)
-		opts := output.PrintOpts{Color: true, Newline: true}
-		output.PrintRedisValue(stringWriter, single, opts)
-		a.stringView.ScrollToBeginning()
-		a.switchContent("string-view", title)
+		a.showStringValue(title, single, codec)
 		a.focusContent()
 		return
 	}
 
 	// Collection types: show in shared table view.
-	if rows != nil {
-		a.populateTable(headers, rows)
-		a.switchContent("table-view", title)
+	if _, ok := lengthCommands[typeName]; ok {
+		a.showCollection(name, typeName, title, codec)
 		a.focusContent()
-		return
 	}
 }
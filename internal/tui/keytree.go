@@ -0,0 +1,228 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// defaultKeyDelim separates key name segments in the key tree.
+const defaultKeyDelim = ":"
+
+// keyGroup is one child of a key tree node: either a group of keys sharing
+// a prefix or a single key.
+type keyGroup struct {
+	name  string // full prefix including the delimiter, or the key name
+	label string // text shown in the tree, relative to the parent
+	count int    // keys under the group, 1 for a key
+	leaf  bool
+}
+
+// groupKeys returns the children of the tree node for prefix: groups for the
+// next name segment of each key under prefix, then keys with no further
+// delimiter, each sorted by name.
+func groupKeys(keys []string, prefix, delim string) []keyGroup {
+	groups := map[string]int{}
+	var leaves []keyGroup
+	for _, key := range keys {
+		rest, ok := strings.CutPrefix(key, prefix)
+		if !ok {
+			continue
+		}
+		if i := strings.Index(rest, delim); i >= 0 {
+			groups[rest[:i+len(delim)]]++
+		} else {
+			leaves = append(leaves, keyGroup{name: key, label: rest, count: 1, leaf: true})
+		}
+	}
+
+	children := make([]keyGroup, 0, len(groups)+len(leaves))
+	for label, n := range groups {
+		children = append(children, keyGroup{name: prefix + label, label: label, count: n})
+	}
+	slices.SortFunc(children, func(a, b keyGroup) int { return strings.Compare(a.name, b.name) })
+	slices.SortFunc(leaves, func(a, b keyGroup) int { return strings.Compare(a.name, b.name) })
+	return append(children, leaves...)
+}
+
+// setupKeyTree creates the key tree, an alternative to keyList that groups
+// keys by delimiter. Children are built when a group is first expanded.
+// Enter opens a key or expands/collapses a group; Ctrl+G switches back to
+// the flat list.
+func (a *App) setupKeyTree() {
+	a.keyTree = tview.NewTreeView().
+		SetGraphics(true)
+
+	a.keyTree.SetSelectedFunc(func(node *tview.TreeNode) {
+		g, ok := node.GetReference().(keyGroup)
+		if !ok {
+			return
+		}
+		if g.leaf {
+			a.showKey(g.name)
+			return
+		}
+		if node.IsExpanded() {
+			node.Collapse()
+		} else {
+			a.expandGroup(node)
+		}
+	})
+	a.keyTree.SetChangedFunc(func(node *tview.TreeNode) {
+		if g, ok := node.GetReference().(keyGroup); ok {
+			a.leftPane.SetTitle(fmt.Sprintf(" Keys [%s %d] ", g.name, g.count))
+		}
+	})
+
+	a.keyTree.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyCtrlG:
+			a.toggleKeyView()
+			return nil
+		case tcell.KeyCtrlN:
+			a.newKey()
+			return nil
+		case tcell.KeyDelete:
+			a.deleteMarked()
+			return nil
+		case tcell.KeyCtrlT:
+			a.expireMarked()
+			return nil
+		case tcell.KeyRight:
+			if node := a.keyTree.GetCurrentNode(); node != nil {
+				a.expandGroup(node)
+			}
+			return nil
+		case tcell.KeyLeft:
+			if node := a.keyTree.GetCurrentNode(); node != nil {
+				node.Collapse()
+			}
+			return nil
+		case tcell.KeyRune:
+			a.typeIntoFilter(event.Rune())
+			return nil
+		}
+		return event
+	})
+}
+
+// expandGroup fills a group node with its children on first use and expands
+// it. Key nodes are left alone.
+func (a *App) expandGroup(node *tview.TreeNode) {
+	g, ok := node.GetReference().(keyGroup)
+	if !ok || g.leaf {
+		return
+	}
+	if len(node.GetChildren()) == 0 {
+		prefix := g.name
+		if node == a.keyTree.GetRoot() {
+			prefix = ""
+		}
+		for _, child := range groupKeys(a.keys, prefix, a.keyDelim) {
+			node.AddChild(newKeyTreeNode(child))
+		}
+	}
+	node.Expand()
+}
+
+// newKeyTreeNode returns a collapsed tree node for a group or key.
+func newKeyTreeNode(g keyGroup) *tview.TreeNode {
+	if g.leaf {
+		return tview.NewTreeNode(tview.Escape(g.label)).
+			SetReference(g).
+			SetSelectable(true)
+	}
+	return tview.NewTreeNode(fmt.Sprintf("%s (%d)", tview.Escape(g.label), g.count)).
+		SetReference(g).
+		SetColor(tcell.ColorAqua).
+		SetSelectable(true).
+		SetExpanded(false)
+}
+
+// refreshKeyTree rebuilds the key tree from a.keys, re-expanding the groups
+// that were open and keeping the current node when it still exists. It does
+// nothing while the flat list is shown; the tree is rebuilt when it is
+// switched to.
+func (a *App) refreshKeyTree() {
+	if !a.treeMode {
+		return
+	}
+
+	expanded := map[string]bool{}
+	current := ""
+	if old := a.keyTree.GetRoot(); old != nil {
+		old.Walk(func(node, parent *tview.TreeNode) bool {
+			g := node.GetReference().(keyGroup)
+			if !g.leaf && node.IsExpanded() {
+				expanded[g.name] = true
+			}
+			return true
+		})
+		if node := a.keyTree.GetCurrentNode(); node != nil {
+			current = node.GetReference().(keyGroup).name
+		}
+	}
+
+	root := newKeyTreeNode(keyGroup{name: "*", label: "*", count: len(a.keys)})
+	a.keyTree.SetRoot(root).SetCurrentNode(root)
+	a.expandGroup(root)
+
+	var reopen func(node *tview.TreeNode)
+	reopen = func(node *tview.TreeNode) {
+		for _, child := range node.GetChildren() {
+			g := child.GetReference().(keyGroup)
+			if g.name == current {
+				a.keyTree.SetCurrentNode(child)
+			}
+			if !g.leaf && expanded[g.name] {
+				a.expandGroup(child)
+				reopen(child)
+			}
+		}
+	}
+	reopen(root)
+	a.leftPane.SetTitle(fmt.Sprintf(" Keys [%d%s] ", len(a.keys), a.scanProgress()))
+}
+
+// toggleKeyView switches the key pane between the flat list and the tree.
+func (a *App) toggleKeyView() {
+	a.treeMode = !a.treeMode
+	if a.treeMode {
+		a.leftPane.RemoveItem(a.keyList).AddItem(a.keyTree, 0, 1, true)
+		a.focusOrder[1] = a.keyTree
+		a.refreshKeyTree()
+		a.fetchKeys() // the tree counts need every key
+	} else {
+		a.leftPane.RemoveItem(a.keyTree).AddItem(a.keyList, 0, 1, true)
+		a.focusOrder[1] = a.keyList
+		a.leftPane.SetTitle(a.keysTitle(a.keyList.GetCurrentItem()))
+	}
+	if a.focusIndex == 1 {
+		a.focusKeys()
+	}
+}
+
+// treeKeys returns the keys under the current tree node.
+func (a *App) treeKeys() []string {
+	node := a.keyTree.GetCurrentNode()
+	if node == nil {
+		return nil
+	}
+	g := node.GetReference().(keyGroup)
+	if g.leaf {
+		return []string{g.name}
+	}
+	if node == a.keyTree.GetRoot() {
+		return slices.Clone(a.keys)
+	}
+	var keys []string
+	for _, key := range a.keys {
+		if strings.HasPrefix(key, g.name) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,156 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cosmez/redisman-go/internal/conn"
+	"github.com/cosmez/redisman-go/internal/keyspace"
+	"github.com/cosmez/redisman-go/internal/resp"
+	"github.com/rivo/tview"
+)
+
+// keyMeta is what the metadata strip shows about the current key. Fields
+// the server did not answer (older versions, ACLs) are left empty or -1.
+type keyMeta struct {
+	pttl     int64  // milliseconds, -1 for no expiry
+	encoding string // OBJECT ENCODING
+	idle     int64  // OBJECT IDLETIME in seconds, -1 if unknown
+	freq     int64  // OBJECT FREQ under an LFU maxmemory policy, -1 if unknown
+	memory   int64  // MEMORY USAGE in bytes, -1 if unknown
+	length   int64  // STRLEN or the collection length, -1 if unknown
+	typeName string // key type; a string's length is shown in bytes
+}
+
+// fetchKeyMeta pipelines PTTL, OBJECT ENCODING, OBJECT IDLETIME, MEMORY
+// USAGE and the type's length command. OBJECT IDLETIME fails under an LFU
+// policy, in which case OBJECT FREQ is asked instead. It must be called
+// while holding connMu.
+func fetchKeyMeta(c *conn.Connection, key, typeName string) (keyMeta, error) {
+	m := keyMeta{pttl: -1, idle: -1, freq: -1, memory: -1, length: -1, typeName: typeName}
+	cmds := [][]string{
+		{"PTTL", key},
+		{"OBJECT", "ENCODING", key},
+		{"OBJECT", "IDLETIME", key},
+		{"MEMORY", "USAGE", key},
+	}
+	lengthCmd := lengthCommands[typeName]
+	if typeName == "string" {
+		lengthCmd = "STRLEN"
+	}
+	if lengthCmd != "" {
+		cmds = append(cmds, []string{lengthCmd, key})
+	}
+
+	replies, err := c.Pipeline(cmds)
+	if err != nil {
+		return m, err
+	}
+	integer := func(i int) int64 {
+		if n, ok := replies[i].(resp.RedisInteger); ok {
+			return n.IntValue
+		}
+		return -1
+	}
+	m.pttl = integer(0)
+	if _, isErr := replies[1].(resp.RedisError); !isErr {
+		m.encoding = replies[1].StringValue()
+	}
+	m.idle = integer(2)
+	m.memory = integer(3)
+	if lengthCmd != "" {
+		m.length = integer(4)
+	}
+
+	if _, isErr := replies[2].(resp.RedisError); isErr {
+		if reply, err := c.Do("OBJECT", "FREQ", key); err == nil {
+			if n, ok := reply.(resp.RedisInteger); ok {
+				m.freq = n.IntValue
+			}
+		}
+	}
+	return m, nil
+}
+
+// String renders the metadata as one line, e.g.
+// "TTL 59m58s | listpack | idle 12s | 1.2 KB | 40 elements".
+func (m keyMeta) String() string {
+	ttl := "no TTL"
+	if m.pttl >= 0 {
+		ttl = "TTL " + (time.Duration(m.pttl) * time.Millisecond).Round(time.Second).String()
+		if m.pttl < 1000 {
+			ttl = fmt.Sprintf("TTL %dms", m.pttl)
+		}
+	}
+	parts := []string{ttl}
+	if m.encoding != "" {
+		parts = append(parts, m.encoding)
+	}
+	switch {
+	case m.idle >= 0:
+		parts = append(parts, "idle "+(time.Duration(m.idle)*time.Second).String())
+	case m.freq >= 0:
+		parts = append(parts, "freq "+strconv.FormatInt(m.freq, 10))
+	}
+	if m.memory >= 0 {
+		parts = append(parts, keyspace.FormatBytes(m.memory))
+	}
+	switch {
+	case m.length < 0:
+	case m.typeName == "string":
+		parts = append(parts, keyspace.FormatBytes(m.length))
+	default:
+		parts = append(parts, strconv.FormatInt(m.length, 10)+" elements")
+	}
+	return strings.Join(parts, " | ")
+}
+
+// showMeta fetches and shows the metadata of the current key, or hides the
+// strip when no key is shown.
+func (a *App) showMeta() {
+	if a.currentKey == "" {
+		a.metaBar.SetText("")
+		a.rightSide.ResizeItem(a.metaBar, 0, 0)
+		return
+	}
+	a.connMu.Lock()
+	m, err := fetchKeyMeta(a.conn, a.currentKey, a.currentType)
+	a.connMu.Unlock()
+
+	text := " " + tview.Escape(m.String())
+	if err != nil {
+		text = fmt.Sprintf(" [red]Metadata error: %v", tview.Escape(err.Error()))
+	}
+	a.metaBar.SetText(text)
+	a.rightSide.ResizeItem(a.metaBar, 1, 0)
+}
+
+// setExpiry asks for a TTL in seconds and sets it on the current key.
+func (a *App) setExpiry() {
+	if a.currentKey == "" {
+		return
+	}
+	key := a.currentKey
+
+	a.showEditModal("Set TTL: "+key, func(form *tview.Form) {
+		form.AddInputField("Seconds", "3600", 20, tview.InputFieldInteger, nil)
+	}, func(form *tview.Form) {
+		seconds, err := strconv.ParseInt(form.GetFormItemByLabel("Seconds").(*tview.InputField).GetText(), 10, 64)
+		a.app.SetRoot(a.layout, true).SetFocus(a.activeContent)
+		if err != nil || seconds <= 0 {
+			a.showError("Seconds must be a positive integer")
+			return
+		}
+		a.sendEditCommand("EXPIRE", key, strconv.FormatInt(seconds, 10))
+	})
+}
+
+// clearExpiry removes the current key's TTL.
+func (a *App) clearExpiry() {
+	if a.currentKey == "" {
+		return
+	}
+	a.sendEditCommand("PERSIST", a.currentKey)
+}
@@ -0,0 +1,234 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cosmez/redisman-go/internal/command"
+	"github.com/cosmez/redisman-go/internal/conn"
+	"github.com/cosmez/redisman-go/internal/output"
+	"github.com/cosmez/redisman-go/internal/server"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// The monitor pane keeps the last monitorLines commands and is redrawn at
+// most every monitorFlush, however busy the server is.
+const (
+	monitorLines = 2000
+	monitorFlush = 100 * time.Millisecond
+)
+
+// monitorItem is one event or error read from MONITOR.
+type monitorItem struct {
+	event conn.MonitorEvent
+	err   error
+}
+
+// monitorSession is a running (or finished) MONITOR. The reader goroutine
+// only touches queue; everything else belongs to the UI goroutine, so the
+// filter can change while it runs.
+type monitorSession struct {
+	filter  server.MonitorFilter
+	record  *os.File // matching lines are written here, nil when not recording
+	path    string
+	seen    int    // commands read
+	shown   int    // commands that passed the filter
+	stopped string // why the stream ended, empty while it runs
+	cancel  context.CancelFunc
+	queue   streamQueue[monitorItem]
+}
+
+// setupMonitor creates the monitor page. f edits the filter, c clears the
+// pane, s stops and Escape stops and returns to the output page.
+func (a *App) setupMonitor() {
+	a.monitorView = tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetMaxLines(monitorLines)
+	a.contentPages.AddPage("monitor", a.monitorView, true, false)
+
+	a.monitorView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			a.switchContent("output", "Output")
+			a.focusKeys()
+			return nil
+		}
+		if event.Key() != tcell.KeyRune || a.monitor == nil {
+			return event
+		}
+		switch event.Rune() {
+		case 'f':
+			a.filterMonitor()
+		case 'c':
+			a.monitorView.Clear()
+		case 's':
+			a.stopMonitor()
+		default:
+			return event
+		}
+		return nil
+	})
+}
+
+// handleMonitor parses MONITOR [CMD names] [KEY pattern] [CLIENT text]
+// [> file] and opens the monitor pane.
+func (a *App) handleMonitor(parsed *command.ParsedCommand) {
+	if parsed.Pipe != "" || parsed.Filter != "" {
+		fmt.Fprintf(a.ansiWriter, "[red]MONITOR output cannot be piped or filtered; use the CMD, KEY and CLIENT options, or record it with > file[white]\n")
+		return
+	}
+	filter, err := server.ParseMonitorArgs(parsed.Args)
+	if err != nil {
+		fmt.Fprintf(a.ansiWriter, "[red]Usage: MONITOR [CMD name[,name...]] [KEY pattern] [CLIENT text] [> file] (%v)[white]\n", err)
+		return
+	}
+	a.showMonitor(filter, parsed.Redirect, parsed.Append)
+}
+
+// showMonitor runs MONITOR on a second connection and shows it in the
+// monitor pane until another page is shown (see switchContent). With a path,
+// the matching lines are also recorded there as the server sent them.
+func (a *App) showMonitor(filter server.MonitorFilter, path string, appendMode bool) {
+	a.stopMonitor()
+	m := &monitorSession{filter: filter, path: path}
+	if path != "" {
+		f, err := output.OpenRedirect(path, appendMode)
+		if err != nil {
+			a.showError("Redirect failed: " + err.Error())
+			return
+		}
+		m.record = f
+	}
+
+	a.connMu.Lock()
+	mc, err := a.conn.Dial()
+	a.connMu.Unlock()
+	if err != nil {
+		if m.record != nil {
+			m.record.Close()
+		}
+		a.showError("MONITOR connection failed: " + err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	a.monitor = m
+	a.monitorView.Clear()
+	a.monitorView.ScrollToEnd()
+	a.switchContent("monitor", monitorTitle(m))
+	a.focusContent()
+
+	events := func(yield func(monitorItem) bool) {
+		defer mc.Close()
+		for e, err := range mc.Monitor(ctx) {
+			if !yield(monitorItem{e, err}) {
+				return
+			}
+		}
+	}
+	stream(a, events, &m.queue, monitorFlush,
+		func() { a.flushMonitor(m) },
+		func() { a.closeMonitor(m, "stopped") })
+}
+
+// flushMonitor shows and records the events read since the last flush. Events
+// of a session that is no longer shown are only recorded.
+func (a *App) flushMonitor(m *monitorSession) {
+	var b strings.Builder
+	for _, it := range m.queue.take() {
+		if it.err != nil {
+			// A line that cannot be parsed is shown; only other errors end
+			// the stream.
+			if !errors.Is(it.err, conn.ErrMonitorLine) {
+				m.stopped = it.err.Error()
+			}
+			fmt.Fprintf(&b, "[red]%s[-]\n", tview.Escape(it.err.Error()))
+			continue
+		}
+		m.seen++
+		if !m.filter.Match(it.event) {
+			continue
+		}
+		m.shown++
+		b.WriteString(tview.Escape(server.FormatMonitorEvent(it.event)) + "\n")
+		if m.record != nil {
+			if _, err := fmt.Fprintln(m.record, it.event.Line); err != nil {
+				a.showError("Recording stopped: " + err.Error())
+				m.record.Close()
+				m.record = nil
+			}
+		}
+	}
+	if a.monitor == m {
+		a.monitorView.Write([]byte(b.String()))
+		if a.activeContent == a.monitorView {
+			a.contentPages.SetTitle(contentTitle(monitorTitle(m)))
+		}
+	}
+}
+
+// closeMonitor finishes a session whose stream has ended: the recording is
+// closed and the title says why it stopped, unless an error already did.
+func (a *App) closeMonitor(m *monitorSession, reason string) {
+	if m.record != nil {
+		m.record.Close()
+		m.record = nil
+	}
+	if m.stopped == "" {
+		m.stopped = reason
+	}
+	if a.monitor == m && a.activeContent == a.monitorView {
+		a.contentPages.SetTitle(contentTitle(monitorTitle(m)))
+	}
+}
+
+// stopMonitor cancels the running MONITOR, if any. Its connection is closed
+// and its recording finished once the reader notices, within 200ms.
+func (a *App) stopMonitor() {
+	if a.monitor != nil && a.monitor.cancel != nil {
+		a.monitor.cancel()
+	}
+}
+
+// filterMonitor edits the filter of the running MONITOR. It applies to the
+// commands read from then on.
+func (a *App) filterMonitor() {
+	m := a.monitor
+	a.showEditModal("Filter monitor", func(form *tview.Form) {
+		form.AddInputField("Commands", strings.Join(m.filter.Commands, ","), 40, nil, nil)
+		form.AddInputField("Key pattern", m.filter.KeyPattern, 40, nil, nil)
+		form.AddInputField("Client", m.filter.Client, 40, nil, nil)
+	}, func(form *tview.Form) {
+		a.app.SetRoot(a.layout, true).SetFocus(a.activeContent)
+		m.filter = server.MonitorFilter{
+			Commands:   server.ParseMonitorCommands(form.GetFormItemByLabel("Commands").(*tview.InputField).GetText()),
+			KeyPattern: strings.TrimSpace(form.GetFormItemByLabel("Key pattern").(*tview.InputField).GetText()),
+			Client:     strings.TrimSpace(form.GetFormItemByLabel("Client").(*tview.InputField).GetText()),
+		}
+		if a.monitor == m {
+			a.contentPages.SetTitle(contentTitle(monitorTitle(m)))
+		}
+	})
+}
+
+// monitorTitle returns the monitor pane's title with its counts, filter and
+// recording.
+func monitorTitle(m *monitorSession) string {
+	title := fmt.Sprintf("Monitor [%d of %d commands", m.shown, m.seen)
+	if f := m.filter.String(); f != "" {
+		title += ", " + f
+	}
+	if m.record != nil {
+		title += ", recording to " + m.path
+	}
+	if m.stopped != "" {
+		title += ", " + m.stopped
+	}
+	return title + "] f filter, c clear, s stop"
+}
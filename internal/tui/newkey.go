@@ -0,0 +1,192 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cosmez/redisman-go/internal/conn"
+	"github.com/cosmez/redisman-go/internal/resp"
+	"github.com/cosmez/redisman-go/internal/serializer"
+	"github.com/rivo/tview"
+)
+
+// newKeyTypes are the types the New key dialog creates, with the hint shown
+// in its elements field.
+var newKeyTypes = []struct{ name, hint string }{
+	{"string", "The value"},
+	{"list", "One element per line, pushed in order"},
+	{"set", "One member per line"},
+	{"hash", "One \"field value\" per line"},
+	{"zset", "One \"score member\" per line"},
+	{"stream", "One \"field value\" per line, added as one entry"},
+}
+
+// newKeyCommand returns the command that creates key with the elements typed
+// in the New key dialog. Blank lines are skipped; a string takes the text as
+// is. Values, members and list elements are encoded with codec if not nil.
+func newKeyCommand(typeName, key, elements string, codec serializer.Serializer) ([]string, error) {
+	encode := func(v string) (string, error) {
+		if codec == nil {
+			return v, nil
+		}
+		b, err := codec.Serialize([]byte(v))
+		if err != nil {
+			return "", fmt.Errorf("codec error: %w", err)
+		}
+		return string(b), nil
+	}
+
+	if typeName == "string" {
+		value, err := encode(elements)
+		if err != nil {
+			return nil, err
+		}
+		return []string{"SET", key, value}, nil
+	}
+
+	var lines []string
+	for line := range strings.Lines(elements) {
+		if line = strings.TrimRight(line, "\r\n"); strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("a %s needs at least one element", typeName)
+	}
+
+	var cmd []string
+	switch typeName {
+	case "list", "set":
+		cmd = []string{map[string]string{"list": "RPUSH", "set": "SADD"}[typeName], key}
+		for _, line := range lines {
+			v, err := encode(line)
+			if err != nil {
+				return nil, err
+			}
+			cmd = append(cmd, v)
+		}
+	case "hash", "stream":
+		cmd = []string{"HSET", key}
+		if typeName == "stream" {
+			cmd = []string{"XADD", key, "*"}
+		}
+		for i, line := range lines {
+			field, value, ok := strings.Cut(line, " ")
+			if !ok {
+				return nil, fmt.Errorf("line %d: expected a field and a value", i+1)
+			}
+			// Stream values are stored as typed, as in addStreamEntry.
+			if typeName == "hash" {
+				var err error
+				if value, err = encode(value); err != nil {
+					return nil, err
+				}
+			}
+			cmd = append(cmd, field, value)
+		}
+	case "zset":
+		cmd = []string{"ZADD", key}
+		for i, line := range lines {
+			score, member, ok := strings.Cut(strings.TrimSpace(line), " ")
+			if _, err := strconv.ParseFloat(score, 64); !ok || err != nil {
+				return nil, fmt.Errorf("line %d: expected a score and a member", i+1)
+			}
+			m, err := encode(member)
+			if err != nil {
+				return nil, err
+			}
+			cmd = append(cmd, score, m)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported type %q", typeName)
+	}
+	return cmd, nil
+}
+
+// createKey runs cmd to create key and sets its TTL in seconds if ttl > 0.
+// An existing key is left alone and reported, since the write commands
+// would otherwise add to it. It must be called while holding connMu.
+func createKey(c *conn.Connection, key string, cmd []string, ttl int64) error {
+	reply, err := c.Do("EXISTS", key)
+	if err != nil {
+		return err
+	}
+	if n, ok := reply.(resp.RedisInteger); ok && n.IntValue > 0 {
+		return fmt.Errorf("key %q already exists", key)
+	}
+	if _, err := c.Do(cmd...); err != nil {
+		return err
+	}
+	if ttl > 0 {
+		if _, err := c.Do("EXPIRE", key, strconv.FormatInt(ttl, 10)); err != nil {
+			return fmt.Errorf("key created but EXPIRE failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// newKey shows the New key dialog: a type, a name, the initial elements, an
+// optional TTL and an optional codec (a rule matching the name applies when
+// empty, "raw" disables it). The new key is opened and selected in the key
+// list once created.
+func (a *App) newKey() {
+	a.showEditModal("New key", func(form *tview.Form) {
+		names := make([]string, len(newKeyTypes))
+		for i, t := range newKeyTypes {
+			names[i] = t.name
+		}
+		elements := tview.NewTextArea().SetPlaceholder(newKeyTypes[0].hint)
+		elements.SetLabel("Elements").SetSize(6, 50)
+		form.AddDropDown("Type", names, 0, func(option string, index int) {
+			elements.SetPlaceholder(newKeyTypes[index].hint)
+		})
+		form.AddInputField("Key", "", 50, nil, nil)
+		form.AddFormItem(elements)
+		form.AddInputField("TTL seconds", "", 20, tview.InputFieldInteger, nil)
+		form.AddInputField("Codec", "", 20, nil, nil)
+	}, func(form *tview.Form) {
+		_, typeName := form.GetFormItemByLabel("Type").(*tview.DropDown).GetCurrentOption()
+		key := form.GetFormItemByLabel("Key").(*tview.InputField).GetText()
+		elements := form.GetFormItemByLabel("Elements").(*tview.TextArea).GetText()
+		ttlText := form.GetFormItemByLabel("TTL seconds").(*tview.InputField).GetText()
+		codecSpec := strings.TrimSpace(form.GetFormItemByLabel("Codec").(*tview.InputField).GetText())
+		a.app.SetRoot(a.layout, true).SetFocus(a.activeContent)
+
+		if key == "" {
+			a.showError("Key name must not be empty")
+			return
+		}
+		var ttl int64
+		if ttlText != "" {
+			n, err := strconv.ParseInt(ttlText, 10, 64)
+			if err != nil || n <= 0 {
+				a.showError("TTL must be a positive number of seconds")
+				return
+			}
+			ttl = n
+		}
+		codec, err := a.rules.Resolve(codecSpec, key)
+		if err != nil {
+			a.showError("Codec error: " + err.Error())
+			return
+		}
+		cmd, err := newKeyCommand(typeName, key, elements, codec)
+		if err != nil {
+			a.showError(err.Error())
+			return
+		}
+
+		a.connMu.Lock()
+		err = createKey(a.conn, key, cmd, ttl)
+		a.connMu.Unlock()
+		if err != nil {
+			a.showError(err.Error())
+			return
+		}
+		a.keyToSelect = key
+		a.reloadKeys()
+		a.showKey(key)
+		a.showStatus("[green]Created")
+	})
+}
@@ -0,0 +1,300 @@
+package tui
+
+import (
+	"cmp"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cosmez/redisman-go/internal/conn"
+	"github.com/cosmez/redisman-go/internal/resp"
+	"github.com/cosmez/redisman-go/internal/serializer"
+	"github.com/rivo/tview"
+)
+
+// Collection pages: the table view is filled a page at a time as it scrolls.
+const (
+	rowPageSize = 200 // rows fetched per page
+	rowPrefetch = 20  // fetch the next page when the selection is this close to the end
+)
+
+// lengthCommands maps collection types to the command returning their length.
+var lengthCommands = map[string]string{
+	"list":   "LLEN",
+	"set":    "SCARD",
+	"hash":   "HLEN",
+	"zset":   "ZCARD",
+	"stream": "XLEN",
+}
+
+// collectionHeaders are the table headers of each collection type.
+var collectionHeaders = map[string][]string{
+	"list":   {"#", "Value"},
+	"set":    {"Member"},
+	"hash":   {"Field", "Value"},
+	"zset":   {"Member", "Score"},
+	"stream": {"ID", "Data"},
+}
+
+// collectionPage is the collection shown in tableView. Rows are fetched
+// with LRANGE, SSCAN, HSCAN, ZRANGE (or ZRANGEBYSCORE) and XRANGE, one page
+// at a time.
+type collectionPage struct {
+	key      string
+	typeName string
+	title    string
+	codec    serializer.Serializer
+	total    int64  // length when the key was opened, -1 if unknown
+	next     string // where the next page starts: list index, zset rank or offset, SCAN cursor or stream ID
+	min, max string // zset score range after a jump, empty for rank order
+	loaded   int    // data rows in the table
+	done     bool
+}
+
+// fetch returns the next page of raw rows and advances p. It must be called
+// while holding connMu.
+func (p *collectionPage) fetch(c *conn.Connection) ([][]string, error) {
+	switch p.typeName {
+	case "list":
+		start, _ := strconv.ParseInt(p.next, 10, 64)
+		reply, err := c.Do("LRANGE", p.key, strconv.FormatInt(start, 10), strconv.FormatInt(start+rowPageSize-1, 10))
+		if err != nil {
+			return nil, err
+		}
+		values := arrayValues(reply)
+		rows := make([][]string, len(values))
+		for i, v := range values {
+			rows[i] = []string{strconv.FormatInt(start+int64(i)+1, 10), v.StringValue()}
+		}
+		p.next = strconv.FormatInt(start+int64(len(values)), 10)
+		p.done = len(values) < rowPageSize
+		return rows, nil
+
+	case "set", "hash":
+		cmd := map[string]string{"set": "SSCAN", "hash": "HSCAN"}[p.typeName]
+		var rows [][]string
+		for !p.done && len(rows) < rowPageSize {
+			reply, err := c.Do(cmd, p.key, p.next, "COUNT", strconv.Itoa(rowPageSize))
+			if err != nil {
+				return rows, err
+			}
+			parts := arrayValues(reply)
+			if len(parts) < 2 {
+				return rows, fmt.Errorf("unexpected %s response format", cmd)
+			}
+			p.next = parts[0].StringValue()
+			p.done = p.next == "0"
+			items := arrayValues(parts[1])
+			if p.typeName == "set" {
+				for _, v := range items {
+					rows = append(rows, []string{v.StringValue()})
+				}
+			} else {
+				for i := 0; i+1 < len(items); i += 2 {
+					rows = append(rows, []string{items[i].StringValue(), items[i+1].StringValue()})
+				}
+			}
+		}
+		return rows, nil
+
+	case "zset":
+		offset, _ := strconv.ParseInt(p.next, 10, 64)
+		args := []string{"ZRANGE", p.key, p.next, strconv.FormatInt(offset+rowPageSize-1, 10), "WITHSCORES"}
+		if p.min != "" {
+			args = []string{"ZRANGEBYSCORE", p.key, p.min, p.max, "WITHSCORES", "LIMIT", p.next, strconv.Itoa(rowPageSize)}
+		}
+		reply, err := c.Do(args...)
+		if err != nil {
+			return nil, err
+		}
+		rows := zsetRows(arrayValues(reply))
+		p.next = strconv.FormatInt(offset+int64(len(rows)), 10)
+		p.done = len(rows) < rowPageSize
+		return rows, nil
+
+	case "stream":
+		reply, err := c.Do("XRANGE", p.key, p.next, "+", "COUNT", strconv.Itoa(rowPageSize))
+		if err != nil {
+			return nil, err
+		}
+		var rows [][]string
+		for _, entry := range arrayValues(reply) {
+			if fields := arrayValues(entry); len(fields) >= 2 {
+				rows = append(rows, []string{fields[0].StringValue(), formatStreamFields(fields[1])})
+			}
+		}
+		p.done = len(rows) < rowPageSize
+		if len(rows) > 0 {
+			p.next = nextStreamID(rows[len(rows)-1][0])
+		}
+		return rows, nil
+	}
+	p.done = true
+	return nil, nil
+}
+
+// arrayValues returns the elements of an array reply, or nil.
+func arrayValues(v resp.RedisValue) []resp.RedisValue {
+	if arr, ok := v.(resp.RedisArray); ok {
+		return arr.Values
+	}
+	return nil
+}
+
+// zsetRows turns a WITHSCORES reply into [member, score] rows. RESP2 replies
+// alternate members and scores; RESP3 replies nest them in pairs.
+func zsetRows(values []resp.RedisValue) [][]string {
+	var rows [][]string
+	for i := 0; i < len(values); i++ {
+		if pair := arrayValues(values[i]); len(pair) == 2 {
+			rows = append(rows, []string{pair[0].StringValue(), pair[1].StringValue()})
+		} else if i+1 < len(values) {
+			rows = append(rows, []string{values[i].StringValue(), values[i+1].StringValue()})
+			i++
+		}
+	}
+	return rows
+}
+
+// nextStreamID returns the smallest stream ID after id, so XRANGE can
+// continue without the exclusive "(" syntax that needs Redis 6.2.
+func nextStreamID(id string) string {
+	ms, seq, ok := strings.Cut(id, "-")
+	n, err := strconv.ParseUint(seq, 10, 64)
+	if !ok || err != nil {
+		return id
+	}
+	return ms + "-" + strconv.FormatUint(n+1, 10)
+}
+
+// showCollection opens a collection key in the table view: its length is
+// fetched up front and the first page of rows is loaded, the rest follow as
+// the table scrolls. It must not be called while holding connMu.
+func (a *App) showCollection(key, typeName, title string, codec serializer.Serializer) {
+	a.page = &collectionPage{key: key, typeName: typeName, title: title, codec: codec, total: -1}
+	a.resetPage("0")
+	if typeName == "stream" {
+		a.page.next = "-"
+	}
+
+	a.connMu.Lock()
+	if reply, err := a.conn.Do(lengthCommands[typeName], key); err == nil {
+		if n, ok := reply.(resp.RedisInteger); ok {
+			a.page.total = n.IntValue
+		}
+	}
+	a.connMu.Unlock()
+
+	a.loadRows()
+	a.switchContent("table-view", a.pageTitle())
+}
+
+// resetPage clears the table and restarts the page at next.
+func (a *App) resetPage(next string) {
+	a.page.next = next
+	a.page.loaded = 0
+	a.page.done = false
+	a.populateTable(collectionHeaders[a.page.typeName], nil, nil)
+}
+
+// loadRows appends the next page of rows to the table.
+func (a *App) loadRows() {
+	p := a.page
+	if p == nil || p.done {
+		return
+	}
+	a.connMu.Lock()
+	rows, err := p.fetch(a.conn)
+	a.connMu.Unlock()
+	if err != nil {
+		p.done = true
+		fmt.Fprintf(a.ansiWriter, "[red]Error: %v[white]\n", err)
+		a.showStatus("[red]Error")
+	}
+	a.appendTableRows(decodeRows(p.typeName, rows, p.codec), rows)
+	p.loaded += len(rows)
+	a.contentPages.SetTitle(contentTitle(a.pageTitle()))
+}
+
+// pageTitle returns the table title with the loaded row count and length.
+func (a *App) pageTitle() string {
+	p := a.page
+	switch {
+	case p.done && p.loaded == 0:
+		return fmt.Sprintf("%s [empty]", p.title)
+	case p.total < 0:
+		return fmt.Sprintf("%s [%d rows]", p.title, p.loaded)
+	default:
+		return fmt.Sprintf("%s [%d of %d]", p.title, p.loaded, p.total)
+	}
+}
+
+// jumpInCollection asks where to restart the table: a list index, a zset
+// rank or score range, or a stream ID. Sets and hashes have no order to
+// jump in.
+func (a *App) jumpInCollection() {
+	p := a.page
+	if p == nil {
+		return
+	}
+	switch p.typeName {
+	case "list":
+		a.showEditModal("Jump to index", func(form *tview.Form) {
+			form.AddInputField("Index", "0", 20, nil, nil)
+		}, func(form *tview.Form) {
+			a.app.SetRoot(a.layout, true).SetFocus(a.activeContent)
+			index, err := strconv.ParseInt(form.GetFormItemByLabel("Index").(*tview.InputField).GetText(), 10, 64)
+			if err != nil {
+				a.showError("Index must be an integer")
+				return
+			}
+			if index < 0 && p.total >= 0 {
+				index = max(p.total+index, 0) // negative indexes count from the tail, as in LRANGE
+			}
+			a.restartPage(strconv.FormatInt(max(index, 0), 10), "", "")
+		})
+	case "zset":
+		a.showEditModal("Jump to rank or score range", func(form *tview.Form) {
+			form.AddInputField("Rank", "0", 20, nil, nil)
+			form.AddInputField("Min score", "", 20, nil, nil)
+			form.AddInputField("Max score", "", 20, nil, nil)
+		}, func(form *tview.Form) {
+			a.app.SetRoot(a.layout, true).SetFocus(a.activeContent)
+			text := func(label string) string {
+				return strings.TrimSpace(form.GetFormItemByLabel(label).(*tview.InputField).GetText())
+			}
+			lo, hi := text("Min score"), text("Max score")
+			if lo != "" || hi != "" {
+				// Scores go straight to ZRANGEBYSCORE, so "(1.5" and "+inf" work.
+				a.restartPage("0", cmp.Or(lo, "-inf"), cmp.Or(hi, "+inf"))
+				return
+			}
+			rank, err := strconv.ParseInt(text("Rank"), 10, 64)
+			if err != nil || rank < 0 {
+				a.showError("Rank must be a non-negative integer")
+				return
+			}
+			a.restartPage(strconv.FormatInt(rank, 10), "", "")
+		})
+	case "stream":
+		a.showEditModal("Jump to stream ID", func(form *tview.Form) {
+			form.AddInputField("Start ID", "-", 30, nil, nil)
+		}, func(form *tview.Form) {
+			a.app.SetRoot(a.layout, true).SetFocus(a.activeContent)
+			id := strings.TrimSpace(form.GetFormItemByLabel("Start ID").(*tview.InputField).GetText())
+			a.restartPage(cmp.Or(id, "-"), "", "")
+		})
+	default:
+		a.showStatus("[yellow]No order to jump in")
+	}
+}
+
+// restartPage reloads the table from next, with the zset score range lo to
+// hi if lo is set.
+func (a *App) restartPage(next, lo, hi string) {
+	a.page.min, a.page.max = lo, hi
+	a.resetPage(next)
+	a.loadRows()
+	a.tableView.Select(1, 0)
+	a.focusContent()
+}
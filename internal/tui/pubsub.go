@@ -0,0 +1,363 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cosmez/redisman-go/internal/command"
+	"github.com/cosmez/redisman-go/internal/conn"
+	"github.com/cosmez/redisman-go/internal/server"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// The Pub/Sub pane keeps the last pubsubMessages messages across all
+// channels and is redrawn at most every pubsubFlush.
+const (
+	pubsubMessages = 2000
+	pubsubFlush    = 100 * time.Millisecond
+)
+
+// pubsubItem is one push or error read from a subscription, with the time it
+// arrived.
+type pubsubItem struct {
+	msg conn.PubSubMessage
+	err error
+	at  time.Time
+}
+
+// pubsubSession is a running (or finished) subscription. As with
+// monitorSession, the reader goroutine only touches queue.
+type pubsubSession struct {
+	subscribe string         // SUBSCRIBE, PSUBSCRIBE or SSUBSCRIBE
+	targets   []string       // the channels or patterns subscribed to
+	modifier  string         // #:codec given with the command, "" for the codec rules
+	channels  []string       // channels listed after "all", in order of appearance
+	counts    map[string]int // messages per channel
+	history   []pubsubItem   // the last pubsubMessages messages, payloads decoded
+	total     int
+	stopped   string // why the stream ended, empty while it runs
+	cancel    context.CancelFunc
+	queue     streamQueue[pubsubItem]
+}
+
+// pubsubSelected returns the channel highlighted in the channel list, "" for all.
+func (a *App) pubsubSelected() string {
+	row, _ := a.pubsubChannels.GetSelection()
+	if s := a.pubsub; s != nil && row >= 1 && row <= len(s.channels) {
+		return s.channels[row-1]
+	}
+	return ""
+}
+
+// setupPubSub creates the Pub/Sub page: channels with their message counts
+// on the left, the messages of the highlighted one on the right and a
+// publish input below. The input keeps the focus: Up and Down pick the
+// channel, Enter publishes to it and Escape unsubscribes and returns to the
+// output page.
+func (a *App) setupPubSub() {
+	a.pubsubChannels = tview.NewTable().SetSelectable(true, false)
+	a.pubsubView = tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetMaxLines(pubsubMessages)
+	a.pubsubInput = tview.NewInputField().SetLabel("Publish> ")
+	a.pubsubPane = tview.NewFlex().
+		AddItem(a.pubsubChannels, 30, 0, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(a.pubsubView, 0, 1, false).
+			AddItem(a.pubsubInput, 1, 0, true), 0, 1, true)
+	a.contentPages.AddPage("pubsub", a.pubsubPane, true, false)
+
+	a.pubsubChannels.SetSelectionChangedFunc(func(row, column int) {
+		if a.pubsub != nil {
+			a.renderPubSubMessages(a.pubsub)
+		}
+	})
+	a.pubsubInput.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			a.switchContent("output", "Output")
+			a.focusKeys()
+			return nil
+		case tcell.KeyUp, tcell.KeyDown:
+			a.pubsubChannels.InputHandler()(event, nil)
+			return nil
+		}
+		return event
+	})
+	a.pubsubInput.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter && a.pubsub != nil {
+			a.publishPubSub(a.pubsub, a.pubsubInput.GetText())
+		}
+	})
+}
+
+// handleSubscribe opens SUBSCRIBE, PSUBSCRIBE or SSUBSCRIBE in the Pub/Sub
+// pane. Payloads are decoded with #:codec, or the codec rule matching each
+// channel.
+func (a *App) handleSubscribe(parsed *command.ParsedCommand) {
+	if len(parsed.Args) == 0 {
+		fmt.Fprintf(a.ansiWriter, "[red]Usage: %s channel [channel ...][white]\n", parsed.Name)
+		return
+	}
+	if parsed.Pipe != "" || parsed.Filter != "" || parsed.Redirect != "" {
+		fmt.Fprintf(a.ansiWriter, "[red]%s output cannot be piped, filtered or redirected in the TUI; use the REPL for that[white]\n", parsed.Name)
+		return
+	}
+	a.showPubSub(parsed.Name, parsed.Args, parsed.Modifier)
+}
+
+// showPubSub subscribes on a second connection and shows the messages in the
+// Pub/Sub pane until another page is shown (see switchContent).
+func (a *App) showPubSub(subscribe string, targets []string, modifier string) {
+	a.stopPubSub()
+	a.connMu.Lock()
+	sc, err := a.conn.Dial()
+	a.connMu.Unlock()
+	if err != nil {
+		a.showError("Subscribe connection failed: " + err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &pubsubSession{subscribe: subscribe, targets: targets, modifier: modifier, counts: map[string]int{}, cancel: cancel}
+	if subscribe != "PSUBSCRIBE" {
+		s.channels = slices.Clone(targets)
+	}
+	a.pubsub = s
+	a.pubsubInput.SetText("")
+	a.renderPubSubChannels(s)
+	a.pubsubChannels.Select(0, 0) // "all", which also clears the messages
+	a.switchContent("pubsub", pubsubTitle(s))
+	a.focusContent()
+
+	items := func(yield func(pubsubItem) bool) {
+		defer sc.Close()
+		for m, err := range sc.Listen(ctx, subscribe, targets...) {
+			if !yield(pubsubItem{m, err, time.Now()}) {
+				return
+			}
+		}
+	}
+	stream(a, items, &s.queue, pubsubFlush,
+		func() { a.flushPubSub(s) },
+		func() {
+			if s.stopped == "" {
+				s.stopped = "unsubscribed"
+			}
+			if a.pubsub == s && a.activeContent == a.pubsubPane {
+				a.contentPages.SetTitle(contentTitle(pubsubTitle(s)))
+			}
+		})
+}
+
+// flushPubSub adds the messages read since the last flush, decoding their
+// payloads. Subscription confirmations are skipped.
+func (a *App) flushPubSub(s *pubsubSession) {
+	selected := a.pubsubSelected()
+	var b strings.Builder
+	for _, it := range s.queue.take() {
+		if it.err != nil {
+			s.stopped = it.err.Error()
+			fmt.Fprintf(&b, "[red]%s[-]\n", tview.Escape(it.err.Error()))
+			continue
+		}
+		if !it.msg.IsMessage() {
+			continue
+		}
+		if ser, err := a.rules.Resolve(s.modifier, it.msg.Channel); err == nil {
+			it.msg.Payload = server.DecodePayload(it.msg.Payload, ser)
+		}
+		s.total++
+		if !slices.Contains(s.channels, it.msg.Channel) {
+			s.channels = append(s.channels, it.msg.Channel)
+		}
+		s.counts[it.msg.Channel]++
+		s.history = append(s.history, it)
+		if selected == "" || selected == it.msg.Channel {
+			b.WriteString(pubsubLine(it))
+		}
+	}
+	if len(s.history) > pubsubMessages {
+		s.history = slices.Delete(s.history, 0, len(s.history)-pubsubMessages)
+	}
+	if a.pubsub != s {
+		return
+	}
+	a.pubsubView.Write([]byte(b.String()))
+	a.renderPubSubChannels(s)
+	if a.activeContent == a.pubsubPane {
+		a.contentPages.SetTitle(contentTitle(pubsubTitle(s)))
+	}
+}
+
+// pubsubLine renders a message as "time source: payload".
+func pubsubLine(it pubsubItem) string {
+	return fmt.Sprintf("[gray]%s[-] [aqua]%s:[-] %s\n", it.at.Format(server.MonitorTimeFormat),
+		tview.Escape(server.MessageSource(it.msg)), tview.Escape(it.msg.Payload))
+}
+
+// renderPubSubChannels fills the channel list. Channels are only ever added,
+// so the selection stays where it was.
+func (a *App) renderPubSubChannels(s *pubsubSession) {
+	a.pubsubChannels.Clear()
+	a.pubsubChannels.SetCell(0, 0, tview.NewTableCell("all").SetTextColor(tcell.ColorYellow).SetExpansion(1))
+	a.pubsubChannels.SetCell(0, 1, tview.NewTableCell(strconv.Itoa(s.total)).SetAlign(tview.AlignRight))
+	for i, ch := range s.channels {
+		a.pubsubChannels.SetCell(i+1, 0, tview.NewTableCell(tview.Escape(ch)).SetExpansion(1))
+		a.pubsubChannels.SetCell(i+1, 1, tview.NewTableCell(strconv.Itoa(s.counts[ch])).SetAlign(tview.AlignRight))
+	}
+	a.updatePublishLabel()
+}
+
+// renderPubSubMessages shows the kept messages of the selected channel.
+func (a *App) renderPubSubMessages(s *pubsubSession) {
+	selected := a.pubsubSelected()
+	var b strings.Builder
+	for _, it := range s.history {
+		if selected == "" || selected == it.msg.Channel {
+			b.WriteString(pubsubLine(it))
+		}
+	}
+	a.pubsubView.SetText(b.String())
+	a.pubsubView.ScrollToEnd()
+	a.updatePublishLabel()
+}
+
+// updatePublishLabel shows where the publish input sends to.
+func (a *App) updatePublishLabel() {
+	if ch := a.pubsubSelected(); ch != "" {
+		a.pubsubInput.SetLabel("Publish to " + ch + "> ")
+	} else {
+		a.pubsubInput.SetLabel("Publish (channel message)> ")
+	}
+}
+
+// publishPubSub publishes text to the selected channel, or, with "all"
+// selected, text's first word is the channel and the rest the message. The
+// message is encoded with the codec the pane decodes that channel with, and
+// sent with SPUBLISH for a shard subscription.
+func (a *App) publishPubSub(s *pubsubSession, text string) {
+	channel, message := a.pubsubSelected(), text
+	if channel == "" {
+		var ok bool
+		if channel, message, ok = strings.Cut(strings.TrimLeft(text, " "), " "); !ok {
+			a.showError("Type the channel, a space and the message, or pick a channel with Up and Down")
+			return
+		}
+	}
+	ser, err := a.rules.Resolve(s.modifier, channel)
+	if err != nil {
+		a.showError("Codec error: " + err.Error())
+		return
+	}
+	if ser != nil {
+		b, err := ser.Serialize([]byte(message))
+		if err != nil {
+			a.showError("Codec error: " + err.Error())
+			return
+		}
+		message = string(b)
+	}
+
+	a.connMu.Lock()
+	n, err := a.conn.Publish(s.subscribe == "SSUBSCRIBE", channel, message)
+	a.connMu.Unlock()
+	if err != nil {
+		a.showError("PUBLISH failed: " + err.Error())
+		return
+	}
+	a.pubsubInput.SetText("")
+	a.showStatus(fmt.Sprintf("[green]Delivered to %d subscribers", n))
+}
+
+// stopPubSub cancels the running subscription, if any.
+func (a *App) stopPubSub() {
+	if a.pubsub != nil && a.pubsub.cancel != nil {
+		a.pubsub.cancel()
+	}
+}
+
+// pubsubTitle returns the Pub/Sub pane's title with what is subscribed and
+// the message count.
+func pubsubTitle(s *pubsubSession) string {
+	title := fmt.Sprintf("Pub/Sub [%s %s, %d messages", s.subscribe, strings.Join(s.targets, " "), s.total)
+	if s.stopped != "" {
+		title += ", " + s.stopped
+	}
+	return title + "] Up/Down channel, Enter publish"
+}
+
+// handlePubSub shows PUBSUB CHANNELS and NUMSUB (and their SHARD variants)
+// in the report view. It returns false for other subcommands and for replies
+// sent to a pipe, filter or file, which run as standard commands.
+func (a *App) handlePubSub(parsed *command.ParsedCommand) bool {
+	sub := ""
+	if len(parsed.Args) > 0 {
+		sub = strings.ToUpper(parsed.Args[0])
+	}
+	if parsed.Pipe != "" || parsed.Filter != "" || parsed.Redirect != "" {
+		return false
+	}
+	switch sub {
+	case "CHANNELS", "SHARDCHANNELS":
+		if len(parsed.Args) > 2 {
+			fmt.Fprintf(a.ansiWriter, "[red]Usage: PUBSUB %s [pattern][white]\n", sub)
+			return true
+		}
+		pattern := ""
+		if len(parsed.Args) == 2 {
+			pattern = parsed.Args[1]
+		}
+		a.showChannels(sub == "SHARDCHANNELS", pattern, nil)
+	case "NUMSUB", "SHARDNUMSUB":
+		if len(parsed.Args) < 2 {
+			fmt.Fprintf(a.ansiWriter, "[red]Usage: PUBSUB %s channel [channel ...][white]\n", sub)
+			return true
+		}
+		a.showChannels(sub == "SHARDNUMSUB", "", parsed.Args[1:])
+	default:
+		return false
+	}
+	return true
+}
+
+// showChannels lists channels with their subscriber counts in the report
+// view: the given names, or else the active channels matching pattern.
+// Enter subscribes to the highlighted channel and r refreshes.
+func (a *App) showChannels(shard bool, pattern string, names []string) {
+	var channels []server.ChannelInfo
+	var err error
+	a.connMu.Lock()
+	if names != nil {
+		channels, err = server.NumSub(a.conn, shard, names)
+	} else {
+		channels, err = server.Channels(a.conn, shard, pattern)
+	}
+	a.connMu.Unlock()
+	if err != nil {
+		a.showError("PUBSUB failed: " + err.Error())
+		return
+	}
+
+	subscribe := "SUBSCRIBE"
+	if shard {
+		subscribe = "SSUBSCRIBE"
+	}
+	rows := make([]reportRow, len(channels))
+	for i, ch := range channels {
+		rows[i] = reportRow{
+			cells:    []string{ch.Channel, strconv.FormatInt(ch.Subscribers, 10)},
+			selected: func() { a.showPubSub(subscribe, []string{ch.Channel}, "") },
+		}
+	}
+	a.showReport(fmt.Sprintf("Channels [%d] Enter subscribe, r refresh", len(channels)), []string{"Channel", "Subscribers"}, rows)
+	a.reportKeys = map[rune]func(){
+		'r': func() { a.showChannels(shard, pattern, names) },
+	}
+}
@@ -0,0 +1,74 @@
+package tui
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// reportRow is one row of the report view.
+type reportRow struct {
+	cells    []string
+	section  bool   // a section heading: highlighted and not selectable
+	selected func() // run on Enter, nil if the row has no action
+}
+
+// setupReportView creates the report table page used by ANALYZE and other
+// keyspace reports. Enter runs the selected row's action, the report's
+// reportKeys handle their letters and Escape returns to the output page.
+func (a *App) setupReportView() {
+	a.reportTable = tview.NewTable().
+		SetBorders(false).
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+	a.contentPages.AddPage("report-view", a.reportTable, true, false)
+
+	a.reportTable.SetSelectedFunc(func(row, column int) {
+		if row >= 1 && row <= len(a.reportRows) && a.reportRows[row-1].selected != nil {
+			a.reportRows[row-1].selected()
+		}
+	})
+	a.reportTable.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			a.switchContent("output", "Output")
+			a.focusKeys()
+			return nil
+		}
+		if action := a.reportKeys[event.Rune()]; event.Key() == tcell.KeyRune && action != nil {
+			action()
+			return nil
+		}
+		return event
+	})
+}
+
+// showReport fills the report table and switches to it. Reports with
+// their own shortcuts set reportKeys afterwards.
+func (a *App) showReport(title string, headers []string, rows []reportRow) {
+	a.reportTable.Clear()
+	a.reportRows = rows
+	a.reportKeys = nil
+
+	for col, h := range headers {
+		a.reportTable.SetCell(0, col, tview.NewTableCell(h).
+			SetTextColor(tcell.ColorYellow).
+			SetSelectable(false).
+			SetExpansion(1))
+	}
+	for r, row := range rows {
+		for c, val := range row.cells {
+			cell := tview.NewTableCell(tview.Escape(val)).SetExpansion(1)
+			switch {
+			case row.section:
+				cell.SetTextColor(tcell.ColorFuchsia).SetSelectable(false)
+			case c == 0:
+				cell.SetTextColor(tcell.ColorAqua)
+			}
+			a.reportTable.SetCell(r+1, c, cell)
+		}
+	}
+
+	a.reportTable.ScrollToBeginning()
+	a.reportTable.Select(1, 0)
+	a.switchContent("report-view", title)
+	a.focusContent()
+}
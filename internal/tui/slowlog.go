@@ -0,0 +1,115 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cosmez/redisman-go/internal/command"
+	"github.com/cosmez/redisman-go/internal/server"
+	"github.com/rivo/tview"
+)
+
+// handleSlowlog opens SLOWLOG GET in the report view and always confirms
+// SLOWLOG RESET. It returns false for other subcommands and for SLOWLOG GET
+// replies sent to a pipe, filter or file, which run as standard commands.
+func (a *App) handleSlowlog(parsed *command.ParsedCommand) bool {
+	sub := ""
+	if len(parsed.Args) > 0 {
+		sub = strings.ToUpper(parsed.Args[0])
+	}
+	if sub == "RESET" {
+		a.resetSlowlog(nil)
+		return true
+	}
+	if parsed.Pipe != "" || parsed.Filter != "" || parsed.Redirect != "" {
+		return false
+	}
+	switch sub {
+	case "GET":
+		opts, err := server.ParseSlowlogArgs(parsed.Args[1:])
+		if err != nil {
+			fmt.Fprintf(a.ansiWriter, "[red]Usage: SLOWLOG GET [count] [SORT time|duration] [CMD name] (%v)[white]\n", err)
+			return true
+		}
+		a.showSlowlog(opts)
+	default:
+		return false
+	}
+	return true
+}
+
+// showSlowlog fetches the slowlog and shows it in the report view. s toggles
+// the sort order, f filters by command, r refreshes and X resets the log.
+func (a *App) showSlowlog(opts server.SlowlogOptions) {
+	a.connMu.Lock()
+	entries, err := server.Slowlog(a.conn, opts)
+	a.connMu.Unlock()
+	if err != nil {
+		a.showError("SLOWLOG failed: " + err.Error())
+		return
+	}
+
+	rows := make([]reportRow, len(entries))
+	for i, e := range entries {
+		rows[i] = reportRow{cells: []string{
+			strconv.FormatInt(e.ID, 10), e.Time.Format(server.TimeFormat), e.Duration.String(), e.Client, e.Name, e.CommandLine(),
+		}}
+	}
+	title := fmt.Sprintf("Slowlog [%d entries by %s", len(entries), opts.Sort)
+	if opts.Command != "" {
+		title += ", " + strings.ToUpper(opts.Command) + " only"
+	}
+	title += "] s sort, f filter, r refresh, X reset"
+	a.showReport(title, []string{"ID", "Time", "Duration", "Client", "Name", "Command"}, rows)
+
+	a.reportKeys = map[rune]func(){
+		's': func() {
+			if opts.Sort == server.SortByDuration {
+				opts.Sort = server.SortByTime
+			} else {
+				opts.Sort = server.SortByDuration
+			}
+			a.showSlowlog(opts)
+		},
+		'f': func() { a.filterSlowlog(opts) },
+		'r': func() { a.showSlowlog(opts) },
+		'X': func() { a.resetSlowlog(&opts) },
+	}
+}
+
+// filterSlowlog asks for a command name to keep, empty for all.
+func (a *App) filterSlowlog(opts server.SlowlogOptions) {
+	a.showEditModal("Filter slowlog", func(form *tview.Form) {
+		form.AddInputField("Command", opts.Command, 30, nil, nil)
+	}, func(form *tview.Form) {
+		a.app.SetRoot(a.layout, true).SetFocus(a.activeContent)
+		opts.Command = strings.TrimSpace(form.GetFormItemByLabel("Command").(*tview.InputField).GetText())
+		a.showSlowlog(opts)
+	})
+}
+
+// resetSlowlog empties the slowlog after a confirmation. With opts, the
+// slowlog view is reloaded with them afterwards.
+func (a *App) resetSlowlog(opts *server.SlowlogOptions) {
+	a.connMu.Lock()
+	n, err := server.SlowlogLen(a.conn)
+	a.connMu.Unlock()
+	if err != nil {
+		a.showError("SLOWLOG LEN failed: " + err.Error())
+		return
+	}
+	a.confirmAndExecute(fmt.Sprintf("Reset the slowlog (%d entries)?", n), func() {
+		a.connMu.Lock()
+		err := server.ResetSlowlog(a.conn)
+		a.connMu.Unlock()
+		if err != nil {
+			a.showError("SLOWLOG RESET failed: " + err.Error())
+			return
+		}
+		a.showStatus("[green]Slowlog reset")
+		if opts != nil {
+			a.showSlowlog(*opts)
+		}
+	})
+}
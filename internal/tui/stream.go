@@ -0,0 +1,66 @@
+package tui
+
+import (
+	"iter"
+	"sync"
+	"time"
+)
+
+// streamQueue holds what a reader goroutine has read until the UI goroutine
+// takes it, so busy streams (MONITOR, Pub/Sub) are drawn in batches.
+type streamQueue[T any] struct {
+	mu    sync.Mutex
+	items []T
+}
+
+func (q *streamQueue[T]) push(item T) {
+	q.mu.Lock()
+	q.items = append(q.items, item)
+	q.mu.Unlock()
+}
+
+// take empties the queue and returns what it held.
+func (q *streamQueue[T]) take() []T {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	items := q.items
+	q.items = nil
+	return items
+}
+
+func (q *streamQueue[T]) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items)
+}
+
+// stream reads seq into q on a goroutine and runs flush on the UI goroutine
+// every interval while q holds something. Once seq ends, flush runs a last
+// time, followed by end.
+func stream[T any](a *App, seq iter.Seq[T], q *streamQueue[T], interval time.Duration, flush, end func()) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for item := range seq {
+			q.push(item)
+		}
+	}()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if q.len() > 0 {
+					a.app.QueueUpdateDraw(flush)
+				}
+			case <-done:
+				a.app.QueueUpdateDraw(func() {
+					flush()
+					end()
+				})
+				return
+			}
+		}
+	}()
+}
@@ -1,17 +1,31 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"sync"
+	"sync/atomic"
 
 	"github.com/cosmez/redisman-go/internal/command"
+	"github.com/cosmez/redisman-go/internal/config"
 	"github.com/cosmez/redisman-go/internal/conn"
+	"github.com/cosmez/redisman-go/internal/output"
+	"github.com/cosmez/redisman-go/internal/serializer"
 	"github.com/fatih/color"
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
 
+// Options carries user configuration into the TUI.
+type Options struct {
+	Rules    *serializer.Rules // key pattern → codec rules, nil for none
+	Format   string            // file format for `> file` redirects; empty for text
+	Profiles config.Profiles   // named servers for COPY-KEYS
+	KeyView  string            // "tree" to start with the key tree; empty or "list" for the flat list
+	KeyDelim string            // key name delimiter for the key tree; empty for ":"
+}
+
 // App holds all TUI state.
 //
 // C#: Roughly equivalent to a WPF Window class with bound properties.
@@ -19,46 +33,98 @@ import (
 type App struct {
 	conn     *conn.Connection
 	registry *command.Registry
+	rules    *serializer.Rules
+	format   string // file format for `> file` redirects, changed with FORMAT
+	profiles config.Profiles
 
 	app          *tview.Application
-	layout       *tview.Flex   // root layout (restored after modals)
-	contentPages *tview.Pages  // swaps between outputView and future type-specific views
+	layout       *tview.Flex  // root layout (restored after modals)
+	contentPages *tview.Pages // swaps between outputView and future type-specific views
 	outputView   *tview.TextView
 	keyList      *tview.List
+	keyTree      *tview.TreeView // keys grouped by keyDelim, shown instead of keyList in tree mode
+	treeMode     bool            // keyTree is shown instead of keyList (Ctrl+G toggles)
+	keyDelim     string          // separates key name segments in keyTree
 	cmdInput     *tview.InputField
 	filterInput  *tview.InputField
-	ansiWriter   io.Writer // tview.ANSIWriter(outputView) — translates ANSI escapes to tview color tags
+	ansiWriter   io.Writer   // tview.ANSIWriter(outputView) — translates ANSI escapes to tview color tags
 	leftPane     *tview.Flex // for updating key list title with scroll position
 
 	// Type-specific key views
 	tableView     *tview.Table    // shared table for list/set/hash/zset/stream
+	page          *collectionPage // collection shown in tableView, loaded as it scrolls
 	stringView    *tview.TextView // dedicated view for string key values
+	jsonTree      *tview.TreeView // collapsible view for string values holding JSON
+	jsonTitle     string          // content title while jsonTree is shown (path is appended)
 	activeContent tview.Primitive // currently visible content widget (for focus cycling)
 
+	// Keyspace reports (ANALYZE, ...)
+	reportTable *tview.Table
+	reportRows  []reportRow     // rows shown in reportTable, after its header row
+	reportKeys  map[rune]func() // shortcuts of the report shown, e.g. sorting the slowlog
+
+	// Server dashboard (DASHBOARD)
+	dashTable *tview.Table
+	dash      *dashboard    // INFO samples shown in dashTable (see dashboard.go)
+	dashGen   atomic.Uint64 // bumped to stop the dashboard refresh
+
+	// MONITOR pane
+	monitorView *tview.TextView
+	monitor     *monitorSession // the session shown in monitorView (see monitor.go)
+
+	// Pub/Sub pane (SUBSCRIBE, PSUBSCRIBE, SSUBSCRIBE)
+	pubsubPane     *tview.Flex
+	pubsubChannels *tview.Table      // "all" and the channels that received messages
+	pubsubView     *tview.TextView   // messages of the selected channel
+	pubsubInput    *tview.InputField // publishes to the selected channel
+	pubsub         *pubsubSession    // the subscription shown in pubsubPane (see pubsub.go)
+
+	// Keyspace notification feed (WATCHKEYS)
+	watchTable *tview.Table
+	watch      *watchSession // runs in the background until stopped (see watch.go)
+
 	// Action bar and CRUD state
-	actionBar   *tview.Flex     // contextual edit buttons between content and command input
-	statusLabel *tview.TextView // transient status feedback (right side of action bar)
-	currentKey  string          // name of currently viewed key (empty when on output page)
-	currentType string          // Redis type of currently viewed key
+	rightSide    *tview.Flex           // content, metadata strip, action bar and command input
+	metaBar      *tview.TextView       // TTL, encoding, memory, ... of the current key (see meta.go)
+	actionBar    *tview.Flex           // contextual edit buttons between content and command input
+	statusLabel  *tview.TextView       // transient status feedback (right side of action bar)
+	currentKey   string                // name of currently viewed key (empty when on output page)
+	currentType  string                // Redis type of currently viewed key
+	currentCodec serializer.Serializer // codec applied to the current key's values, nil for none
 
 	bottomPane *tview.Flex // command input container (for border highlighting)
 
 	focusOrder []tview.Primitive
 	focusIndex int
 
-	keys   []string   // current key names (parallel to keyList items)
-	connMu sync.Mutex // serializes all connection operations
+	keys    []string        // current key names (parallel to keyList items)
+	marked  map[string]bool // keys marked with Space for bulk delete/expire
+	scan    keyScan         // incremental SCAN filling keys (see keys.go)
+	scanGen atomic.Uint64   // bumped to cancel the running key scan
+	connMu  sync.Mutex      // serializes all connection operations
+
+	keyToSelect string // highlighted in the key list when the scan reaches it, e.g. a new key
 }
 
 // newApp creates and initializes the TUI application with all widgets.
 // Separated from Run() for testability (smoke tests can build the app without
 // calling Run, which takes over the terminal).
-func newApp(c *conn.Connection, reg *command.Registry) *App {
+func newApp(c *conn.Connection, reg *command.Registry, opts Options) *App {
 	a := &App{
 		conn:     c,
 		registry: reg,
+		rules:    opts.Rules,
+		format:   opts.Format,
+		profiles: opts.Profiles,
+		keyDelim: opts.KeyDelim,
 		app:      tview.NewApplication(),
 	}
+	if a.format == "" {
+		a.format = output.FormatText
+	}
+	if a.keyDelim == "" {
+		a.keyDelim = defaultKeyDelim
+	}
 
 	// --- Left pane: filter + key list ---
 	a.filterInput = tview.NewInputField().
@@ -96,9 +162,21 @@ func newApp(c *conn.Connection, reg *command.Registry) *App {
 		SetScrollable(true).
 		SetWordWrap(true)
 
+	a.jsonTree = tview.NewTreeView().
+		SetGraphics(true)
+	a.jsonTree.SetSelectedFunc(func(node *tview.TreeNode) {
+		node.SetExpanded(!node.IsExpanded())
+	})
+	a.jsonTree.SetChangedFunc(func(node *tview.TreeNode) {
+		if path, ok := node.GetReference().(string); ok {
+			a.contentPages.SetTitle(contentTitle(a.jsonTitle + " | " + path))
+		}
+	})
+
 	a.contentPages.
 		AddPage("string-view", a.stringView, true, false).
-		AddPage("table-view", a.tableView, true, false)
+		AddPage("table-view", a.tableView, true, false).
+		AddPage("json-view", a.jsonTree, true, false)
 
 	a.activeContent = a.outputView
 
@@ -106,9 +184,7 @@ func newApp(c *conn.Connection, reg *command.Registry) *App {
 	a.tableView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 		if event.Key() == tcell.KeyEscape {
 			a.switchContent("output", "Output")
-			a.focusIndex = 1 // keyList
-			a.app.SetFocus(a.keyList)
-			a.highlightFocusedPane()
+			a.focusKeys()
 			return nil
 		}
 		return event
@@ -116,9 +192,15 @@ func newApp(c *conn.Connection, reg *command.Registry) *App {
 	a.stringView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 		if event.Key() == tcell.KeyEscape {
 			a.switchContent("output", "Output")
-			a.focusIndex = 1 // keyList
-			a.app.SetFocus(a.keyList)
-			a.highlightFocusedPane()
+			a.focusKeys()
+			return nil
+		}
+		return event
+	})
+	a.jsonTree.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			a.switchContent("output", "Output")
+			a.focusKeys()
 			return nil
 		}
 		return event
@@ -132,6 +214,10 @@ func newApp(c *conn.Connection, reg *command.Registry) *App {
 	a.statusLabel.SetBackgroundColor(tcell.ColorDarkSlateGray)
 	a.statusLabel.SetTextAlign(tview.AlignRight)
 
+	// --- Metadata strip: hidden until a key is shown ---
+	a.metaBar = tview.NewTextView().SetDynamicColors(true)
+	a.metaBar.SetTextColor(tcell.ColorLightGray)
+
 	// --- Bottom pane: command input ---
 	a.cmdInput = tview.NewInputField().
 		SetLabel("> ").
@@ -142,23 +228,21 @@ func newApp(c *conn.Connection, reg *command.Registry) *App {
 	a.bottomPane.SetBorder(true).SetTitle(" Command ")
 
 	// --- Compose layout ---
-	rightSide := tview.NewFlex().SetDirection(tview.FlexRow).
+	a.rightSide = tview.NewFlex().SetDirection(tview.FlexRow).
 		AddItem(a.contentPages, 0, 1, false).
+		AddItem(a.metaBar, 0, 0, false).
 		AddItem(a.actionBar, 1, 0, false).
 		AddItem(a.bottomPane, 3, 0, false)
 
 	a.layout = tview.NewFlex().
 		AddItem(a.leftPane, 0, 3, false).
-		AddItem(rightSide, 0, 7, false)
+		AddItem(a.rightSide, 0, 7, false)
 
 	// --- ANSI writer for output.PrintRedisValue ---
 	a.ansiWriter = tview.ANSIWriter(a.outputView)
 
 	// --- Scroll position indicators in pane titles ---
-	a.keyList.SetChangedFunc(func(index int, mainText string, secondaryText string, shortcut rune) {
-		total := a.keyList.GetItemCount()
-		a.leftPane.SetTitle(fmt.Sprintf(" Keys [%d/%d] ", index+1, total))
-	})
+	// (the key list's is set in setupKeyHandlers, where it also fetches more keys)
 	a.outputView.SetChangedFunc(func() {
 		if a.activeContent == a.outputView {
 			row, _ := a.outputView.GetScrollOffset()
@@ -167,16 +251,31 @@ func newApp(c *conn.Connection, reg *command.Registry) *App {
 	})
 
 	// --- Forward typing on keyList to filter input ---
-	// When the key list is focused and the user types a printable character,
+	// Space, Delete and Ctrl+T are bulk actions on marked keys (see bulk.go),
+	// Ctrl+G switches to the key tree (see keytree.go), Ctrl+N creates a key
+	// (see newkey.go).
+	// When the key list is focused and the user types another printable character,
 	// move focus to the filter input and append the character. This lets the
 	// user start filtering just by typing without having to Tab to the filter.
 	a.keyList.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		ch := event.Rune()
-		if ch != 0 && event.Key() == tcell.KeyRune {
-			a.filterInput.SetText(a.filterInput.GetText() + string(ch))
-			a.app.SetFocus(a.filterInput)
-			a.focusIndex = 0 // filterInput is index 0
-			a.highlightFocusedPane()
+		switch {
+		case event.Key() == tcell.KeyRune && event.Rune() == ' ':
+			a.toggleMark(a.keyList.GetCurrentItem())
+			return nil
+		case event.Key() == tcell.KeyDelete:
+			a.deleteMarked()
+			return nil
+		case event.Key() == tcell.KeyCtrlT:
+			a.expireMarked()
+			return nil
+		case event.Key() == tcell.KeyCtrlG:
+			a.toggleKeyView()
+			return nil
+		case event.Key() == tcell.KeyCtrlN:
+			a.newKey()
+			return nil
+		case event.Key() == tcell.KeyRune:
+			a.typeIntoFilter(event.Rune())
 			return nil
 		}
 		return event
@@ -206,6 +305,15 @@ func newApp(c *conn.Connection, reg *command.Registry) *App {
 	a.setupKeyHandlers()
 	a.setupCommandInput()
 	a.setupEditHandlers()
+	a.setupReportView()
+	a.setupDashboard()
+	a.setupMonitor()
+	a.setupPubSub()
+	a.setupWatch()
+	a.setupKeyTree()
+	if opts.KeyView == "tree" {
+		a.toggleKeyView()
+	}
 
 	// Set initial border highlight (cmdInput is focused).
 	a.highlightFocusedPane()
@@ -213,20 +321,43 @@ func newApp(c *conn.Connection, reg *command.Registry) *App {
 	return a
 }
 
+// typeIntoFilter appends ch to the filter input and focuses it, so the user
+// can start filtering the key pane just by typing.
+func (a *App) typeIntoFilter(ch rune) {
+	a.filterInput.SetText(a.filterInput.GetText() + string(ch))
+	a.app.SetFocus(a.filterInput)
+	a.focusIndex = 0 // filterInput is index 0
+	a.highlightFocusedPane()
+}
+
+// focusKeys moves focus to the key pane (list or tree).
+func (a *App) focusKeys() {
+	a.focusIndex = 1
+	a.app.SetFocus(a.focusOrder[1])
+	a.highlightFocusedPane()
+}
+
 // Run creates and starts the TUI application. This is the public entry point
 // called from main.go when --tui is passed.
-func Run(c *conn.Connection, registry *command.Registry) error {
+func Run(c *conn.Connection, registry *command.Registry, opts Options) error {
 	// Force color output — fatih/color auto-detects no-terminal and disables
 	// colors, but tview.ANSIWriter needs ANSI codes to translate into tview
 	// color tags.
 	color.NoColor = false
 
-	a := newApp(c, registry)
+	a := newApp(c, registry, opts)
 
 	// Load keys synchronously before the event loop starts (no concurrency concerns).
 	if c != nil {
 		a.loadKeysSync("*")
 	}
 
-	return a.app.EnableMouse(true).SetRoot(a.layout, true).SetFocus(a.cmdInput).Run()
+	err := a.app.EnableMouse(true).SetRoot(a.layout, true).SetFocus(a.cmdInput).Run()
+
+	// A watch still running has changed the server's notify-keyspace-events.
+	if a.watch != nil {
+		a.stopWatch()
+		err = errors.Join(err, a.restoreNotifications(a.watch))
+	}
+	return err
 }
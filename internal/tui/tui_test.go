@@ -1,9 +1,20 @@
 package tui
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/cosmez/redisman-go/internal/command"
+	"github.com/cosmez/redisman-go/internal/conn"
+	"github.com/cosmez/redisman-go/internal/keyspace"
+	"github.com/cosmez/redisman-go/internal/redistest"
+	"github.com/cosmez/redisman-go/internal/serializer"
 )
 
 // TestAppScaffold verifies that the TUI application can be constructed
@@ -16,7 +27,7 @@ func TestAppScaffold(t *testing.T) {
 	}
 
 	// Build the app with a nil connection (we won't execute commands).
-	app := newApp(nil, reg)
+	app := newApp(nil, reg, Options{})
 	if app == nil {
 		t.Fatal("newApp returned nil")
 	}
@@ -54,3 +65,771 @@ func TestAppScaffold(t *testing.T) {
 		t.Errorf("Expected 4 focus targets, got %d", len(app.focusOrder))
 	}
 }
+
+func TestBuildJSONTree(t *testing.T) {
+	root, err := buildJSONTree(`{"items":[{"id":7}],"odd key":true}`)
+	if err != nil {
+		t.Fatalf("buildJSONTree failed: %v", err)
+	}
+
+	children := root.GetChildren()
+	if len(children) != 2 {
+		t.Fatalf("Expected 2 top-level children, got %d", len(children))
+	}
+	if got := children[0].GetText(); got != "items: [1]" {
+		t.Errorf("Expected first child text %q, got %q", "items: [1]", got)
+	}
+	if got := children[1].GetReference(); got != `$["odd key"]` {
+		t.Errorf("Expected bracket path for odd key, got %v", got)
+	}
+
+	leaf := children[0].GetChildren()[0].GetChildren()[0]
+	if got := leaf.GetReference(); got != "$.items[0].id" {
+		t.Errorf("Expected leaf path $.items[0].id, got %v", got)
+	}
+
+	if _, err := buildJSONTree(`{"a":`); err == nil {
+		t.Error("Expected error for truncated JSON")
+	}
+}
+
+func TestToggleMark(t *testing.T) {
+	reg, err := command.NewRegistry()
+	if err != nil {
+		t.Fatalf("Failed to create registry: %v", err)
+	}
+	app := newApp(nil, reg, Options{})
+	for _, key := range []string{"a", "b", "c"} {
+		app.keys = append(app.keys, key)
+		app.keyList.AddItem(key, "", 0, nil)
+	}
+
+	if got := app.markedOrCurrent(); len(got) != 1 || got[0] != "a" {
+		t.Errorf("markedOrCurrent() with no marks = %q, want [a]", got)
+	}
+
+	app.toggleMark(2)
+	app.toggleMark(0)
+	if got := app.markedOrCurrent(); len(got) != 2 || got[0] != "a" || got[1] != "c" {
+		t.Errorf("markedOrCurrent() = %q, want [a c]", got)
+	}
+	if main, _ := app.keyList.GetItemText(0); main != "* a" {
+		t.Errorf("Marked item text = %q, want %q", main, "* a")
+	}
+	if app.keyList.GetCurrentItem() != 1 {
+		t.Errorf("Expected marking to advance to item 1, got %d", app.keyList.GetCurrentItem())
+	}
+
+	app.toggleMark(0)
+	if main, _ := app.keyList.GetItemText(0); main != "a" || len(app.marked) != 1 {
+		t.Errorf("Unmarking left %q, marked %v", main, app.marked)
+	}
+}
+
+func TestShowAnalyzeReport(t *testing.T) {
+	reg, err := command.NewRegistry()
+	if err != nil {
+		t.Fatalf("Failed to create registry: %v", err)
+	}
+	app := newApp(nil, reg, Options{})
+
+	app.showAnalyzeReport(keyspace.Report{
+		Pattern:  "*",
+		Analyzed: 3,
+		Types: []keyspace.TypeStats{
+			{Type: "hash", Keys: 2, Memory: 3000, Top: []keyspace.KeyStat{
+				{Key: "h:[1]", Type: "hash", Memory: 2000, Length: 10},
+				{Key: "h:2", Type: "hash", Memory: 1000, Length: 5},
+			}},
+			{Type: "string", Keys: 1, Memory: 100, Top: []keyspace.KeyStat{{Key: "s", Type: "string", Memory: 100}}},
+		},
+	})
+
+	if app.activeContent != app.reportTable {
+		t.Fatal("Expected the report view to be active")
+	}
+	if got := app.reportTable.GetRowCount(); got != 6 { // header + 2 sections + 3 keys
+		t.Errorf("Row count = %d, want 6", got)
+	}
+	if app.reportRows[0].selected != nil || !app.reportRows[0].section {
+		t.Error("Section rows should not be selectable")
+	}
+	if app.reportRows[1].selected == nil {
+		t.Error("Key rows should open the key")
+	}
+	if got := app.reportTable.GetCell(2, 0).Text; got != "  h:[1[]" {
+		t.Errorf("Key cell = %q, want the escaped key name", got)
+	}
+}
+
+func TestShowPrefixLevel(t *testing.T) {
+	// Filtering reloads the key list after a debounce, so this needs a server.
+	app := newTestApp(t, redistest.NewServer(t))
+
+	user := &keyspace.PrefixStats{Prefix: "svc:user:", Keys: 2, Memory: 200}
+	svc := &keyspace.PrefixStats{Prefix: "svc:", Keys: 3, Memory: 300, Children: []*keyspace.PrefixStats{user}}
+	report := keyspace.PrefixReport{
+		Total:    keyspace.PrefixStats{Prefix: "*", Keys: 4, Memory: 305},
+		Prefixes: []*keyspace.PrefixStats{svc, {Prefix: keyspace.NoPrefix, Keys: 1, Memory: 5}},
+	}
+
+	app.showPrefixLevel(report, nil)
+	if got := app.reportTable.GetRowCount(); got != 3 { // header + 2 prefixes
+		t.Fatalf("Row count = %d, want 3", got)
+	}
+	if app.reportRows[1].selected != nil {
+		t.Error("The no-prefix group should not be selectable")
+	}
+
+	app.reportRows[0].selected() // drill into svc:
+	if len(app.reportRows) != 2 || app.reportRows[0].cells[0] != ".." || app.reportRows[1].cells[0] != "svc:user:" {
+		t.Fatalf("Drill-down rows = %+v", app.reportRows)
+	}
+
+	app.reportRows[1].selected() // leaf: filter the key list
+	if got := app.filterInput.GetText(); got != "svc:user:" {
+		t.Errorf("Filter = %q, want svc:user:", got)
+	}
+}
+
+func TestGroupKeys(t *testing.T) {
+	keys := []string{"user:2:profile", "user:1:profile", "user:1:session", "config", "order:9", "user"}
+
+	top := groupKeys(keys, "", ":")
+	want := []keyGroup{
+		{name: "order:", label: "order:", count: 1},
+		{name: "user:", label: "user:", count: 3},
+		{name: "config", label: "config", count: 1, leaf: true},
+		{name: "user", label: "user", count: 1, leaf: true},
+	}
+	if !slices.Equal(top, want) {
+		t.Errorf("groupKeys(top) = %+v, want %+v", top, want)
+	}
+
+	user1 := groupKeys(keys, "user:1:", ":")
+	if len(user1) != 2 || user1[0].label != "profile" || !user1[0].leaf || user1[0].name != "user:1:profile" {
+		t.Errorf("groupKeys(user:1:) = %+v", user1)
+	}
+}
+
+func TestKeyTree(t *testing.T) {
+	reg, err := command.NewRegistry()
+	if err != nil {
+		t.Fatalf("Failed to create registry: %v", err)
+	}
+	app := newApp(nil, reg, Options{KeyView: "tree", KeyDelim: "/"})
+	if !app.treeMode || app.focusOrder[1] != app.keyTree {
+		t.Fatal("KeyView tree should start with the key tree")
+	}
+
+	app.keys = []string{"a/b/1", "a/b/2", "a/c", "top"}
+	app.refreshKeyTree()
+	root := app.keyTree.GetRoot()
+	if got := len(root.GetChildren()); got != 2 { // a/ and top
+		t.Fatalf("Root children = %d, want 2", got)
+	}
+
+	// Children are only built when a group is expanded.
+	a := root.GetChildren()[0]
+	if len(a.GetChildren()) != 0 || a.IsExpanded() {
+		t.Fatal("Groups should start collapsed and empty")
+	}
+	app.expandGroup(a)
+	if got := len(a.GetChildren()); got != 2 { // a/b/ and a/c
+		t.Fatalf("a/ children = %d, want 2", got)
+	}
+
+	// The current node stands for the keys under it.
+	app.keyTree.SetCurrentNode(a)
+	if got := app.markedOrCurrent(); !slices.Equal(got, []string{"a/b/1", "a/b/2", "a/c"}) {
+		t.Errorf("markedOrCurrent() = %q", got)
+	}
+
+	// A refresh keeps expanded groups open.
+	app.keys = append(app.keys, "a/d")
+	app.refreshKeyTree()
+	a = app.keyTree.GetRoot().GetChildren()[0]
+	if !a.IsExpanded() || len(a.GetChildren()) != 3 {
+		t.Errorf("After refresh a/ expanded=%v with %d children, want expanded with 3", a.IsExpanded(), len(a.GetChildren()))
+	}
+	if app.keyTree.GetCurrentNode() != a {
+		t.Error("Refresh should keep the current node")
+	}
+
+	app.toggleKeyView()
+	if app.treeMode || app.focusOrder[1] != app.keyList {
+		t.Error("Toggle should switch back to the key list")
+	}
+}
+
+func TestKeyPaging(t *testing.T) {
+	var names []string
+	for i := range 250 {
+		names = append(names, fmt.Sprintf("key:%03d", i))
+	}
+	s := redistest.NewServer(t)
+	s.Handle("DBSIZE", func(args []string) string { return redistest.Int(int64(len(names))) })
+	s.Handle("SCAN", func(args []string) string { // 30 keys per page
+		start, _ := strconv.Atoi(args[1])
+		end := min(start+30, len(names))
+		next := strconv.Itoa(end)
+		if end == len(names) {
+			next = "0"
+		}
+		return redistest.Array(redistest.Bulk(next), redistest.BulkArray(names[start:end]...))
+	})
+	app := newTestApp(t, s)
+
+	// The first page is at least keyPageSize keys, not the whole keyspace.
+	app.loadKeysSync("*")
+	if len(app.keys) != 120 || app.keyList.GetItemCount() != 120 || app.scan.done {
+		t.Fatalf("After the first page: %d keys, done=%v; want 120, not done", len(app.keys), app.scan.done)
+	}
+	if title := app.leftPane.GetTitle(); !strings.Contains(title, "of ~250") {
+		t.Errorf("Title = %q, want the DBSIZE estimate", title)
+	}
+
+	fetch := func(gen uint64) {
+		keys, next, err := app.scanKeys(gen, app.scan.pattern, app.scan.cursor, keyPageSize)
+		app.addKeys(gen, keys, next, err, true)
+	}
+	// A key waiting to be selected is highlighted when its page arrives.
+	app.keyToSelect = "key:130"
+	fetch(app.scan.gen)
+	if got := app.keyList.GetCurrentItem(); got != 130 || app.keyToSelect != "" {
+		t.Errorf("Current item = %d, want 130 (key:130)", got)
+	}
+	fetch(app.scan.gen)
+	if len(app.keys) != 250 || !app.scan.done {
+		t.Fatalf("After paging: %d keys, done=%v; want 250, done", len(app.keys), app.scan.done)
+	}
+	if title := app.leftPane.GetTitle(); strings.Contains(title, "~") {
+		t.Errorf("Title = %q, want no estimate once done", title)
+	}
+
+	// A superseded scan stops and its pages are dropped.
+	gen := app.scan.gen
+	app.scan.gen = app.scanGen.Add(1)
+	if keys, _, _ := app.scanKeys(gen, "*", "0", keyPageSize); len(keys) != 0 {
+		t.Errorf("Cancelled scan returned %d keys", len(keys))
+	}
+	app.addKeys(gen, []string{"stale"}, "0", nil, true)
+	if len(app.keys) != 250 {
+		t.Error("Pages from an old scan should be dropped")
+	}
+}
+
+// newTestApp returns an app connected to s.
+func newTestApp(t *testing.T, s *redistest.Server) *App {
+	t.Helper()
+	c, err := conn.Connect(s.Host, s.Port, "", "")
+	if err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	reg, err := command.NewRegistry()
+	if err != nil {
+		t.Fatalf("Failed to create registry: %v", err)
+	}
+	return newApp(c, reg, Options{})
+}
+
+func TestCollectionPaging(t *testing.T) {
+	var items []string
+	for i := range 450 {
+		items = append(items, fmt.Sprintf("v%d", i))
+	}
+	s := redistest.NewServer(t)
+	s.Handle("LLEN", func(args []string) string { return redistest.Int(int64(len(items))) })
+	s.Handle("LRANGE", func(args []string) string {
+		start, _ := strconv.Atoi(args[2])
+		stop, _ := strconv.Atoi(args[3])
+		start, stop = min(start, len(items)), min(stop+1, len(items))
+		return redistest.BulkArray(items[start:stop]...)
+	})
+	app := newTestApp(t, s)
+
+	app.showCollection("big", "list", "big (list)", nil)
+	if app.page.loaded != rowPageSize || app.tableView.GetRowCount() != rowPageSize+1 {
+		t.Fatalf("Opened with %d rows, want one page of %d", app.page.loaded, rowPageSize)
+	}
+	if got := app.pageTitle(); got != "big (list) [200 of 450]" {
+		t.Errorf("Title = %q", got)
+	}
+
+	// Selecting near the end of the loaded rows fetches the next page.
+	app.tableView.Select(rowPageSize-5, 0)
+	if app.page.loaded != 400 {
+		t.Errorf("After scrolling: %d rows, want 400", app.page.loaded)
+	}
+	app.loadRows()
+	if app.page.loaded != 450 || !app.page.done {
+		t.Errorf("After the last page: %d rows, done=%v", app.page.loaded, app.page.done)
+	}
+
+	// Jumping restarts the table at a list index; # stays the list position.
+	app.restartPage("440", "", "")
+	if got := app.tableView.GetCell(1, 0).Text; got != "441" {
+		t.Errorf("First row after jump = %q, want 441", got)
+	}
+	if app.page.loaded != 10 {
+		t.Errorf("After jump: %d rows, want 10", app.page.loaded)
+	}
+}
+
+func TestCollectionPageZSetRange(t *testing.T) {
+	s := redistest.NewServer(t)
+	s.Handle("ZRANGEBYSCORE", func(args []string) string {
+		return redistest.BulkArray("a", "1.5", "b", "2")
+	})
+	app := newTestApp(t, s)
+	app.page = &collectionPage{key: "z", typeName: "zset", title: "z (zset)", total: 2}
+
+	app.restartPage("0", "(1", "+inf")
+	cmds := s.Commands()
+	want := []string{"ZRANGEBYSCORE", "z", "(1", "+inf", "WITHSCORES", "LIMIT", "0", "200"}
+	if last := cmds[len(cmds)-1]; !slices.Equal(last, want) {
+		t.Errorf("Sent %q, want %q", last, want)
+	}
+	if app.page.loaded != 2 || app.tableView.GetCell(2, 1).Text != "2" {
+		t.Errorf("Rows = %d, want 2 member/score rows", app.page.loaded)
+	}
+}
+
+func TestNextStreamID(t *testing.T) {
+	for id, want := range map[string]string{"1700000000000-0": "1700000000000-1", "5-41": "5-42", "bad": "bad"} {
+		if got := nextStreamID(id); got != want {
+			t.Errorf("nextStreamID(%q) = %q, want %q", id, got, want)
+		}
+	}
+}
+
+func TestKeyMeta(t *testing.T) {
+	s := redistest.NewServer(t)
+	s.Handle("PTTL", func(args []string) string { return redistest.Int(3599400) })
+	s.Handle("OBJECT", func(args []string) string {
+		switch strings.ToUpper(args[1]) {
+		case "ENCODING":
+			return redistest.Bulk("listpack")
+		case "FREQ":
+			return redistest.Int(7)
+		}
+		return redistest.Err("ERR An LFU maxmemory policy is selected, idle time not tracked.")
+	})
+	s.Handle("MEMORY", func(args []string) string { return redistest.Int(1536) })
+	s.Handle("HLEN", func(args []string) string { return redistest.Int(40) })
+	app := newTestApp(t, s)
+
+	app.currentKey, app.currentType = "h", "hash"
+	app.showMeta()
+	want := " TTL 59m59s | listpack | freq 7 | 1.5 KB | 40 elements"
+	if got := app.metaBar.GetText(false); got != want {
+		t.Errorf("Metadata = %q, want %q", got, want)
+	}
+
+	app.switchContent("output", "Output")
+	if got := app.metaBar.GetText(false); got != "" {
+		t.Errorf("Metadata after leaving the key = %q, want empty", got)
+	}
+
+	if got := (keyMeta{pttl: -1, idle: 90, freq: -1, memory: -1, length: -1}).String(); got != "no TTL | idle 1m30s" {
+		t.Errorf("String() = %q", got)
+	}
+	if got := (keyMeta{pttl: -1, idle: -1, freq: -1, memory: -1, length: 2048, typeName: "string"}).String(); got != "no TTL | 2.0 KB" {
+		t.Errorf("String() of a string = %q", got)
+	}
+}
+
+func TestRunKeyAction(t *testing.T) {
+	s := redistest.NewServer(t)
+	s.Handle("RENAMENX", func(args []string) string { return redistest.Int(1) })
+	s.Handle("MOVE", func(args []string) string { return redistest.Int(1) })
+	s.Handle("TYPE", func(args []string) string { return redistest.Simple("string") })
+	s.Handle("GET", func(args []string) string { return redistest.Bulk("v") })
+	app := newTestApp(t, s)
+
+	app.currentKey, app.currentType = "old", "string"
+	app.runKeyAction(keyspace.KeyAction{Op: keyspace.OpRename, Key: "old", Dest: "new", DB: -1})
+	if app.currentKey != "new" {
+		t.Errorf("After renaming the shown key, currentKey = %q, want new", app.currentKey)
+	}
+
+	app.runKeyAction(keyspace.KeyAction{Op: keyspace.OpMove, Key: "new", DB: 2})
+	if app.currentKey != "" || app.activeContent != app.outputView {
+		t.Errorf("After moving the shown key, currentKey = %q, want it closed", app.currentKey)
+	}
+}
+
+func TestNewKeyCommand(t *testing.T) {
+	b64, err := serializer.Get("base64")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		typeName string
+		elements string
+		codec    serializer.Serializer
+		want     []string
+		wantErr  bool
+	}{
+		{"String", "string", "hello world\n", nil, []string{"SET", "k", "hello world\n"}, false},
+		{"String Codec", "string", "hi", b64, []string{"SET", "k", "aGk="}, false},
+		{"List", "list", "a\n\nb\r\n", nil, []string{"RPUSH", "k", "a", "b"}, false},
+		{"Set", "set", "x", b64, []string{"SADD", "k", "eA=="}, false},
+		{"Hash", "hash", "name Ann Lee\nage 30", nil, []string{"HSET", "k", "name", "Ann Lee", "age", "30"}, false},
+		{"Hash Missing Value", "hash", "name", nil, nil, true},
+		{"ZSet", "zset", "1.5 a\n-inf b", nil, []string{"ZADD", "k", "1.5", "a", "-inf", "b"}, false},
+		{"ZSet Bad Score", "zset", "high a", nil, nil, true},
+		{"Stream", "stream", "event login", b64, []string{"XADD", "k", "*", "event", "login"}, false},
+		{"Empty List", "list", "\n", nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newKeyCommand(tt.typeName, "k", tt.elements, tt.codec)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("newKeyCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateKey(t *testing.T) {
+	s := redistest.NewServer(t)
+	s.Handle("EXISTS", func(args []string) string {
+		if args[1] == "taken" {
+			return redistest.Int(1)
+		}
+		return redistest.Int(0)
+	})
+	s.Handle("RPUSH", func(args []string) string { return redistest.Int(int64(len(args) - 2)) })
+	s.Handle("EXPIRE", func(args []string) string { return redistest.Int(1) })
+	c, err := conn.Connect(s.Host, s.Port, "", "")
+	if err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+
+	if err := createKey(c, "taken", []string{"RPUSH", "taken", "a"}, 0); err == nil {
+		t.Error("Creating an existing key should fail")
+	}
+	if err := createKey(c, "new", []string{"RPUSH", "new", "a", "b"}, 60); err != nil {
+		t.Fatalf("createKey failed: %v", err)
+	}
+	var sent []string
+	for _, cmd := range s.Commands() {
+		sent = append(sent, strings.Join(cmd, " "))
+	}
+	if got := strings.Join(sent, "; "); !strings.HasSuffix(got, "EXISTS taken; EXISTS new; RPUSH new a b; EXPIRE new 60") {
+		t.Errorf("Commands = %s", got)
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	if got := sparkline([]float64{0, 7, 3.5, -1, 7}); got != "▁█▅ █" {
+		t.Errorf("sparkline = %q", got)
+	}
+	if got := sparkline([]float64{5, 5}); got != "▁▁" {
+		t.Errorf("Flat sparkline = %q", got)
+	}
+}
+
+func TestDashboard(t *testing.T) {
+	var d dashboard
+	info := func(ops, hits, misses string) map[string]string {
+		return map[string]string{
+			"redis_version": "7.2.0", "role": "master", "connected_slaves": "1",
+			"instantaneous_ops_per_sec": ops, "used_memory": "2048", "connected_clients": "3",
+			"keyspace_hits": hits, "keyspace_misses": misses,
+			"rdb_last_bgsave_status": "ok", "aof_enabled": "0",
+			"db10": "keys=1,expires=0,avg_ttl=0", "db2": "keys=10,expires=2,avg_ttl=60000",
+		}
+	}
+	d.add(info("10", "90", "10"))
+	d.add(info("20", "120", "20")) // 30 hits in 40 lookups since the first sample
+	rows := d.rows()
+	byLabel := map[string][3]string{}
+	var labels []string
+	for _, row := range rows {
+		byLabel[row[0]] = row
+		labels = append(labels, row[0])
+	}
+	if got := byLabel["Hit ratio"][1]; got != "75% recently, 86% since start" {
+		t.Errorf("Hit ratio = %q", got)
+	}
+	if got := byLabel["Ops/sec"][2]; got != "▁█" {
+		t.Errorf("Ops sparkline = %q", got)
+	}
+	if got := byLabel["Replication"][1]; got != "master, 1 replicas" {
+		t.Errorf("Replication = %q", got)
+	}
+	if got := byLabel["Persistence"][1]; got != "RDB ok; AOF off" {
+		t.Errorf("Persistence = %q", got)
+	}
+	if !slices.Equal(labels[len(labels)-2:], []string{"db2", "db10"}) {
+		t.Errorf("Keyspace rows = %q, want db2 before db10", labels)
+	}
+	if got := byLabel["db2"][1]; got != "10 keys, 2 with expiry, avg TTL 1m0s" {
+		t.Errorf("db2 = %q", got)
+	}
+
+	for range dashboardSamples {
+		d.add(info("20", "120", "20"))
+	}
+	if len(d.samples) != dashboardSamples || d.samples[0].ops != 20 {
+		t.Errorf("Kept %d samples, want the last %d", len(d.samples), dashboardSamples)
+	}
+}
+
+func TestShowDashboard(t *testing.T) {
+	app := newTestApp(t, redistest.NewServer(t)) // answers INFO
+	app.showDashboard(time.Hour)
+	if app.activeContent != app.dashTable {
+		t.Fatal("DASHBOARD should open the dashboard")
+	}
+
+	gen := app.dashGen.Load()
+	info := map[string]string{"redis_version": "7.2.0", "instantaneous_ops_per_sec": "5"}
+	app.addDashboardSample(gen, info, nil)
+	if app.dashTable.GetRowCount() == 0 {
+		t.Fatal("A sample should be drawn")
+	}
+
+	app.switchContent("output", "Output")
+	app.addDashboardSample(gen, info, nil)
+	if len(app.dash.samples) != 1 {
+		t.Error("Leaving the dashboard should stop its refresh")
+	}
+}
+
+func TestShowSlowlog(t *testing.T) {
+	s := redistest.NewServer(t)
+	s.Handle("SLOWLOG", func(args []string) string {
+		return redistest.Array(
+			redistest.Array(redistest.Int(2), redistest.Int(1700000000), redistest.Int(10), redistest.BulkArray("GET", "a")),
+			redistest.Array(redistest.Int(1), redistest.Int(1699999999), redistest.Int(900), redistest.BulkArray("KEYS", "*")),
+		)
+	})
+	app := newTestApp(t, s)
+
+	parsed, _ := command.Parse("SLOWLOG GET 10", app.registry)
+	if !app.handleSlowlog(parsed) || app.activeContent != app.reportTable {
+		t.Fatal("SLOWLOG GET should open the report view")
+	}
+	if got := app.reportRows[0].cells[0]; got != "2" {
+		t.Errorf("First row id = %s, want the newest entry", got)
+	}
+
+	app.reportKeys['s']() // sort by duration
+	if got := app.reportRows[0].cells[0]; got != "1" || !strings.Contains(app.contentPages.GetTitle(), "by duration") {
+		t.Errorf("After s: first id %s, title %q", got, app.contentPages.GetTitle())
+	}
+
+	parsed, _ = command.Parse("SLOWLOG LEN", app.registry)
+	if app.handleSlowlog(parsed) {
+		t.Error("SLOWLOG LEN should run as a standard command")
+	}
+}
+
+func TestShowClients(t *testing.T) {
+	s := redistest.NewServer(t)
+	s.Handle("CLIENT", func(args []string) string {
+		switch strings.ToUpper(args[1]) {
+		case "LIST":
+			return redistest.Bulk("id=5 addr=10.0.0.1:1 name=me age=1 idle=0 db=0 cmd=client|list flags=N\n" +
+				"id=9 addr=10.0.0.2:1 name=worker age=60 idle=30 db=0 cmd=blpop flags=b\n")
+		case "ID":
+			return redistest.Int(5)
+		case "KILL":
+			return redistest.Int(1)
+		}
+		return redistest.Err("ERR unknown subcommand")
+	})
+	app := newTestApp(t, s)
+
+	parsed, _ := command.Parse("CLIENT LIST SORT idle", app.registry)
+	if !app.handleClient(parsed) || app.activeContent != app.reportTable {
+		t.Fatal("CLIENT LIST should open the report view")
+	}
+	if got := app.reportRows[1].cells[2]; got != "me (this connection)" {
+		t.Errorf("Own connection name = %q", got)
+	}
+
+	app.reportTable.Select(2, 0) // this connection
+	app.reportKeys['K']()
+	for _, cmd := range s.Commands() {
+		if len(cmd) > 1 && strings.EqualFold(cmd[1], "KILL") {
+			t.Fatal("K should not kill this connection")
+		}
+	}
+
+	app.reportKeys['s']() // idle -> db
+	app.reportKeys['s']() // db -> cmd
+	if got := app.reportRows[0].cells[6]; got != "blpop" || !strings.Contains(app.contentPages.GetTitle(), "by cmd") {
+		t.Errorf("After s: first cmd %q, title %q", got, app.contentPages.GetTitle())
+	}
+}
+
+func TestShowMonitor(t *testing.T) {
+	s := redistest.NewServer(t)
+	s.Handle("MONITOR", func(args []string) string {
+		return redistest.Simple("OK") +
+			redistest.Simple(`1700000000.5 [0 10.0.0.1:5000] "get" "user:1"`) +
+			redistest.Simple("garbage") +
+			redistest.Simple(`1700000000.6 [0 10.0.0.1:5000] "set" "user:1" "x"`)
+	})
+	app := newTestApp(t, s)
+	path := filepath.Join(t.TempDir(), "monitor.log")
+
+	parsed, _ := command.Parse("MONITOR CMD get > "+path, app.registry)
+	app.handleMonitor(parsed)
+	m := app.monitor
+	if m == nil || app.activeContent != app.monitorView {
+		t.Fatal("MONITOR should open the monitor pane")
+	}
+	for deadline := time.Now().Add(2 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		n := m.queue.len()
+		if n == 3 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Read %d items, want 2 events and an error", n)
+		}
+	}
+	app.flushMonitor(m)
+
+	text := app.monitorView.GetText(true)
+	if !strings.Contains(text, "get user:1") || strings.Contains(text, "set") {
+		t.Errorf("Pane shows %q, want only the GET", text)
+	}
+	if !strings.Contains(text, "garbage") || m.stopped != "" {
+		t.Errorf("A malformed line should be shown without stopping the monitor (stopped %q)", m.stopped)
+	}
+	if m.seen != 2 || m.shown != 1 || !strings.Contains(app.contentPages.GetTitle(), "1 of 2") {
+		t.Errorf("seen %d, shown %d, title %q", m.seen, m.shown, app.contentPages.GetTitle())
+	}
+	if data, _ := os.ReadFile(path); string(data) != "1700000000.5 [0 10.0.0.1:5000] \"get\" \"user:1\"\n" {
+		t.Errorf("Recorded %q", data)
+	}
+
+	app.switchContent("output", "Output")
+	app.closeMonitor(m, "stopped")
+	if m.record != nil || !strings.Contains(monitorTitle(m), "stopped") {
+		t.Error("Leaving the pane should stop the monitor and its recording")
+	}
+}
+
+func TestShowPubSub(t *testing.T) {
+	s := redistest.NewServer(t)
+	s.Handle("PSUBSCRIBE", func(args []string) string {
+		return redistest.Array(redistest.Bulk("psubscribe"), redistest.Bulk(args[1]), redistest.Int(1)) +
+			redistest.BulkArray("pmessage", args[1], "news.eu", "aGk=") +
+			redistest.BulkArray("pmessage", args[1], "news.us", "aGV5")
+	})
+	s.Handle("PUBLISH", func(args []string) string { return redistest.Int(3) })
+	app := newTestApp(t, s)
+
+	parsed, _ := command.Parse("PSUBSCRIBE news.* #:base64", app.registry)
+	app.handleSubscribe(parsed)
+	ps := app.pubsub
+	if ps == nil || app.activeContent != app.pubsubPane {
+		t.Fatal("PSUBSCRIBE should open the Pub/Sub pane")
+	}
+	for deadline := time.Now().Add(2 * time.Second); ps.queue.len() < 3; time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("Read %d pushes, want 3", ps.queue.len())
+		}
+	}
+	app.flushPubSub(ps)
+
+	if !slices.Equal(ps.channels, []string{"news.eu", "news.us"}) || ps.total != 2 {
+		t.Errorf("channels %v, total %d", ps.channels, ps.total)
+	}
+	if text := app.pubsubView.GetText(true); !strings.Contains(text, "news.eu (news.*): hi") || !strings.Contains(text, "hey") {
+		t.Errorf("Pane shows %q, want both messages decoded", text)
+	}
+
+	app.pubsubChannels.Select(2, 0) // news.us
+	if text := app.pubsubView.GetText(true); strings.Contains(text, "news.eu") || !strings.Contains(text, "hey") {
+		t.Errorf("With news.us selected the pane shows %q", text)
+	}
+	app.publishPubSub(ps, "yo")
+	cmds := s.Commands()
+	if last := cmds[len(cmds)-1]; !slices.Equal(last, []string{"PUBLISH", "news.us", "eW8="}) {
+		t.Errorf("Sent %q, want the message encoded for news.us", last)
+	}
+}
+
+func TestWatchKeys(t *testing.T) {
+	value := "v1"
+	s := redistest.NewServer(t)
+	s.Handle("CLIENT", func(args []string) string { return redistest.Bulk("id=7 addr=127.0.0.1:1 db=0 cmd=client|info\n") })
+	s.Handle("CONFIG", func(args []string) string {
+		if args[1] == "SET" {
+			return redistest.Simple("OK")
+		}
+		return redistest.BulkArray("notify-keyspace-events", "")
+	})
+	s.Handle("PSUBSCRIBE", func(args []string) string {
+		return redistest.Array(redistest.Bulk("psubscribe"), redistest.Bulk(args[1]), redistest.Int(1)) +
+			redistest.BulkArray("pmessage", args[1], "__keyspace@0__:user:1", "set") +
+			redistest.BulkArray("pmessage", args[1], "__keyspace@0__:user:2", "del")
+	})
+	s.Handle("TYPE", func(args []string) string { return redistest.Simple("string") })
+	s.Handle("GET", func(args []string) string { return redistest.Bulk(value) })
+	app := newTestApp(t, s)
+
+	parsed, _ := command.Parse("WATCHKEYS user:*", app.registry)
+	app.handleWatchKeys(parsed)
+	w := app.watch
+	if w == nil || app.activeContent != app.watchTable {
+		t.Fatal("WATCHKEYS should open the watch pane")
+	}
+	if !slices.ContainsFunc(s.Commands(), func(cmd []string) bool {
+		return slices.Equal(cmd, []string{"CONFIG", "SET", "notify-keyspace-events", "KA"})
+	}) {
+		t.Error("WATCHKEYS should enable keyspace notifications")
+	}
+	for deadline := time.Now().Add(2 * time.Second); w.queue.len() < 3; time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("Read %d pushes, want 3", w.queue.len())
+		}
+	}
+
+	// The feed keeps running while user:1 is open, and reloads it.
+	app.showKey("user:1")
+	app.app.SetFocus(app.cmdInput)
+	app.focusIndex = 3
+	value = "v2"
+	app.flushWatch(w)
+
+	if w.count != 2 || app.watchTable.GetRowCount() != 3 || app.watchTable.GetCell(2, 3).Text != "del" {
+		t.Errorf("count %d, rows %d", w.count, app.watchTable.GetRowCount())
+	}
+	if got := app.stringView.GetText(true); !strings.Contains(got, "v2") {
+		t.Errorf("Open key shows %q, want it reloaded", got)
+	}
+	if app.app.GetFocus() != app.cmdInput {
+		t.Error("Reloading the open key should leave the focus alone")
+	}
+
+	parsed, _ = command.Parse("WATCHKEYS OFF", app.registry)
+	app.handleWatchKeys(parsed)
+	restored := func(cmd []string) bool {
+		return slices.Equal(cmd, []string{"CONFIG", "SET", "notify-keyspace-events", ""})
+	}
+	for deadline := time.Now().Add(2 * time.Second); !slices.ContainsFunc(s.Commands(), restored); time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("Stopping the watch should restore notify-keyspace-events")
+		}
+	}
+}
@@ -1,11 +1,12 @@
 package tui
 
 import (
-	"iter"
-	"strconv"
+	"fmt"
 	"strings"
 
+	"github.com/cosmez/redisman-go/internal/output"
 	"github.com/cosmez/redisman-go/internal/resp"
+	"github.com/cosmez/redisman-go/internal/serializer"
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
@@ -36,13 +37,53 @@ func (a *App) switchContent(pageName string, title string) {
 		a.activeContent = a.outputView
 		a.currentKey = ""
 		a.currentType = ""
+		a.currentCodec = nil
 	case "string-view":
 		a.activeContent = a.stringView
 	case "table-view":
 		a.activeContent = a.tableView
+	case "json-view":
+		a.activeContent = a.jsonTree
+	case "report-view":
+		a.activeContent = a.reportTable
+		a.currentKey = ""
+		a.currentType = ""
+		a.currentCodec = nil
+	case "dashboard":
+		a.activeContent = a.dashTable
+		a.currentKey = ""
+		a.currentType = ""
+		a.currentCodec = nil
+	case "monitor":
+		a.activeContent = a.monitorView
+		a.currentKey = ""
+		a.currentType = ""
+		a.currentCodec = nil
+	case "pubsub":
+		a.activeContent = a.pubsubPane
+		a.currentKey = ""
+		a.currentType = ""
+		a.currentCodec = nil
+	case "watch":
+		a.activeContent = a.watchTable
+		a.currentKey = ""
+		a.currentType = ""
+		a.currentCodec = nil
+	}
+	if pageName != "dashboard" {
+		a.dashGen.Add(1) // stops the dashboard refresh
+	}
+	if pageName != "monitor" {
+		a.stopMonitor()
+	}
+	if pageName != "pubsub" {
+		a.stopPubSub()
 	}
 	a.focusOrder[2] = a.activeContent
 	a.updateActionBar()
+	if a.currentKey == "" {
+		a.showMeta() // hides the metadata strip
+	}
 }
 
 // focusContent moves focus to the active content view and updates focusIndex
@@ -77,89 +118,6 @@ func (a *App) highlightFocusedPane() {
 	}
 }
 
-// --- Iterator consumers ---
-// These drain iter.Seq iterators into [][]string rows.
-// Must be called while holding connMu (iterators lazily call Send/Receive).
-
-// consumeList drains a list iterator into table rows: [index, value].
-func consumeList(collection iter.Seq[resp.RedisValue]) (headers []string, rows [][]string) {
-	headers = []string{"#", "Value"}
-	i := 0
-	for val := range collection {
-		if _, ok := val.(resp.RedisError); ok {
-			break
-		}
-		i++
-		rows = append(rows, []string{strconv.Itoa(i), val.StringValue()})
-	}
-	return
-}
-
-// consumeSet drains a set iterator into table rows: [member].
-func consumeSet(collection iter.Seq[resp.RedisValue]) (headers []string, rows [][]string) {
-	headers = []string{"Member"}
-	for val := range collection {
-		if _, ok := val.(resp.RedisError); ok {
-			break
-		}
-		rows = append(rows, []string{val.StringValue()})
-	}
-	return
-}
-
-// consumeHash drains a hash iterator into table rows: [field, value].
-// SafeHash yields RedisArray{field, value} pairs.
-func consumeHash(collection iter.Seq[resp.RedisValue]) (headers []string, rows [][]string) {
-	headers = []string{"Field", "Value"}
-	for val := range collection {
-		if _, ok := val.(resp.RedisError); ok {
-			break
-		}
-		if arr, ok := val.(resp.RedisArray); ok && len(arr.Values) >= 2 {
-			rows = append(rows, []string{arr.Values[0].StringValue(), arr.Values[1].StringValue()})
-		}
-	}
-	return
-}
-
-// consumeSortedSet drains a zset iterator into table rows: [member, score].
-// SafeSortedSets yields alternating member, score values (not paired like SafeHash).
-func consumeSortedSet(collection iter.Seq[resp.RedisValue]) (headers []string, rows [][]string) {
-	headers = []string{"Member", "Score"}
-	var pending string
-	hasPending := false
-	for val := range collection {
-		if _, ok := val.(resp.RedisError); ok {
-			break
-		}
-		if !hasPending {
-			pending = val.StringValue()
-			hasPending = true
-		} else {
-			rows = append(rows, []string{pending, val.StringValue()})
-			hasPending = false
-		}
-	}
-	return
-}
-
-// consumeStream drains a stream iterator into table rows: [id, data].
-// SafeStream yields RedisArray{id, RedisArray{field, value, field, value, ...}}.
-func consumeStream(collection iter.Seq[resp.RedisValue]) (headers []string, rows [][]string) {
-	headers = []string{"ID", "Data"}
-	for val := range collection {
-		if _, ok := val.(resp.RedisError); ok {
-			break
-		}
-		if arr, ok := val.(resp.RedisArray); ok && len(arr.Values) >= 2 {
-			id := arr.Values[0].StringValue()
-			data := formatStreamFields(arr.Values[1])
-			rows = append(rows, []string{id, data})
-		}
-	}
-	return
-}
-
 // formatStreamFields formats stream entry fields as "field1=val1, field2=val2, ...".
 func formatStreamFields(v resp.RedisValue) string {
 	arr, ok := v.(resp.RedisArray)
@@ -173,8 +131,43 @@ func formatStreamFields(v resp.RedisValue) string {
 	return strings.Join(parts, ", ")
 }
 
+// valueColumn returns the table column holding stored values for a type,
+// i.e. the column a codec applies to, or -1 if none does.
+func valueColumn(typeName string) int {
+	switch typeName {
+	case "list", "hash":
+		return 1
+	case "set", "zset":
+		return 0
+	}
+	return -1
+}
+
+// decodeRows returns a copy of rows with the value column decoded by ser.
+// Values that fail to decode are shown as stored, matching PrintRedisValue.
+func decodeRows(typeName string, rows [][]string, ser serializer.Serializer) [][]string {
+	col := valueColumn(typeName)
+	if ser == nil || col < 0 {
+		return rows
+	}
+	decoded := make([][]string, len(rows))
+	for i, row := range rows {
+		out := append([]string(nil), row...)
+		if col < len(out) {
+			if d, err := ser.Deserialize([]byte(out[col])); err == nil {
+				out[col] = string(d)
+			}
+		}
+		decoded[i] = out
+	}
+	return decoded
+}
+
 // populateTable clears the shared table and fills it with headers and rows.
-func (a *App) populateTable(headers []string, rows [][]string) {
+// raw holds the stored (undecoded) values parallel to rows; each cell keeps
+// its raw value as its reference so edits can address the stored data.
+// raw may be nil when rows are not decoded.
+func (a *App) populateTable(headers []string, rows [][]string, raw [][]string) {
 	a.tableView.Clear()
 
 	// Header row (fixed, styled).
@@ -186,18 +179,79 @@ func (a *App) populateTable(headers []string, rows [][]string) {
 		a.tableView.SetCell(0, col, cell)
 	}
 
-	// Data rows.
+	a.appendTableRows(rows, raw)
+	a.tableView.ScrollToBeginning()
+}
+
+// appendTableRows adds data rows below the rows already in the table, with
+// raw as in populateTable.
+func (a *App) appendTableRows(rows [][]string, raw [][]string) {
+	first := a.tableView.GetRowCount() // header row included
+	columns := a.tableView.GetColumnCount()
 	for r, row := range rows {
 		for c, val := range row {
 			cell := tview.NewTableCell(val).
 				SetExpansion(1)
+			if raw != nil {
+				cell.SetReference(raw[r][c])
+			}
 			// First column gets a distinct color for visual structure.
-			if c == 0 && len(headers) > 1 {
+			if c == 0 && columns > 1 {
 				cell.SetTextColor(tcell.ColorAqua)
 			}
-			a.tableView.SetCell(r+1, c, cell) // +1 for header row
+			a.tableView.SetCell(first+r, c, cell)
 		}
 	}
+}
 
-	a.tableView.ScrollToBeginning()
+// showStringValue renders a string key's value. JSON objects and arrays open
+// in the collapsible jsonTree (the pretty-printed text stays available in
+// stringView, toggled with 't'); everything else goes to stringView.
+// A json codec (`#:json` or a rule) forces JSON handling and warns when the
+// value does not parse.
+func (a *App) showStringValue(title string, single resp.RedisValue, codec serializer.Serializer) {
+	a.stringView.Clear()
+	stringWriter := tview.ANSIWriter(a.stringView)
+
+	text := single.StringValue()
+	if codec != nil {
+		if decoded, err := codec.Deserialize([]byte(text)); err == nil {
+			text = string(decoded)
+		}
+	}
+
+	isJSON := single.Type() != resp.TypeNull && output.LooksLikeJSON(text)
+	if !isJSON && serializer.IsJSON(codec) {
+		fmt.Fprintf(stringWriter, "[red]Value is not a valid JSON object or array[white]\n")
+	}
+
+	opts := output.PrintOpts{Color: true, Newline: true, Serializer: codec, PrettyJSON: true}
+	output.PrintRedisValue(stringWriter, single, opts)
+	a.stringView.ScrollToBeginning()
+
+	if isJSON {
+		if root, err := buildJSONTree(text); err == nil {
+			a.jsonTree.SetRoot(root).SetCurrentNode(root)
+			a.jsonTitle = title
+			a.switchContent("json-view", title+" | $")
+			return
+		}
+	}
+	a.jsonTree.SetRoot(nil)
+	a.switchContent("string-view", title)
+}
+
+// toggleJSONView flips between the JSON tree and the pretty-printed text of
+// the current string value. It does nothing for non-JSON values.
+func (a *App) toggleJSONView() {
+	if a.jsonTree.GetRoot() == nil {
+		return
+	}
+	if a.activeContent == a.jsonTree {
+		a.switchContent("string-view", a.jsonTitle)
+	} else {
+		a.switchContent("json-view", a.jsonTitle+" | $")
+		a.jsonTree.SetCurrentNode(a.jsonTree.GetRoot())
+	}
+	a.focusContent()
 }
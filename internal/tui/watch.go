@@ -0,0 +1,300 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cosmez/redisman-go/internal/command"
+	"github.com/cosmez/redisman-go/internal/conn"
+	"github.com/cosmez/redisman-go/internal/server"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// The watch pane keeps the last watchRows notifications and is redrawn at
+// most every watchFlush.
+const (
+	watchRows  = 1000
+	watchFlush = 100 * time.Millisecond
+)
+
+// removedEvents are the notifications after which a key no longer exists
+// under its name, so the open key is not reloaded.
+var removedEvents = map[string]bool{
+	"del": true, "expired": true, "evicted": true, "rename_from": true, "move_from": true,
+}
+
+// watchItem is one notification or error read by WATCHKEYS, with the time
+// it arrived.
+type watchItem struct {
+	msg conn.PubSubMessage
+	err error
+	at  time.Time
+}
+
+// watchSession is a running (or finished) WATCHKEYS. Unlike MONITOR and
+// Pub/Sub it keeps running while other pages are shown, so the key being
+// viewed can be reloaded when it changes. The reader goroutine only touches
+// queue.
+type watchSession struct {
+	opts         server.WatchOptions
+	db           int
+	autoRefresh  bool   // reload the open key when it changes
+	warning      string // why notifications may not arrive, if CONFIG SET failed
+	count        int
+	stopped      string // why the stream ended, empty while it runs
+	cancel       context.CancelFunc
+	queue        streamQueue[watchItem]
+	notifyBefore string    // notify-keyspace-events before the watch changed it
+	notifySet    bool      // whether the watch changed notify-keyspace-events
+	restore      sync.Once // sets notify-keyspace-events back when the watch ends
+}
+
+// setupWatch creates the watch page. Enter opens the highlighted key, a
+// toggles auto-refresh, c clears the feed, s stops watching and Escape
+// returns to the output page, leaving the watch running.
+func (a *App) setupWatch() {
+	a.watchTable = tview.NewTable().
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+	a.contentPages.AddPage("watch", a.watchTable, true, false)
+
+	a.watchTable.SetSelectedFunc(func(row, column int) {
+		if row >= 1 {
+			a.showKey(a.watchTable.GetCell(row, 2).GetReference().(string))
+		}
+	})
+	a.watchTable.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			a.switchContent("output", "Output")
+			a.focusKeys()
+			return nil
+		}
+		if event.Key() != tcell.KeyRune || a.watch == nil {
+			return event
+		}
+		switch event.Rune() {
+		case 'a':
+			a.watch.autoRefresh = !a.watch.autoRefresh
+			a.contentPages.SetTitle(contentTitle(watchTitle(a.watch)))
+		case 'c':
+			a.clearWatchTable()
+		case 's':
+			a.stopWatch()
+		default:
+			return event
+		}
+		return nil
+	})
+}
+
+// clearWatchTable empties the feed, leaving its header.
+func (a *App) clearWatchTable() {
+	a.watchTable.Clear()
+	for col, h := range []string{"Time", "DB", "Key", "Event"} {
+		a.watchTable.SetCell(0, col, tview.NewTableCell(h).
+			SetTextColor(tcell.ColorYellow).
+			SetSelectable(false).
+			SetExpansion(1))
+	}
+}
+
+// handleWatchKeys parses WATCHKEYS [pattern] [EVENT names] and opens the
+// watch pane. WATCHKEYS OFF stops a running watch.
+func (a *App) handleWatchKeys(parsed *command.ParsedCommand) {
+	if len(parsed.Args) == 1 && strings.EqualFold(parsed.Args[0], "OFF") {
+		a.stopWatch()
+		a.showStatus("[green]Stopped watching keys")
+		return
+	}
+	if parsed.Pipe != "" || parsed.Filter != "" || parsed.Redirect != "" {
+		fmt.Fprintf(a.ansiWriter, "[red]WATCHKEYS output cannot be piped, filtered or redirected[white]\n")
+		return
+	}
+	opts, err := server.ParseWatchArgs(parsed.Args)
+	if err != nil {
+		fmt.Fprintf(a.ansiWriter, "[red]Usage: WATCHKEYS [pattern] [EVENT name[,name...]] | WATCHKEYS OFF (%v)[white]\n", err)
+		return
+	}
+	a.showWatch(opts)
+}
+
+// showWatch subscribes to keyspace notifications on a second connection,
+// enables them if needed and allowed, and shows them in the watch pane. The
+// open key is reloaded when it changes, until auto-refresh is turned off. The
+// setting is restored when the watch ends (see restoreNotifications).
+func (a *App) showWatch(opts server.WatchOptions) {
+	a.stopWatch()
+	w := &watchSession{opts: opts, autoRefresh: true}
+
+	a.connMu.Lock()
+	wc, err := a.conn.Dial()
+	if err != nil {
+		a.connMu.Unlock()
+		a.showError("Subscribe connection failed: " + err.Error())
+		return
+	}
+
+	// The new watch takes over restoring the setting of the one it replaces,
+	// which must not set it back under it.
+	old, inherited := a.watch, false
+	if old != nil {
+		old.restore.Do(func() { inherited = old.notifySet })
+	}
+	w.db, _ = a.conn.CurrentDB()
+	before, after, notifyErr := server.EnableNotifications(a.conn, opts.Flags())
+	a.connMu.Unlock()
+	w.notifyBefore, w.notifySet = before, notifyErr == nil && after != before
+	if inherited {
+		w.notifyBefore, w.notifySet = old.notifyBefore, true
+	}
+	if notifyErr != nil {
+		w.warning = "notifications may be off"
+		a.showStatus("[yellow]" + notifyErr.Error())
+	} else if after != before {
+		a.showStatus(fmt.Sprintf("[yellow]%s changed from %q to %q", server.NotifyConfig, before, after))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+	a.watch = w
+	a.clearWatchTable()
+	a.switchContent("watch", watchTitle(w))
+	a.focusContent()
+
+	items := func(yield func(watchItem) bool) {
+		consumerStopped := false
+		for m, err := range wc.Listen(ctx, "PSUBSCRIBE", opts.Channels(w.db)...) {
+			if !yield(watchItem{m, err, time.Now()}) {
+				consumerStopped = true
+				break
+			}
+		}
+		wc.Close()
+		// The setting is restored either way, but a failure can only be
+		// reported while the consumer still reads.
+		if err := a.restoreNotifications(w); err != nil && !consumerStopped {
+			yield(watchItem{err: err, at: time.Now()})
+		}
+	}
+	stream(a, items, &w.queue, watchFlush,
+		func() { a.flushWatch(w) },
+		func() {
+			if w.stopped == "" {
+				w.stopped = "stopped"
+			}
+			if a.watch == w && a.activeContent == a.watchTable {
+				a.contentPages.SetTitle(contentTitle(watchTitle(w)))
+			}
+		})
+}
+
+// flushWatch adds the notifications read since the last flush to the feed
+// and reloads the open key if one of them was about it.
+func (a *App) flushWatch(w *watchSession) {
+	items := w.queue.take()
+	if a.watch != w {
+		return
+	}
+	row, _ := a.watchTable.GetSelection()
+	following := row >= a.watchTable.GetRowCount()-1
+
+	var changed *server.KeyEvent
+	for _, it := range items {
+		if it.err != nil {
+			w.stopped = it.err.Error()
+			continue
+		}
+		e, ok := server.ParseKeyEvent(it.msg)
+		if !it.msg.IsMessage() || !ok || !w.opts.Match(e) {
+			continue
+		}
+		w.count++
+		r := a.watchTable.GetRowCount()
+		a.watchTable.SetCell(r, 0, tview.NewTableCell(it.at.Format(server.MonitorTimeFormat)).SetTextColor(tcell.ColorGray))
+		a.watchTable.SetCell(r, 1, tview.NewTableCell(strconv.Itoa(e.DB)))
+		a.watchTable.SetCell(r, 2, tview.NewTableCell(tview.Escape(e.Key)).SetReference(e.Key).SetExpansion(1))
+		a.watchTable.SetCell(r, 3, tview.NewTableCell(e.Event).SetTextColor(tcell.ColorAqua))
+		if e.Key == a.currentKey {
+			changed = &e
+		}
+	}
+	for a.watchTable.GetRowCount() > watchRows+1 {
+		a.watchTable.RemoveRow(1)
+	}
+	if following {
+		a.watchTable.Select(a.watchTable.GetRowCount()-1, 0)
+	}
+	if a.activeContent == a.watchTable {
+		a.contentPages.SetTitle(contentTitle(watchTitle(w)))
+	}
+	if changed != nil && w.autoRefresh {
+		a.refreshWatchedKey(*changed)
+	}
+}
+
+// refreshWatchedKey reloads the open key after e, keeping the focus where it
+// was. A key that is gone is left on screen with a note, and nothing is
+// reloaded while a dialog is open.
+func (a *App) refreshWatchedKey(e server.KeyEvent) {
+	if removedEvents[e.Event] {
+		a.showStatus(fmt.Sprintf("[yellow]%s: %s", e.Key, e.Event))
+		return
+	}
+	if !a.layout.HasFocus() {
+		a.showStatus(fmt.Sprintf("[yellow]%s changed (%s)", e.Key, e.Event))
+		return
+	}
+	focus := a.focusIndex
+	a.showKey(e.Key)
+	a.focusIndex = focus
+	a.app.SetFocus(a.focusOrder[focus])
+	a.highlightFocusedPane()
+	a.showStatus(fmt.Sprintf("[green]Reloaded after %s", e.Event))
+}
+
+// restoreNotifications sets notify-keyspace-events back to what it was before
+// w changed it, once. It uses a connection of its own, so it neither waits for
+// nor disturbs a job holding the main one.
+func (a *App) restoreNotifications(w *watchSession) (err error) {
+	w.restore.Do(func() {
+		if !w.notifySet {
+			return
+		}
+		var rc *conn.Connection
+		if rc, err = a.conn.Dial(); err != nil {
+			err = fmt.Errorf("cannot restore %s: %w", server.NotifyConfig, err)
+			return
+		}
+		defer rc.Close()
+		err = server.RestoreNotifications(rc, w.notifyBefore)
+	})
+	return err
+}
+
+// stopWatch cancels the running WATCHKEYS, if any.
+func (a *App) stopWatch() {
+	if a.watch != nil && a.watch.cancel != nil {
+		a.watch.cancel()
+	}
+}
+
+// watchTitle returns the watch pane's title with what is watched, the event
+// count and whether the open key is reloaded.
+func watchTitle(w *watchSession) string {
+	title := fmt.Sprintf("Watch [%s in db %d, %d events", w.opts, w.db, w.count)
+	if w.autoRefresh {
+		title += ", auto-refresh"
+	}
+	if w.warning != "" {
+		title += ", " + w.warning
+	}
+	if w.stopped != "" {
+		title += ", " + w.stopped
+	}
+	return title + "] Enter open, a auto-refresh, c clear, s stop"
+}